@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -9,10 +10,21 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/mouizahmed/justscribe-backend/internal/ask"
+	"github.com/mouizahmed/justscribe-backend/internal/chapters"
 	"github.com/mouizahmed/justscribe-backend/internal/database"
 	"github.com/mouizahmed/justscribe-backend/internal/handlers"
+	"github.com/mouizahmed/justscribe-backend/internal/jobs"
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
 	"github.com/mouizahmed/justscribe-backend/internal/middleware"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/redact"
 	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/semantic"
+	"github.com/mouizahmed/justscribe-backend/internal/storage"
+	"github.com/mouizahmed/justscribe-backend/internal/summarize"
+	"github.com/mouizahmed/justscribe-backend/internal/translate"
+	"github.com/mouizahmed/justscribe-backend/internal/trash"
 )
 
 func init() {
@@ -35,11 +47,100 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	folderRepo := repository.NewFolderRepository(db)
+	transcriptRepo := repository.NewTranscriptRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	embeddingRepo := repository.NewEmbeddingRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	summaryRepo := repository.NewSummaryRepository(db)
+	askRepo := repository.NewAskRepository(db)
+	redactionRepo := repository.NewRedactionRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	fileRepo := repository.NewFileRepository(db)
+	clipRepo := repository.NewClipRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	keywordRepo := repository.NewKeywordRepository(db)
+	vocabularyRepo := repository.NewVocabularyRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	starRepo := repository.NewStarRepository(db)
+	smartFolderRepo := repository.NewSmartFolderRepository(db)
+
+	// Initialize media storage
+	mediaRoot := os.Getenv("MEDIA_ROOT")
+	if mediaRoot == "" {
+		mediaRoot = "media" // Default directory if not specified
+	}
+	mediaStore := storage.NewLocalStorage(mediaRoot)
+
+	// Initialize the embedder (the local hash embedder needs no external service)
+	var embedder semantic.Embedder = semantic.NewHashEmbedder(semantic.DefaultDimensions)
+	if os.Getenv("EMBEDDINGS_PROVIDER") == "openai" {
+		embedder = semantic.NewOpenAIEmbedder(
+			os.Getenv("EMBEDDINGS_BASE_URL"),
+			os.Getenv("OPENAI_API_KEY"),
+			os.Getenv("EMBEDDINGS_MODEL"),
+			semantic.DefaultDimensions,
+		)
+	}
+
+	// Initialize LLM-backed features (local implementations unless an OpenAI-compatible endpoint is configured)
+	var summarizer summarize.Summarizer = summarize.NewRuleBasedSummarizer()
+	var completer llm.Completer = llm.NewStubCompleter()
+	var translator translate.Translator = translate.NewDictionaryTranslator()
+	var recognizer redact.EntityRecognizer
+	var titler chapters.Titler = chapters.NewKeywordTitler()
+	if os.Getenv("LLM_PROVIDER") == "openai" {
+		llmClient := llm.NewOpenAIClient(os.Getenv("LLM_BASE_URL"), os.Getenv("OPENAI_API_KEY"), os.Getenv("LLM_MODEL"))
+		summarizer = summarize.NewLLMSummarizer(llmClient)
+		completer = llmClient
+		translator = translate.NewLLMTranslator(llmClient)
+		recognizer = redact.NewLLMRecognizer(llmClient)
+		titler = chapters.NewLLMTitler(llmClient)
+	}
+
+	// Initialize redaction (stored redactions need a key to encrypt the originals)
+	var redactionCipher *redact.Cipher
+	if key := os.Getenv("REDACTION_KEY"); key != "" {
+		redactionCipher, err = redact.NewCipher(key)
+		if err != nil {
+			log.Fatalf("Invalid REDACTION_KEY: %v", err)
+		}
+	}
+	redactor := redact.NewRedactor(redactionRepo, recognizer, redactionCipher)
+
+	// Start the background job runner
+	jobRunner := jobs.NewRunner(jobRepo)
+	jobRunner.Register(models.JobTypeSummarize, summarize.JobHandler(summarizer, transcriptRepo, summaryRepo))
+	jobRunner.Register(models.JobTypeTranslate, translate.JobHandler(translator, transcriptRepo))
+	jobRunner.Register(models.JobTypeChapter, chapters.JobHandler(chapters.NewChapterer(titler, chapters.DefaultOptions), transcriptRepo))
+	jobRunner.Register(models.JobTypeEmbed, semantic.JobHandler(semantic.NewIndexer(embedder, embeddingRepo), transcriptRepo))
+	go jobRunner.Run(context.Background())
+
+	// Start the trash purger (items are kept for the owner's plan retention window)
+	go trash.NewPurger(trashRepo, mediaStore).Run(context.Background())
 
 	// Initialize handlers
 	clerkWebhookHandler := handlers.NewClerkWebhookHandler(userRepo)
 	userHandler := handlers.NewUserHandler(userRepo)
 	folderHandler := handlers.NewFolderHandler(folderRepo)
+	transcriptHandler := handlers.NewTranscriptHandler(transcriptRepo, redactor)
+	searchHandler := handlers.NewSearchHandler(searchRepo, folderRepo)
+	semanticSearchHandler := handlers.NewSemanticSearchHandler(searchRepo, embeddingRepo, transcriptRepo, folderRepo, embedder)
+	summaryHandler := handlers.NewSummaryHandler(transcriptRepo, summaryRepo, jobRepo)
+	translationHandler := handlers.NewTranslationHandler(transcriptRepo, jobRepo)
+	chapterHandler := handlers.NewChapterHandler(transcriptRepo, jobRepo)
+	redactionHandler := handlers.NewRedactionHandler(transcriptRepo, redactionRepo, redactor)
+	commentHandler := handlers.NewCommentHandler(transcriptRepo, commentRepo)
+	clipHandler := handlers.NewClipHandler(clipRepo, fileRepo, transcriptRepo, mediaStore)
+	reviewHandler := handlers.NewReviewHandler(transcriptRepo, reviewRepo)
+	analyticsHandler := handlers.NewAnalyticsHandler(transcriptRepo, folderRepo)
+	keywordHandler := handlers.NewKeywordHandler(transcriptRepo, keywordRepo)
+	vocabularyHandler := handlers.NewVocabularyHandler(vocabularyRepo, transcriptRepo, fileRepo, userRepo)
+	trashHandler := handlers.NewTrashHandler(trashRepo, userRepo, mediaStore)
+	starHandler := handlers.NewStarHandler(starRepo)
+	fileHandler := handlers.NewFileHandler(fileRepo)
+	smartFolderHandler := handlers.NewSmartFolderHandler(smartFolderRepo, folderRepo)
+	jobHandler := handlers.NewJobHandler(jobRepo)
+	askHandler := handlers.NewAskHandler(transcriptRepo, askRepo, ask.NewAssistant(embedder, embeddingRepo, searchRepo, completer))
 
 	// Initialize the router
 	router := gin.Default()
@@ -48,8 +149,8 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "Connection", "Access-Control-Allow-Origin", "svix-id", "svix-timestamp", "svix-signature"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Cache-Control", "Content-Encoding", "Transfer-Encoding"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "Connection", "Access-Control-Allow-Origin", "svix-id", "svix-timestamp", "svix-signature", "If-Match", "If-None-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Cache-Control", "Content-Encoding", "Transfer-Encoding", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -74,11 +175,133 @@ func main() {
 			// Folder routes
 			authenticated.GET("/folders", folderHandler.GetFolderData)
 			authenticated.GET("/folders/all", folderHandler.GetAllFolders)
+			authenticated.GET("/folders/tree", folderHandler.GetFolderTree)
+			authenticated.GET("/folders/by-path", folderHandler.GetFolderByPath)
 			authenticated.GET("/folders/:id", folderHandler.GetFolderData)
+			authenticated.GET("/folders/:id/tree", folderHandler.GetFolderTree)
 			authenticated.POST("/folders", folderHandler.CreateFolder)
+			authenticated.POST("/folders/by-path", folderHandler.CreateFolderByPath)
 			authenticated.PATCH("/folders/:id", folderHandler.UpdateFolder)
 			authenticated.PATCH("/folders/:id/move", folderHandler.MoveFolder)
+			authenticated.POST("/folders/:id/copy", folderHandler.CopyFolder)
+			authenticated.PATCH("/folders/:id/metadata", folderHandler.UpdateFolderMetadata)
+			authenticated.POST("/folders/:id/pin", folderHandler.PinFolder)
+			authenticated.DELETE("/folders/:id/pin", folderHandler.UnpinFolder)
 			authenticated.DELETE("/folders/:id", folderHandler.DeleteFolder)
+
+			// Star and quick access routes
+			authenticated.GET("/quick-access", starHandler.GetQuickAccess)
+			authenticated.POST("/folders/:id/star", starHandler.StarFolder)
+			authenticated.DELETE("/folders/:id/star", starHandler.UnstarFolder)
+			authenticated.POST("/files/:id/star", starHandler.StarFile)
+			authenticated.DELETE("/files/:id/star", starHandler.UnstarFile)
+
+			// Smart folder routes
+			authenticated.GET("/smart-folders", smartFolderHandler.ListSmartFolders)
+			authenticated.POST("/smart-folders", smartFolderHandler.CreateSmartFolder)
+			authenticated.GET("/smart-folders/:id", smartFolderHandler.GetSmartFolder)
+			authenticated.PATCH("/smart-folders/:id", smartFolderHandler.UpdateSmartFolder)
+			authenticated.DELETE("/smart-folders/:id", smartFolderHandler.DeleteSmartFolder)
+
+			// Trash routes
+			authenticated.GET("/trash", trashHandler.ListTrash)
+			authenticated.DELETE("/trash", trashHandler.EmptyTrash)
+			authenticated.POST("/trash/folders/:id/restore", trashHandler.RestoreFolder)
+			authenticated.DELETE("/trash/folders/:id", trashHandler.DeleteFolder)
+			authenticated.POST("/trash/files/:id/restore", trashHandler.RestoreFile)
+			authenticated.DELETE("/trash/files/:id", trashHandler.DeleteFile)
+
+			// Transcript routes
+			authenticated.GET("/files/:id", fileHandler.GetFile)
+			authenticated.GET("/files/:id/transcript", transcriptHandler.GetTranscript)
+			authenticated.GET("/files/:id/export", transcriptHandler.ExportTranscript)
+			authenticated.GET("/files/:id/speakers", transcriptHandler.GetSpeakers)
+			authenticated.PATCH("/files/:id/speakers/:speakerId", transcriptHandler.UpdateSpeaker)
+			authenticated.POST("/files/:id/speakers/merge", transcriptHandler.MergeSpeakers)
+			authenticated.POST("/files/:id/speakers/:speakerId/split", transcriptHandler.SplitSpeaker)
+			authenticated.PATCH("/files/:id/segments/:segmentId", transcriptHandler.UpdateSegment)
+			authenticated.GET("/files/:id/segments/:segmentId/revisions", transcriptHandler.GetSegmentRevisions)
+
+			// Search routes
+			authenticated.GET("/search", searchHandler.Search)
+			authenticated.GET("/search/semantic", semanticSearchHandler.Search)
+			authenticated.POST("/files/:id/embeddings", semanticSearchHandler.IndexFile)
+
+			// Summary routes
+			authenticated.GET("/files/:id/summary", summaryHandler.GetSummary)
+			authenticated.POST("/files/:id/summary", summaryHandler.CreateSummary)
+
+			// Translation routes
+			authenticated.GET("/files/:id/translations", translationHandler.ListTranslations)
+			authenticated.POST("/files/:id/translations", translationHandler.CreateTranslation)
+
+			// Chapter routes
+			authenticated.GET("/files/:id/chapters", chapterHandler.GetChapters)
+			authenticated.POST("/files/:id/chapters", chapterHandler.CreateChapters)
+
+			// Redaction routes
+			authenticated.GET("/redaction-terms", redactionHandler.ListTerms)
+			authenticated.POST("/redaction-terms", redactionHandler.CreateTerm)
+			authenticated.DELETE("/redaction-terms/:id", redactionHandler.DeleteTerm)
+			authenticated.GET("/files/:id/transcript/redacted", redactionHandler.GetRedactedTranscript)
+			authenticated.GET("/files/:id/redactions", redactionHandler.ListRedactions)
+			authenticated.POST("/files/:id/redactions", redactionHandler.ApplyRedactions)
+			authenticated.POST("/files/:id/redactions/reveal", redactionHandler.RevealRedactions)
+			authenticated.POST("/files/:id/redactions/restore", redactionHandler.RestoreRedactions)
+			authenticated.GET("/files/:id/redactions/audit", redactionHandler.GetAuditLog)
+
+			// Comment routes
+			authenticated.GET("/files/:id/comments", commentHandler.ListComments)
+			authenticated.POST("/files/:id/comments", commentHandler.CreateComment)
+			authenticated.PATCH("/files/:id/comments/:commentId", commentHandler.UpdateComment)
+			authenticated.DELETE("/files/:id/comments/:commentId", commentHandler.DeleteComment)
+			authenticated.POST("/files/:id/comments/:commentId/resolve", commentHandler.ResolveComment)
+			authenticated.POST("/files/:id/comments/:commentId/unresolve", commentHandler.UnresolveComment)
+			authenticated.GET("/mentions", commentHandler.ListMentions)
+
+			// Clip routes
+			authenticated.GET("/files/:id/clips", clipHandler.ListClips)
+			authenticated.POST("/files/:id/clips", clipHandler.CreateClip)
+			authenticated.GET("/clips/:id", clipHandler.GetClip)
+			authenticated.PATCH("/clips/:id", clipHandler.UpdateClip)
+			authenticated.DELETE("/clips/:id", clipHandler.DeleteClip)
+			authenticated.GET("/clips/:id/export", clipHandler.ExportClip)
+			authenticated.GET("/clips/:id/audio", clipHandler.ExportClipAudio)
+
+			// Review routes
+			authenticated.GET("/review/queue", reviewHandler.GetQueue)
+			authenticated.POST("/files/:id/review/accept", reviewHandler.AcceptItem)
+			authenticated.POST("/files/:id/review/correct", reviewHandler.CorrectItem)
+			authenticated.POST("/files/:id/review/complete", reviewHandler.CompleteReview)
+			authenticated.DELETE("/files/:id/review", reviewHandler.ReopenReview)
+
+			// Analytics routes
+			authenticated.GET("/files/:id/analytics", analyticsHandler.GetFileAnalytics)
+			authenticated.GET("/folders/:id/analytics", analyticsHandler.GetFolderAnalytics)
+
+			// Keyword routes
+			authenticated.GET("/keywords", keywordHandler.GetTopKeywords)
+			authenticated.GET("/keywords/files", keywordHandler.GetKeywordFiles)
+			authenticated.GET("/files/:id/keywords", keywordHandler.GetFileKeywords)
+			authenticated.POST("/files/:id/keywords", keywordHandler.ExtractFileKeywords)
+			authenticated.POST("/files/:id/keywords/confirm", keywordHandler.ConfirmKeyword)
+			authenticated.DELETE("/files/:id/keywords/confirm", keywordHandler.UnconfirmKeyword)
+
+			// Vocabulary routes
+			authenticated.GET("/vocabulary", vocabularyHandler.ListTerms)
+			authenticated.POST("/vocabulary", vocabularyHandler.CreateTerm)
+			authenticated.GET("/vocabulary/effective", vocabularyHandler.GetEffectiveTerms)
+			authenticated.PATCH("/vocabulary/:id", vocabularyHandler.UpdateTerm)
+			authenticated.DELETE("/vocabulary/:id", vocabularyHandler.DeleteTerm)
+			authenticated.POST("/files/:id/vocabulary/apply", vocabularyHandler.ApplyVocabulary)
+
+			// Q&A routes
+			authenticated.POST("/files/:id/ask", askHandler.Ask)
+			authenticated.GET("/files/:id/ask/history", askHandler.GetHistory)
+			authenticated.DELETE("/files/:id/ask/history", askHandler.ClearHistory)
+
+			// Job routes
+			authenticated.GET("/jobs/:id", jobHandler.GetJob)
 		}
 	}
 
@@ -0,0 +1,259 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// UnidentifiedSpeakers names the rollup entry that collects speakers nobody has named yet
+const UnidentifiedSpeakers = "Unidentified speakers"
+
+// speakerKey groups segments by speaker, with unattributed segments under ""
+func speakerKey(segment models.Segment) string {
+	if segment.SpeakerID == nil {
+		return ""
+	}
+	return *segment.SpeakerID
+}
+
+// Compute works out talk time, turns, pace, monologues, interruptions and silence for a transcript.
+// A turn is a run of consecutive segments from one speaker; a segment interrupts when it starts
+// while another speaker's segment is still running.
+func Compute(transcript *models.Transcript) models.TranscriptAnalytics {
+	result := models.TranscriptAnalytics{
+		FileID:       transcript.FileID,
+		TranscriptID: transcript.ID,
+		Speakers:     []models.SpeakerAnalytics{},
+	}
+
+	stats := make(map[string]*models.SpeakerAnalytics)
+	var order []string
+	statsFor := func(key string) *models.SpeakerAnalytics {
+		if speaker, ok := stats[key]; ok {
+			return speaker
+		}
+		speaker := &models.SpeakerAnalytics{Name: UnidentifiedSpeakers}
+		stats[key] = speaker
+		order = append(order, key)
+		return speaker
+	}
+	for _, roster := range transcript.Speakers {
+		speaker := statsFor(roster.ID)
+		id := roster.ID
+		speaker.SpeakerID = &id
+		speaker.Name = roster.DisplayName()
+		speaker.Named = roster.Name != nil && *roster.Name != ""
+	}
+
+	segments := make([]models.Segment, len(transcript.Segments))
+	copy(segments, transcript.Segments)
+	sort.SliceStable(segments, func(i, j int) bool {
+		if segments[i].StartMs != segments[j].StartMs {
+			return segments[i].StartMs < segments[j].StartMs
+		}
+		return segments[i].EndMs < segments[j].EndMs
+	})
+
+	var talkTotal int64
+	var turnKey string
+	var turn *models.Monologue
+	closeTurn := func() {
+		if turn == nil {
+			return
+		}
+		speaker := statsFor(turnKey)
+		if speaker.LongestMonologue == nil || turn.DurationMs > speaker.LongestMonologue.DurationMs {
+			speaker.LongestMonologue = turn
+		}
+	}
+
+	var active []models.Segment
+	var speechEnd int64 = -1
+	for _, segment := range segments {
+		if segment.EndMs <= segment.StartMs {
+			continue
+		}
+		key := speakerKey(segment)
+		speaker := statsFor(key)
+
+		duration := segment.EndMs - segment.StartMs
+		speaker.TalkTimeMs += duration
+		talkTotal += duration
+		speaker.Words += wordCount(segment)
+
+		if turn == nil || key != turnKey {
+			closeTurn()
+			turnKey = key
+			turn = &models.Monologue{StartMs: segment.StartMs, EndMs: segment.EndMs}
+			speaker.Turns++
+			result.Turns++
+		} else if segment.EndMs > turn.EndMs {
+			turn.EndMs = segment.EndMs
+		}
+		turn.DurationMs = turn.EndMs - turn.StartMs
+
+		// The interrupted speaker is whoever holds the floor longest among those still talking
+		current := active[:0]
+		interrupted, floorEnd := "", int64(-1)
+		for _, other := range active {
+			if other.EndMs <= segment.StartMs {
+				continue
+			}
+			current = append(current, other)
+			if other.StartMs < segment.StartMs && speakerKey(other) != key && other.EndMs > floorEnd {
+				interrupted, floorEnd = speakerKey(other), other.EndMs
+			}
+		}
+		if floorEnd >= 0 {
+			speaker.InterruptionsMade++
+			statsFor(interrupted).InterruptionsReceived++
+			result.Interruptions++
+		}
+		active = append(current, segment)
+
+		// Merge overlapping segments into one speech timeline
+		switch {
+		case segment.StartMs >= speechEnd:
+			result.SpeechMs += duration
+			speechEnd = segment.EndMs
+		case segment.EndMs > speechEnd:
+			result.SpeechMs += segment.EndMs - speechEnd
+			speechEnd = segment.EndMs
+		}
+		if segment.EndMs > result.DurationMs {
+			result.DurationMs = segment.EndMs
+		}
+	}
+	closeTurn()
+
+	result.OverlapMs = talkTotal - result.SpeechMs
+	result.SilenceMs = result.DurationMs - result.SpeechMs
+	result.SilenceRatio = ratio(result.SilenceMs, result.DurationMs)
+
+	for _, key := range order {
+		speaker := stats[key]
+		if speaker.SpeakerID == nil && speaker.TalkTimeMs == 0 {
+			continue
+		}
+		finish(speaker, talkTotal)
+		result.Speakers = append(result.Speakers, *speaker)
+	}
+	sortSpeakers(result.Speakers)
+
+	return result
+}
+
+// Rollup combines the analytics of a folder's files. Named speakers are merged across files by
+// name; everyone not yet named is counted together as UnidentifiedSpeakers.
+func Rollup(folderID string, fileNames map[string]string, files []models.TranscriptAnalytics) models.FolderAnalytics {
+	result := models.FolderAnalytics{
+		FolderID: folderID,
+		Speakers: []models.SpeakerAnalytics{},
+		Files:    []models.FileAnalytics{},
+	}
+
+	merged := make(map[string]*models.SpeakerAnalytics)
+	var order []string
+	var talkTotal int64
+	for _, file := range files {
+		result.FileCount++
+		result.DurationMs += file.DurationMs
+		result.SpeechMs += file.SpeechMs
+		result.OverlapMs += file.OverlapMs
+		result.SilenceMs += file.SilenceMs
+		result.Turns += file.Turns
+		result.Interruptions += file.Interruptions
+
+		summary := models.FileAnalytics{
+			FileID:        file.FileID,
+			FileName:      fileNames[file.FileID],
+			DurationMs:    file.DurationMs,
+			SilenceRatio:  file.SilenceRatio,
+			Interruptions: file.Interruptions,
+		}
+
+		for _, speaker := range file.Speakers {
+			if speaker.TalkTimeMs > 0 {
+				summary.SpeakerCount++
+			}
+			if speaker.TalkTimeMs > 0 && speaker.TalkShare > summary.DominantShare {
+				summary.DominantSpeaker = speaker.Name
+				summary.DominantShare = speaker.TalkShare
+			}
+
+			key := ""
+			name := UnidentifiedSpeakers
+			if speaker.Named {
+				key = strings.ToLower(speaker.Name)
+				name = speaker.Name
+			}
+			total, ok := merged[key]
+			if !ok {
+				total = &models.SpeakerAnalytics{Name: name, Named: speaker.Named}
+				merged[key] = total
+				order = append(order, key)
+			}
+
+			total.TalkTimeMs += speaker.TalkTimeMs
+			total.Turns += speaker.Turns
+			total.Words += speaker.Words
+			total.InterruptionsMade += speaker.InterruptionsMade
+			total.InterruptionsReceived += speaker.InterruptionsReceived
+			talkTotal += speaker.TalkTimeMs
+			if speaker.LongestMonologue != nil &&
+				(total.LongestMonologue == nil || speaker.LongestMonologue.DurationMs > total.LongestMonologue.DurationMs) {
+				monologue := *speaker.LongestMonologue
+				monologue.FileID = file.FileID
+				total.LongestMonologue = &monologue
+			}
+		}
+
+		result.Files = append(result.Files, summary)
+	}
+
+	result.SilenceRatio = ratio(result.SilenceMs, result.DurationMs)
+	for _, key := range order {
+		speaker := merged[key]
+		if speaker.TalkTimeMs == 0 {
+			continue
+		}
+		finish(speaker, talkTotal)
+		result.Speakers = append(result.Speakers, *speaker)
+	}
+	sortSpeakers(result.Speakers)
+
+	return result
+}
+
+// finish fills in the figures derived from a speaker's totals
+func finish(speaker *models.SpeakerAnalytics, talkTotal int64) {
+	speaker.TalkShare = ratio(speaker.TalkTimeMs, talkTotal)
+	if speaker.TalkTimeMs > 0 {
+		speaker.WordsPerMinute = math.Round(float64(speaker.Words)/(float64(speaker.TalkTimeMs)/60000)*10) / 10
+	}
+}
+
+func sortSpeakers(speakers []models.SpeakerAnalytics) {
+	sort.SliceStable(speakers, func(i, j int) bool {
+		return speakers[i].TalkTimeMs > speakers[j].TalkTimeMs
+	})
+}
+
+// wordCount prefers the word timings and falls back to splitting the text
+func wordCount(segment models.Segment) int {
+	if len(segment.Words) > 0 {
+		return len(segment.Words)
+	}
+	return len(strings.Fields(segment.Text))
+}
+
+// ratio returns part/whole to four decimal places, or 0 when whole is empty
+func ratio(part, whole int64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 10000
+}
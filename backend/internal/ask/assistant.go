@@ -0,0 +1,144 @@
+package ask
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/semantic"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+const (
+	// MaxSources bounds how many transcript passages are put in the prompt
+	MaxSources = 6
+	// HistoryTurns is how many previous messages are replayed to the model
+	HistoryTurns = 10
+
+	minSourceSimilarity = 0.1
+)
+
+const systemPrompt = `You answer questions about a single recording using only its transcript.
+Use only the numbered sources provided with the question. Cite every claim inline with the source number, e.g. [2].
+If the sources do not contain the answer, say that the transcript does not cover it. Be concise.`
+
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// Assistant answers questions about a transcript by retrieving relevant passages
+// and grounding a completion on them
+type Assistant struct {
+	embedder      semantic.Embedder
+	embeddingRepo *repository.EmbeddingRepository
+	searchRepo    *repository.SearchRepository
+	completer     llm.Completer
+}
+
+func NewAssistant(embedder semantic.Embedder, embeddingRepo *repository.EmbeddingRepository, searchRepo *repository.SearchRepository, completer llm.Completer) *Assistant {
+	return &Assistant{
+		embedder:      embedder,
+		embeddingRepo: embeddingRepo,
+		searchRepo:    searchRepo,
+		completer:     completer,
+	}
+}
+
+// Retrieve finds the passages of a file's transcript most relevant to the question,
+// fusing embedding similarity with keyword matches. Sources are numbered from 1.
+func (a *Assistant) Retrieve(ctx context.Context, userID, fileID, question string) ([]models.Citation, error) {
+	scope := repository.SearchScope{FileID: fileID}
+
+	vectors, err := a.embedder.Embed(ctx, []string{question})
+	if err != nil || len(vectors) != 1 {
+		return nil, fmt.Errorf("failed to embed question: %v", err)
+	}
+
+	semanticHits, err := a.embeddingRepo.SearchSimilar(userID, vectors[0], scope, MaxSources*2, minSourceSimilarity)
+	if err != nil {
+		return nil, err
+	}
+
+	var keywordHits []models.SearchHit
+	if keywordQuery := anyTermQuery(question); keywordQuery != "" {
+		keywordHits, _, err = a.searchRepo.SearchSegments(userID, keywordQuery, scope, MaxSources*2, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hits := semantic.FuseHybrid(semanticHits, keywordHits)
+	if len(hits) > MaxSources {
+		hits = hits[:MaxSources]
+	}
+
+	sources := make([]models.Citation, 0, len(hits))
+	for i, hit := range hits {
+		sources = append(sources, models.Citation{
+			Index:      i + 1,
+			SegmentIDs: hit.SegmentIDs,
+			Speaker:    hit.Speaker,
+			Text:       hit.Text,
+			StartMs:    hit.StartMs,
+			EndMs:      hit.EndMs,
+		})
+	}
+	return sources, nil
+}
+
+// BuildPrompt assembles the grounded conversation: instructions, prior turns, then the
+// numbered sources and the new question
+func BuildPrompt(question string, sources []models.Citation, history []models.AskMessage) []llm.Message {
+	messages := []llm.Message{{Role: llm.RoleSystem, Content: systemPrompt}}
+	for _, turn := range history {
+		messages = append(messages, llm.Message{Role: turn.Role, Content: turn.Content})
+	}
+
+	var b strings.Builder
+	b.WriteString("Sources:\n")
+	if len(sources) == 0 {
+		b.WriteString("(no relevant passages found)\n")
+	}
+	for _, source := range sources {
+		fmt.Fprintf(&b, "[%d] (%s) ", source.Index, textutil.FormatClock(source.StartMs))
+		if source.Speaker != "" {
+			b.WriteString(source.Speaker + ": ")
+		}
+		b.WriteString(strings.TrimSpace(source.Text) + "\n")
+	}
+	b.WriteString("\nQuestion: " + strings.TrimSpace(question))
+
+	return append(messages, llm.Message{Role: llm.RoleUser, Content: b.String()})
+}
+
+// Answer streams the model's reply to a prompt
+func (a *Assistant) Answer(ctx context.Context, prompt []llm.Message, onDelta func(string) error) (string, error) {
+	return a.completer.Stream(ctx, prompt, onDelta)
+}
+
+// CitedSources keeps only the sources the answer actually referenced, in source order
+func CitedSources(answer string, sources []models.Citation) []models.Citation {
+	used := make(map[int]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		if index, err := strconv.Atoi(match[1]); err == nil {
+			used[index] = true
+		}
+	}
+
+	cited := []models.Citation{}
+	for _, source := range sources {
+		if used[source.Index] {
+			cited = append(cited, source)
+		}
+	}
+	return cited
+}
+
+// anyTermQuery turns a natural-language question into a websearch query that matches any content word
+func anyTermQuery(question string) string {
+	words := textutil.ContentWords(question)
+	return strings.Join(words, " or ")
+}
@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotWAV is returned for sources that aren't RIFF/WAVE files
+var ErrNotWAV = errors.New("not a WAV file")
+
+const (
+	formatPCM        = 0x0001
+	formatIEEEFloat  = 0x0003
+	formatExtensible = 0xFFFE
+
+	// maxFmtChunkSize is the size of a WAVE_FORMAT_EXTENSIBLE format chunk, the largest one we use
+	maxFmtChunkSize = 40
+)
+
+// WAV is a parsed RIFF/WAVE header; the sample data is read from the source on demand
+type WAV struct {
+	source     io.ReadSeeker
+	fmtChunk   []byte
+	Format     uint16
+	Channels   uint16
+	SampleRate uint32
+	BlockAlign uint16
+	Bits       uint16
+	dataOffset int64
+	dataSize   int64
+}
+
+// ReadWAV parses the header chunks of a WAV file up to the start of its sample data.
+// Only uncompressed PCM and float data can be sliced on sample boundaries.
+func ReadWAV(source io.ReadSeeker) (*WAV, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(source, riff[:]); err != nil {
+		return nil, ErrNotWAV
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	total, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := source.Seek(12, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	wav := &WAV{source: source}
+	offset := int64(12)
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(source, header[:]); err != nil {
+			return nil, fmt.Errorf("WAV file has no data chunk")
+		}
+		id := string(header[0:4])
+		size := int64(binary.LittleEndian.Uint32(header[4:8]))
+		offset += 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("WAV format chunk is too short")
+			}
+			// The size comes from the file, so only the fields we use are read into memory
+			wav.fmtChunk = make([]byte, min(size, maxFmtChunkSize))
+			if _, err := io.ReadFull(source, wav.fmtChunk); err != nil {
+				return nil, fmt.Errorf("failed to read WAV format chunk: %w", err)
+			}
+			if _, err := io.CopyN(io.Discard, source, size-int64(len(wav.fmtChunk))); err != nil {
+				return nil, fmt.Errorf("failed to read WAV format chunk: %w", err)
+			}
+			wav.Format = binary.LittleEndian.Uint16(wav.fmtChunk[0:2])
+			wav.Channels = binary.LittleEndian.Uint16(wav.fmtChunk[2:4])
+			wav.SampleRate = binary.LittleEndian.Uint32(wav.fmtChunk[4:8])
+			wav.BlockAlign = binary.LittleEndian.Uint16(wav.fmtChunk[12:14])
+			wav.Bits = binary.LittleEndian.Uint16(wav.fmtChunk[14:16])
+		case "data":
+			if wav.fmtChunk == nil {
+				return nil, fmt.Errorf("WAV data chunk precedes its format chunk")
+			}
+			if wav.Format != formatPCM && wav.Format != formatIEEEFloat && wav.Format != formatExtensible {
+				return nil, fmt.Errorf("unsupported WAV encoding 0x%04x", wav.Format)
+			}
+			if wav.BlockAlign == 0 || wav.SampleRate == 0 {
+				return nil, fmt.Errorf("invalid WAV format chunk")
+			}
+			// Streamed recordings may leave the size unset; trust the file length instead
+			if size == 0 || offset+size > total {
+				size = total - offset
+			}
+			wav.dataOffset = offset
+			wav.dataSize = size - size%int64(wav.BlockAlign)
+			return wav, nil
+		}
+
+		// Chunks are word aligned
+		skip := size + size%2
+		if id == "fmt " {
+			skip -= size
+		}
+		if _, err := source.Seek(skip, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+		offset += size + size%2
+	}
+}
+
+// DurationMs is the length of the audio
+func (w *WAV) DurationMs() int64 {
+	frames := w.dataSize / int64(w.BlockAlign)
+	return frames * 1000 / int64(w.SampleRate)
+}
+
+// byteOffset converts a time to a byte offset into the data chunk, on a frame boundary
+func (w *WAV) byteOffset(ms int64) int64 {
+	if ms < 0 {
+		ms = 0
+	}
+	frame := ms * int64(w.SampleRate) / 1000
+	offset := frame * int64(w.BlockAlign)
+	if offset > w.dataSize {
+		offset = w.dataSize
+	}
+	return offset
+}
+
+// Slice is a time range of a WAV file, written out as a standalone WAV file
+type Slice struct {
+	wav    *WAV
+	offset int64
+	length int64
+}
+
+// Slice selects the audio between startMs and endMs, clamped to the recording
+func (w *WAV) Slice(startMs, endMs int64) *Slice {
+	start := w.byteOffset(startMs)
+	end := w.byteOffset(endMs)
+	if end < start {
+		end = start
+	}
+	return &Slice{wav: w, offset: start, length: end - start}
+}
+
+// Size is the byte size of the sliced file, header included
+func (s *Slice) Size() int64 {
+	return 12 + 8 + int64(len(s.wav.fmtChunk)) + int64(len(s.wav.fmtChunk))%2 + 8 + s.length + s.length%2
+}
+
+// WriteTo writes the slice as a complete WAV file: the original format chunk and the selected samples
+func (s *Slice) WriteTo(dst io.Writer) (int64, error) {
+	fmtSize := int64(len(s.wav.fmtChunk))
+	fmtPad := fmtSize % 2
+	dataPad := s.length % 2
+
+	header := make([]byte, 0, 12+8+fmtSize+fmtPad+8)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(s.Size()-8))
+	header = append(header, "WAVE"...)
+	header = append(header, "fmt "...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(fmtSize))
+	header = append(header, s.wav.fmtChunk...)
+	if fmtPad == 1 {
+		header = append(header, 0)
+	}
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(s.length))
+
+	written, err := dst.Write(header)
+	total := int64(written)
+	if err != nil {
+		return total, err
+	}
+
+	if _, err := s.wav.source.Seek(s.wav.dataOffset+s.offset, io.SeekStart); err != nil {
+		return total, err
+	}
+	copied, err := io.CopyN(dst, s.wav.source, s.length)
+	total += copied
+	if err != nil {
+		return total, err
+	}
+
+	if dataPad == 1 {
+		n, err := dst.Write([]byte{0})
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"runtime"
+	"testing"
+)
+
+// wavFile builds a RIFF/WAVE file with the given format chunk and sample data
+func wavFile(fmtChunk []byte, declaredFmtSize uint32, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(4+8+len(fmtChunk)+8+len(data)))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, declaredFmtSize)
+	b.Write(fmtChunk)
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+// pcmFormat is a 16-byte PCM format chunk for 8 kHz, 16-bit mono
+func pcmFormat() []byte {
+	chunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(chunk[0:2], formatPCM)
+	binary.LittleEndian.PutUint16(chunk[2:4], 1)
+	binary.LittleEndian.PutUint32(chunk[4:8], 8000)
+	binary.LittleEndian.PutUint32(chunk[8:12], 16000)
+	binary.LittleEndian.PutUint16(chunk[12:14], 2)
+	binary.LittleEndian.PutUint16(chunk[14:16], 16)
+	return chunk
+}
+
+func TestReadWAV(t *testing.T) {
+	wav, err := ReadWAV(bytes.NewReader(wavFile(pcmFormat(), 16, make([]byte, 16000))))
+	if err != nil {
+		t.Fatalf("ReadWAV() error = %v", err)
+	}
+	if wav.SampleRate != 8000 || wav.Channels != 1 || wav.Bits != 16 {
+		t.Errorf("ReadWAV() = %d Hz, %d channels, %d bits, want 8000 Hz, 1 channel, 16 bits", wav.SampleRate, wav.Channels, wav.Bits)
+	}
+	if got := wav.DurationMs(); got != 1000 {
+		t.Errorf("DurationMs() = %d, want 1000", got)
+	}
+}
+
+func TestReadWAVOversizedFormatChunk(t *testing.T) {
+	// A format chunk claiming to be almost 4 GiB must fail without allocating its declared size
+	source := bytes.NewReader(wavFile(pcmFormat(), 0xFFFFFFF0, nil))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := ReadWAV(source)
+	runtime.ReadMemStats(&after)
+
+	if err == nil {
+		t.Error("ReadWAV() accepted a truncated format chunk")
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("ReadWAV() allocated %d bytes", allocated)
+	}
+}
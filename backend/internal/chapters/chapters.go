@@ -0,0 +1,90 @@
+package chapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// segmenterName identifies the boundary algorithm in each chapter's generator
+const segmenterName = "texttiling"
+
+// Chapterer splits transcripts into titled chapters
+type Chapterer struct {
+	titler   Titler
+	fallback *KeywordTitler
+	options  Options
+}
+
+// NewChapterer builds a chapterer; a nil titler titles chapters by keyword
+func NewChapterer(titler Titler, options Options) *Chapterer {
+	fallback := NewKeywordTitler()
+	if titler == nil {
+		titler = fallback
+	}
+	return &Chapterer{
+		titler:   titler,
+		fallback: fallback,
+		options:  options,
+	}
+}
+
+// Chapters segments a transcript by topic and titles each chapter. A transcript with no clear
+// topic shift comes back as a single chapter.
+func (c *Chapterer) Chapters(ctx context.Context, transcript *models.Transcript) ([]models.Chapter, error) {
+	segments := transcript.Segments
+	if len(segments) == 0 {
+		return []models.Chapter{}, nil
+	}
+
+	starts := append([]int{0}, Boundaries(segments, c.options)...)
+	texts := make([]string, len(starts))
+	chapters := make([]models.Chapter, len(starts))
+	for i, start := range starts {
+		end := len(segments)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+
+		parts := make([]string, 0, end-start)
+		endMs := segments[start].EndMs
+		for _, segment := range segments[start:end] {
+			parts = append(parts, strings.TrimSpace(segment.Text))
+			if segment.EndMs > endMs {
+				endMs = segment.EndMs
+			}
+		}
+		texts[i] = strings.Join(parts, " ")
+
+		startSegmentID := segments[start].ID
+		chapters[i] = models.Chapter{
+			TranscriptID:   transcript.ID,
+			Index:          i,
+			StartMs:        segments[start].StartMs,
+			EndMs:          endMs,
+			StartSegmentID: &startSegmentID,
+			Generator:      segmenterName + "+" + c.titler.Name(),
+		}
+	}
+
+	titles, err := c.titler.Title(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to title chapters: %w", err)
+	}
+
+	var fallbackTitles []string
+	for i := range chapters {
+		if i < len(titles) && titles[i] != "" {
+			chapters[i].Title = titles[i]
+			continue
+		}
+		if fallbackTitles == nil {
+			fallbackTitles, _ = c.fallback.Title(ctx, texts)
+		}
+		chapters[i].Title = fallbackTitles[i]
+	}
+
+	return chapters, nil
+}
@@ -0,0 +1,36 @@
+package chapters
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/jobs"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// JobHandler builds the handler for chapter jobs: it chapters the file's transcript and
+// replaces any chapters stored with it
+func JobHandler(chapterer *Chapterer, transcriptRepo *repository.TranscriptRepository) jobs.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		if job.FileID == nil {
+			return fmt.Errorf("chapter job has no file")
+		}
+
+		transcript, err := transcriptRepo.GetTranscriptByFileID(*job.FileID, job.UserID)
+		if err != nil {
+			return err
+		}
+		if transcript == nil {
+			return fmt.Errorf("transcript not found")
+		}
+
+		chapters, err := chapterer.Chapters(ctx, transcript)
+		if err != nil {
+			return err
+		}
+
+		_, err = transcriptRepo.SaveChapters(transcript.ID, chapters)
+		return err
+	}
+}
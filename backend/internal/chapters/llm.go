@@ -0,0 +1,81 @@
+package chapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+)
+
+// maxPromptChars keeps long transcripts within the model's context window
+const maxPromptChars = 60000
+
+const titleInstructions = `You title the chapters of a transcript, such as a lecture or podcast.
+You receive a JSON array with the text of each chapter, in order. Reply with a JSON array of the same length containing a short title (at most six words) for each chapter.
+Titles should name the topic discussed, not describe the speakers. Reply with JSON only.`
+
+// LLMTitler asks a chat model to title chapters
+type LLMTitler struct {
+	client *llm.OpenAIClient
+}
+
+func NewLLMTitler(client *llm.OpenAIClient) *LLMTitler {
+	return &LLMTitler{client: client}
+}
+
+func (t *LLMTitler) Name() string {
+	return "llm:" + t.client.Model()
+}
+
+func (t *LLMTitler) Title(ctx context.Context, chapters []string) ([]string, error) {
+	if len(chapters) == 0 {
+		return []string{}, nil
+	}
+
+	// Give every chapter an equal share of the prompt; the opening of a chapter says most about it
+	budget := maxPromptChars / len(chapters)
+	excerpts := make([]string, len(chapters))
+	for i, text := range chapters {
+		excerpts[i] = truncate(text, budget)
+	}
+
+	encoded, err := json.Marshal(excerpts)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.client.Complete(ctx, []llm.Message{
+		{Role: llm.RoleSystem, Content: titleInstructions},
+		{Role: llm.RoleUser, Content: string(encoded)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var titles []string
+	if err := json.Unmarshal([]byte(llm.ExtractJSON(reply)), &titles); err != nil {
+		return nil, fmt.Errorf("titler returned invalid JSON: %w", err)
+	}
+	if len(titles) != len(chapters) {
+		return nil, fmt.Errorf("titler returned %d titles, expected %d", len(titles), len(chapters))
+	}
+
+	for i := range titles {
+		titles[i] = strings.Trim(strings.TrimSpace(titles[i]), `"`)
+	}
+	return titles, nil
+}
+
+// truncate cuts text to at most maxChars bytes at a word boundary
+func truncate(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	cut := strings.LastIndex(text[:maxChars], " ")
+	if cut <= 0 {
+		cut = maxChars
+	}
+	return text[:cut] + "…"
+}
@@ -0,0 +1,177 @@
+package chapters
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+// Options tunes topic segmentation
+type Options struct {
+	// BlockWords is how many content words on each side of a gap are compared
+	BlockWords int
+	// MinChapterMs keeps chapters from being shorter than this
+	MinChapterMs int64
+	// MaxChapters caps how many chapters a transcript is split into
+	MaxChapters int
+}
+
+// DefaultOptions suits lectures and podcasts: chapters of at least two minutes, at most twenty of them
+var DefaultOptions = Options{
+	BlockWords:   120,
+	MinChapterMs: 2 * 60 * 1000,
+	MaxChapters:  20,
+}
+
+// Boundaries finds where topics shift, TextTiling-style. Every gap between segments is scored by
+// the lexical similarity of the content words on either side; gaps that sit in a deep valley of
+// similarity become boundaries. It returns the indexes of the segments that open a new chapter,
+// in order, never including the first segment.
+func Boundaries(segments []models.Segment, options Options) []int {
+	if len(segments) < 2 || options.MaxChapters < 2 || options.BlockWords <= 0 {
+		return nil
+	}
+	if segments[len(segments)-1].EndMs-segments[0].StartMs < 2*options.MinChapterMs {
+		return nil
+	}
+
+	tokens := make([][]string, len(segments))
+	for i, segment := range segments {
+		tokens[i] = textutil.ContentWords(segment.Text)
+	}
+
+	// similarity[g] compares the blocks before and after the gap preceding segment g+1
+	similarity := make([]float64, len(segments)-1)
+	for g := range similarity {
+		similarity[g] = cosine(block(tokens, g, -1, options.BlockWords), block(tokens, g+1, 1, options.BlockWords))
+	}
+	similarity = smooth(similarity)
+
+	depths := depthScores(similarity)
+	cutoff := liberalCutoff(depths)
+
+	candidates := make([]int, 0, len(depths))
+	for g, depth := range depths {
+		if depth > cutoff && depth > 0 {
+			candidates = append(candidates, g)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return depths[candidates[a]] > depths[candidates[b]]
+	})
+
+	// Take the deepest valleys first, skipping any that would leave a chapter too short
+	starts := []int{}
+	for _, g := range candidates {
+		if len(starts) >= options.MaxChapters-1 {
+			break
+		}
+		start := g + 1
+		if fitsBetween(segments, starts, start, options.MinChapterMs) {
+			starts = append(starts, start)
+			sort.Ints(starts)
+		}
+	}
+
+	return starts
+}
+
+// block counts the content words of segments walking from index in direction dir until limit words are collected
+func block(tokens [][]string, index, dir, limit int) map[string]int {
+	counts := make(map[string]int)
+	collected := 0
+	for i := index; i >= 0 && i < len(tokens) && collected < limit; i += dir {
+		for _, token := range tokens[i] {
+			counts[token]++
+			collected++
+		}
+	}
+	return counts
+}
+
+func cosine(a, b map[string]int) float64 {
+	var dot, normA, normB float64
+	for token, count := range a {
+		normA += float64(count * count)
+		dot += float64(count * b[token])
+	}
+	for _, count := range b {
+		normB += float64(count * count)
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// smooth averages each score with its neighbours to damp one-segment noise
+func smooth(scores []float64) []float64 {
+	smoothed := make([]float64, len(scores))
+	for i := range scores {
+		sum, n := 0.0, 0
+		for j := i - 1; j <= i+1; j++ {
+			if j >= 0 && j < len(scores) {
+				sum += scores[j]
+				n++
+			}
+		}
+		smoothed[i] = sum / float64(n)
+	}
+	return smoothed
+}
+
+// depthScores measures how far similarity climbs on both sides of each gap before it stops rising
+func depthScores(similarity []float64) []float64 {
+	depths := make([]float64, len(similarity))
+	for g, score := range similarity {
+		left := score
+		for i := g - 1; i >= 0 && similarity[i] >= left; i-- {
+			left = similarity[i]
+		}
+		right := score
+		for i := g + 1; i < len(similarity) && similarity[i] >= right; i++ {
+			right = similarity[i]
+		}
+		depths[g] = (left - score) + (right - score)
+	}
+	return depths
+}
+
+// liberalCutoff is TextTiling's boundary threshold: the mean depth less half a standard deviation
+func liberalCutoff(depths []float64) float64 {
+	if len(depths) == 0 {
+		return 0
+	}
+	var mean float64
+	for _, depth := range depths {
+		mean += depth
+	}
+	mean /= float64(len(depths))
+
+	var variance float64
+	for _, depth := range depths {
+		variance += (depth - mean) * (depth - mean)
+	}
+	return mean - math.Sqrt(variance/float64(len(depths)))/2
+}
+
+// fitsBetween reports whether a chapter starting at segment start keeps every chapter at least minMs long
+func fitsBetween(segments []models.Segment, starts []int, start int, minMs int64) bool {
+	previous := segments[0].StartMs
+	next := segments[len(segments)-1].EndMs
+	for _, existing := range starts {
+		if existing == start {
+			return false
+		}
+		if existing < start && segments[existing].StartMs > previous {
+			previous = segments[existing].StartMs
+		}
+		if existing > start && segments[existing].StartMs < next {
+			next = segments[existing].StartMs
+		}
+	}
+	at := segments[start].StartMs
+	return at-previous >= minMs && next-at >= minMs
+}
@@ -0,0 +1,95 @@
+package chapters
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+const keywordsPerTitle = 3
+
+// Titler names chapters. It receives each chapter's text in order and returns one title per
+// chapter; an empty title is replaced with a keyword title.
+type Titler interface {
+	Name() string
+	Title(ctx context.Context, chapters []string) ([]string, error)
+}
+
+// KeywordTitler is an offline titler that names each chapter after the words that set it apart
+// from the others (TF-IDF over the transcript's chapters)
+type KeywordTitler struct{}
+
+func NewKeywordTitler() *KeywordTitler {
+	return &KeywordTitler{}
+}
+
+func (t *KeywordTitler) Name() string {
+	return "keywords"
+}
+
+func (t *KeywordTitler) Title(ctx context.Context, chapters []string) ([]string, error) {
+	counts := make([]map[string]int, len(chapters))
+	documentFrequency := make(map[string]int)
+	for i, text := range chapters {
+		counts[i] = make(map[string]int)
+		for _, word := range textutil.ContentWords(text) {
+			if counts[i][word] == 0 {
+				documentFrequency[word]++
+			}
+			counts[i][word]++
+		}
+	}
+
+	titles := make([]string, len(chapters))
+	for i := range chapters {
+		type scored struct {
+			word  string
+			score float64
+		}
+		var candidates []scored
+		for word, count := range counts[i] {
+			idf := math.Log(float64(len(chapters))/float64(documentFrequency[word])) + 1
+			candidates = append(candidates, scored{word: word, score: float64(count) * idf})
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			if candidates[a].score != candidates[b].score {
+				return candidates[a].score > candidates[b].score
+			}
+			return candidates[a].word < candidates[b].word
+		})
+
+		var keywords []string
+		for _, candidate := range candidates {
+			if len(keywords) == keywordsPerTitle {
+				break
+			}
+			keywords = append(keywords, capitalize(candidate.word))
+		}
+		titles[i] = joinKeywords(keywords, i)
+	}
+
+	return titles, nil
+}
+
+// joinKeywords renders "Budget, Hiring and Roadmap", or "Chapter n" when there is nothing to go on
+func joinKeywords(keywords []string, index int) string {
+	switch len(keywords) {
+	case 0:
+		return fmt.Sprintf("Chapter %d", index+1)
+	case 1:
+		return keywords[0]
+	default:
+		return strings.Join(keywords[:len(keywords)-1], ", ") + " and " + keywords[len(keywords)-1]
+	}
+}
+
+func capitalize(word string) string {
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
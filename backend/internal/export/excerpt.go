@@ -0,0 +1,56 @@
+package export
+
+import "github.com/mouizahmed/justscribe-backend/internal/models"
+
+// Excerpt returns the part of a transcript between startMs and endMs, re-based so the excerpt
+// starts at zero. Segments and chapters straddling the range are kept and their timings clamped to it.
+func Excerpt(transcript *models.Transcript, startMs, endMs int64) *models.Transcript {
+	excerpt := *transcript
+	excerpt.Segments = []models.Segment{}
+
+	for _, segment := range transcript.Segments {
+		if segment.EndMs <= startMs || segment.StartMs >= endMs {
+			continue
+		}
+
+		segment.StartMs = clamp(segment.StartMs, startMs, endMs) - startMs
+		segment.EndMs = clamp(segment.EndMs, startMs, endMs) - startMs
+
+		if len(segment.Words) > 0 {
+			words := make([]models.Word, 0, len(segment.Words))
+			for _, word := range segment.Words {
+				if word.EndMs <= startMs || word.StartMs >= endMs {
+					continue
+				}
+				word.StartMs = clamp(word.StartMs, startMs, endMs) - startMs
+				word.EndMs = clamp(word.EndMs, startMs, endMs) - startMs
+				words = append(words, word)
+			}
+			segment.Words = words
+		}
+
+		excerpt.Segments = append(excerpt.Segments, segment)
+	}
+
+	excerpt.Chapters = []models.Chapter{}
+	for _, chapter := range transcript.Chapters {
+		if chapter.EndMs <= startMs || chapter.StartMs >= endMs {
+			continue
+		}
+		chapter.StartMs = clamp(chapter.StartMs, startMs, endMs) - startMs
+		chapter.EndMs = clamp(chapter.EndMs, startMs, endMs) - startMs
+		excerpt.Chapters = append(excerpt.Chapters, chapter)
+	}
+
+	return &excerpt
+}
+
+func clamp(value, min, max int64) int64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
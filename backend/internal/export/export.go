@@ -0,0 +1,269 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+type Format string
+
+const (
+	FormatTXT  Format = "txt"
+	FormatSRT  Format = "srt"
+	FormatVTT  Format = "vtt"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates a requested export format, defaulting to plain text
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case "", FormatTXT:
+		return FormatTXT, nil
+	case FormatSRT:
+		return FormatSRT, nil
+	case FormatVTT:
+		return FormatVTT, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", value)
+	}
+}
+
+// ContentType returns the MIME type served for the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatSRT:
+		return "application/x-subrip; charset=utf-8"
+	case FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Write renders a transcript in the given format. Speaker names come from the
+// transcript's roster, so callers must resolve them before exporting.
+func Write(w io.Writer, transcript *models.Transcript, format Format) error {
+	if format == FormatJSON {
+		return writeJSON(w, transcript, nil)
+	}
+	return writeCues(w, transcriptCues(transcript, nil), format)
+}
+
+// WriteBilingual renders a transcript with its translation as dual-line cues: the original
+// line first, the translated line beneath it. Translated segments are matched to the
+// original by SourceSegmentID, so untranslated segments simply get a single line.
+func WriteBilingual(w io.Writer, original, translation *models.Transcript, format Format) error {
+	translated := make(map[string]string, len(translation.Segments))
+	for _, segment := range translation.Segments {
+		if segment.SourceSegmentID != nil {
+			translated[*segment.SourceSegmentID] = segment.Text
+		}
+	}
+
+	if format == FormatJSON {
+		return writeJSON(w, original, &bilingualJSON{language: translation.Language, texts: translated})
+	}
+	return writeCues(w, transcriptCues(original, translated), format)
+}
+
+// WriteChapters renders a transcript's chapters as a WebVTT chapters track
+func WriteChapters(w io.Writer, transcript *models.Transcript) error {
+	if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+	for i, chapter := range transcript.Chapters {
+		_, err := fmt.Fprintf(w, "Chapter %d\n%s --> %s\n%s\n\n",
+			i+1,
+			formatTimestamp(chapter.StartMs, "."),
+			formatTimestamp(chapter.EndMs, "."),
+			chapter.Title,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cue is one timed caption; Lines holds the text and, for bilingual output, its translation
+type cue struct {
+	StartMs int64
+	EndMs   int64
+	Speaker string
+	Lines   []string
+}
+
+func transcriptCues(transcript *models.Transcript, translated map[string]string) []cue {
+	cues := make([]cue, 0, len(transcript.Segments))
+	for _, segment := range transcript.Segments {
+		lines := []string{segment.Text}
+		if text, ok := translated[segment.ID]; ok && text != "" {
+			lines = append(lines, text)
+		}
+		cues = append(cues, cue{
+			StartMs: segment.StartMs,
+			EndMs:   segment.EndMs,
+			Speaker: segment.SpeakerName,
+			Lines:   lines,
+		})
+	}
+	return cues
+}
+
+func writeCues(w io.Writer, cues []cue, format Format) error {
+	switch format {
+	case FormatTXT:
+		return writeTXT(w, cues)
+	case FormatSRT:
+		return writeSRT(w, cues)
+	case FormatVTT:
+		return writeVTT(w, cues)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func writeTXT(w io.Writer, cues []cue) error {
+	for _, c := range cues {
+		line := c.Lines[0]
+		if c.Speaker != "" {
+			line = c.Speaker + ": " + line
+		}
+		if _, err := fmt.Fprintf(w, "[%s] %s\n", textutil.FormatClock(c.StartMs), line); err != nil {
+			return err
+		}
+		for _, extra := range c.Lines[1:] {
+			if _, err := fmt.Fprintf(w, "%s\n", extra); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeSRT(w io.Writer, cues []cue) error {
+	for i, c := range cues {
+		_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n",
+			i+1,
+			formatTimestamp(c.StartMs, ","),
+			formatTimestamp(c.EndMs, ","),
+			cueText(c, false),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeVTT(w io.Writer, cues []cue) error {
+	if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+	for _, c := range cues {
+		_, err := fmt.Fprintf(w, "%s --> %s\n%s\n\n",
+			formatTimestamp(c.StartMs, "."),
+			formatTimestamp(c.EndMs, "."),
+			cueText(c, true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonExport struct {
+	ID                  string           `json:"id"`
+	FileID              string           `json:"file_id"`
+	Language            *string          `json:"language,omitempty"`
+	TranslationLanguage *string          `json:"translation_language,omitempty"`
+	Speakers            []models.Speaker `json:"speakers"`
+	Chapters            []models.Chapter `json:"chapters,omitempty"`
+	Segments            []jsonSegment    `json:"segments"`
+}
+
+type jsonSegment struct {
+	ID          string   `json:"id"`
+	Speaker     string   `json:"speaker,omitempty"`
+	Text        string   `json:"text"`
+	Translation *string  `json:"translation,omitempty"`
+	StartMs     int64    `json:"start_ms"`
+	EndMs       int64    `json:"end_ms"`
+	Confidence  *float64 `json:"confidence,omitempty"`
+}
+
+// bilingualJSON carries the translated text keyed by original segment ID
+type bilingualJSON struct {
+	language *string
+	texts    map[string]string
+}
+
+func writeJSON(w io.Writer, transcript *models.Transcript, bilingual *bilingualJSON) error {
+	out := jsonExport{
+		ID:       transcript.ID,
+		FileID:   transcript.FileID,
+		Language: transcript.Language,
+		Speakers: transcript.Speakers,
+		Chapters: transcript.Chapters,
+		Segments: make([]jsonSegment, 0, len(transcript.Segments)),
+	}
+	if bilingual != nil {
+		out.TranslationLanguage = bilingual.language
+	}
+
+	for _, segment := range transcript.Segments {
+		exported := jsonSegment{
+			ID:         segment.ID,
+			Speaker:    segment.SpeakerName,
+			Text:       segment.Text,
+			StartMs:    segment.StartMs,
+			EndMs:      segment.EndMs,
+			Confidence: segment.Confidence,
+		}
+		if bilingual != nil {
+			if text, ok := bilingual.texts[segment.ID]; ok {
+				exported.Translation = &text
+			}
+		}
+		out.Segments = append(out.Segments, exported)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// cueText prefixes the speaker name to the first line; WebVTT uses a voice span so players can style it
+func cueText(c cue, vtt bool) string {
+	first := c.Lines[0]
+	if c.Speaker != "" {
+		if vtt {
+			first = fmt.Sprintf("<v %s>%s", c.Speaker, first)
+		} else {
+			first = c.Speaker + ": " + first
+		}
+	}
+	return strings.Join(append([]string{first}, c.Lines[1:]...), "\n")
+}
+
+// formatTimestamp renders milliseconds as HH:MM:SS<sep>mmm for subtitle cues
+func formatTimestamp(ms int64, sep string) string {
+	if ms < 0 {
+		ms = 0
+	}
+	hours := ms / 3600000
+	minutes := (ms % 3600000) / 60000
+	seconds := (ms % 60000) / 1000
+	millis := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, sep, millis)
+}
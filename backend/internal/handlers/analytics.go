@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/analytics"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type AnalyticsHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	folderRepo     *repository.FolderRepository
+}
+
+func NewAnalyticsHandler(transcriptRepo *repository.TranscriptRepository, folderRepo *repository.FolderRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		transcriptRepo: transcriptRepo,
+		folderRepo:     folderRepo,
+	}
+}
+
+// GetFileAnalytics returns talk-time and conversation analytics for a file's transcript
+func (h *AnalyticsHandler) GetFileAnalytics(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics.Compute(transcript))
+}
+
+// GetFolderAnalytics rolls up the analytics of every transcript in a folder and its subfolders
+func (h *AnalyticsHandler) GetFolderAnalytics(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	folderID := c.Param("id")
+	folder, err := h.folderRepo.GetFolderByID(folderID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve folder. Please try again later.",
+		})
+		return
+	}
+	if folder == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Folder not found",
+			"message": "The requested folder does not exist or you don't have access to it.",
+		})
+		return
+	}
+
+	transcripts, fileNames, err := h.transcriptRepo.ListFolderTranscripts(folderID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve the folder's transcripts. Please try again later.",
+		})
+		return
+	}
+
+	files := make([]models.TranscriptAnalytics, len(transcripts))
+	for i := range transcripts {
+		files[i] = analytics.Compute(&transcripts[i])
+	}
+
+	c.JSON(http.StatusOK, analytics.Rollup(folderID, fileNames, files))
+}
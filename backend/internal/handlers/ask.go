@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/ask"
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const maxQuestionLength = 2000
+
+type AskHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	askRepo        *repository.AskRepository
+	assistant      *ask.Assistant
+}
+
+func NewAskHandler(transcriptRepo *repository.TranscriptRepository, askRepo *repository.AskRepository, assistant *ask.Assistant) *AskHandler {
+	return &AskHandler{
+		transcriptRepo: transcriptRepo,
+		askRepo:        askRepo,
+		assistant:      assistant,
+	}
+}
+
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+}
+
+// Ask answers a question about a file's transcript, streaming the reply over SSE.
+// Events: "sources" (retrieved passages), "delta" (answer text), "done" (saved message
+// with the citations it used) and "error".
+func (h *AskHandler) Ask(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Question) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include a non-empty 'question'.",
+		})
+		return
+	}
+	question := strings.TrimSpace(req.Question)
+	if len(question) > maxQuestionLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Question must be less than 2000 characters.",
+		})
+		return
+	}
+
+	fileID := c.Param("id")
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(fileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	history, err := h.askRepo.GetHistory(fileID, userID, ask.HistoryTurns)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to load conversation history. Please try again later.",
+		})
+		return
+	}
+
+	sources, err := h.assistant.Retrieve(c.Request.Context(), userID, fileID, question)
+	if err != nil {
+		log.Printf("Error retrieving sources for file %s: %v", fileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Retrieval failed",
+			"message": "Unable to search the transcript. Please try again later.",
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.SSEvent("sources", gin.H{"sources": sources})
+	c.Writer.Flush()
+
+	prompt := ask.BuildPrompt(question, sources, history)
+	answer, err := h.assistant.Answer(c.Request.Context(), prompt, func(delta string) error {
+		c.SSEvent("delta", gin.H{"text": delta})
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	})
+	if err != nil {
+		log.Printf("Error answering question for file %s: %v", fileID, err)
+		c.SSEvent("error", gin.H{
+			"error":   "Answer failed",
+			"message": "Unable to answer the question. Please try again later.",
+		})
+		c.Writer.Flush()
+		return
+	}
+
+	// The question is saved only with its answer, so a failed or cancelled request leaves no
+	// unanswered turn in the history
+	message, err := h.askRepo.AddExchange(&models.AskMessage{
+		FileID:  fileID,
+		UserID:  userID,
+		Role:    llm.RoleUser,
+		Content: question,
+	}, &models.AskMessage{
+		FileID:    fileID,
+		UserID:    userID,
+		Role:      llm.RoleAssistant,
+		Content:   answer,
+		Citations: ask.CitedSources(answer, sources),
+	})
+	if err != nil {
+		c.SSEvent("error", gin.H{
+			"error":   "Database error",
+			"message": "The answer could not be saved to the conversation history.",
+		})
+		c.Writer.Flush()
+		return
+	}
+
+	c.SSEvent("done", message)
+	c.Writer.Flush()
+}
+
+// GetHistory returns a file's conversation
+func (h *AskHandler) GetHistory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := parsePagination(c, 100, 500)
+	messages, err := h.askRepo.GetHistory(c.Param("id"), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to load conversation history. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+	})
+}
+
+// ClearHistory deletes a file's conversation
+func (h *AskHandler) ClearHistory(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.askRepo.ClearHistory(c.Param("id"), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to clear conversation history. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation cleared successfully",
+	})
+}
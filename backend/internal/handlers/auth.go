@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/middleware"
+)
+
+// currentUserID reads the authenticated user ID, writing the error response itself when it is missing
+func currentUserID(c *gin.Context) (string, bool) {
+	userID, ok := middleware.GetUserIDFromContext(c)
+	if !ok || userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Authentication required",
+			"message": "User authentication context not found. Please sign in again.",
+		})
+		return "", false
+	}
+	return userID, true
+}
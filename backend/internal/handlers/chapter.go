@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type ChapterHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	jobRepo        *repository.JobRepository
+}
+
+func NewChapterHandler(transcriptRepo *repository.TranscriptRepository, jobRepo *repository.JobRepository) *ChapterHandler {
+	return &ChapterHandler{
+		transcriptRepo: transcriptRepo,
+		jobRepo:        jobRepo,
+	}
+}
+
+// loadTranscript fetches the :id file's original transcript, writing the error response itself on failure
+func (h *ChapterHandler) loadTranscript(c *gin.Context, userID string) (*models.Transcript, bool) {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// GetChapters returns the chapters stored with a file's transcript
+func (h *ChapterHandler) GetChapters(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+	if len(transcript.Chapters) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Chapters not found",
+			"message": "This transcript has not been chaptered yet.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chapters": transcript.Chapters,
+	})
+}
+
+// CreateChapters queues a chapter job for a file's transcript
+func (h *ChapterHandler) CreateChapters(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.loadTranscript(c, userID); !ok {
+		return
+	}
+
+	// Reuse an in-flight job instead of chaptering the same transcript twice
+	fileID := c.Param("id")
+	job, err := h.jobRepo.GetActiveJob(fileID, userID, models.JobTypeChapter)
+	if err == nil && job == nil {
+		job, err = h.jobRepo.CreateJob(userID, &fileID, models.JobTypeChapter, nil)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to queue chaptering. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/audio"
+	"github.com/mouizahmed/justscribe-backend/internal/export"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/storage"
+)
+
+type ClipHandler struct {
+	clipRepo       *repository.ClipRepository
+	fileRepo       *repository.FileRepository
+	transcriptRepo *repository.TranscriptRepository
+	store          storage.Storage
+}
+
+func NewClipHandler(clipRepo *repository.ClipRepository, fileRepo *repository.FileRepository, transcriptRepo *repository.TranscriptRepository, store storage.Storage) *ClipHandler {
+	return &ClipHandler{
+		clipRepo:       clipRepo,
+		fileRepo:       fileRepo,
+		transcriptRepo: transcriptRepo,
+		store:          store,
+	}
+}
+
+type CreateClipRequest struct {
+	Title   string  `json:"title" binding:"required"`
+	Note    *string `json:"note"`
+	StartMs *int64  `json:"start_ms" binding:"required"`
+	EndMs   *int64  `json:"end_ms" binding:"required"`
+}
+
+type UpdateClipRequest struct {
+	Title   *string `json:"title"`
+	Note    *string `json:"note"`
+	StartMs *int64  `json:"start_ms"`
+	EndMs   *int64  `json:"end_ms"`
+}
+
+// validateClipTitle trims a clip title, writing the error response itself when it is unusable
+func validateClipTitle(c *gin.Context, title string) (string, bool) {
+	title = strings.TrimSpace(title)
+	if title == "" || len(title) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Clip title must be between 1 and 255 characters.",
+		})
+		return "", false
+	}
+	return title, true
+}
+
+// ListClips returns a file's clips in timeline order
+func (h *ClipHandler) ListClips(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	clips, err := h.clipRepo.ListClips(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve clips. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clips": clips,
+	})
+}
+
+// CreateClip cuts a clip from a file's timeline
+func (h *ClipHandler) CreateClip(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateClipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'title', 'start_ms' and 'end_ms'.",
+		})
+		return
+	}
+
+	title, ok := validateClipTitle(c, req.Title)
+	if !ok {
+		return
+	}
+	if *req.StartMs < 0 || *req.EndMs <= *req.StartMs {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "'end_ms' must be after 'start_ms', and both must be non-negative.",
+		})
+		return
+	}
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		req.Note = &trimmed
+		if trimmed == "" {
+			req.Note = nil
+		}
+	}
+
+	clip, err := h.clipRepo.CreateClip(&models.Clip{
+		FileID:  c.Param("id"),
+		UserID:  userID,
+		Title:   title,
+		Note:    req.Note,
+		StartMs: *req.StartMs,
+		EndMs:   *req.EndMs,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "file not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "File not found",
+				"message": "The requested file does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to create clip. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, clip)
+}
+
+// loadClip fetches the :id clip, writing the error response itself on failure
+func (h *ClipHandler) loadClip(c *gin.Context, userID string) (*models.Clip, bool) {
+	clip, err := h.clipRepo.GetClipByID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve clip. Please try again later.",
+		})
+		return nil, false
+	}
+	if clip == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Clip not found",
+			"message": "The requested clip does not exist or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return clip, true
+}
+
+// GetClip returns a single clip
+func (h *ClipHandler) GetClip(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	clip, ok := h.loadClip(c, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, clip)
+}
+
+// UpdateClip changes a clip's title, note or range
+func (h *ClipHandler) UpdateClip(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateClipRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Title == nil && req.Note == nil && req.StartMs == nil && req.EndMs == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'title', 'note', 'start_ms' and/or 'end_ms'.",
+		})
+		return
+	}
+
+	if req.Title != nil {
+		title, ok := validateClipTitle(c, *req.Title)
+		if !ok {
+			return
+		}
+		req.Title = &title
+	}
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		req.Note = &trimmed
+	}
+	if (req.StartMs != nil && *req.StartMs < 0) || (req.EndMs != nil && *req.EndMs <= 0) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "'end_ms' must be after 'start_ms', and both must be non-negative.",
+		})
+		return
+	}
+
+	clip, err := h.clipRepo.UpdateClip(c.Param("id"), userID, req.Title, req.Note, req.StartMs, req.EndMs)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid clip range") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "'end_ms' must be after 'start_ms', and both must be non-negative.",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Clip not found",
+				"message": "The requested clip does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to update clip. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, clip)
+}
+
+// DeleteClip removes a clip
+func (h *ClipHandler) DeleteClip(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.clipRepo.DeleteClip(c.Param("id"), userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Clip not found",
+				"message": "The requested clip does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to delete clip. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Clip deleted successfully",
+	})
+}
+
+// ExportClip streams the clip's transcript excerpt, re-based to start at zero, as txt, srt, vtt or json
+func (h *ClipHandler) ExportClip(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	format, err := export.ParseFormat(c.DefaultQuery("format", string(export.FormatSRT)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid export format",
+			"message": "Supported formats are txt, srt, vtt and json.",
+		})
+		return
+	}
+
+	clip, ok := h.loadClip(c, userID)
+	if !ok {
+		return
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(clip.FileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The clip's file has no transcript.",
+		})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="clip-%s.%s"`, clip.ID, format))
+	c.Status(http.StatusOK)
+	if err := export.Write(c.Writer, export.Excerpt(transcript, clip.StartMs, clip.EndMs), format); err != nil {
+		c.Error(err)
+	}
+}
+
+// ExportClipAudio streams the clip's slice of a WAV source as a standalone WAV file
+func (h *ClipHandler) ExportClipAudio(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	clip, ok := h.loadClip(c, userID)
+	if !ok {
+		return
+	}
+
+	file, err := h.fileRepo.GetFileByID(clip.FileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve file. Please try again later.",
+		})
+		return
+	}
+	if file == nil || file.StorageKey == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Media not found",
+			"message": "The clip's file has no stored media.",
+		})
+		return
+	}
+
+	source, err := h.store.Open(c.Request.Context(), *file.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Media not found",
+				"message": "The clip's file has no stored media.",
+			})
+			return
+		}
+		log.Printf("Error opening media for file %s: %v", file.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Storage error",
+			"message": "Unable to read the file's media. Please try again later.",
+		})
+		return
+	}
+	defer source.Close()
+
+	wav, err := audio.ReadWAV(source)
+	if err != nil {
+		if errors.Is(err, audio.ErrNotWAV) {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"error":   "Unsupported media",
+				"message": "Audio clips can only be cut from WAV files.",
+			})
+			return
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Unsupported media",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	slice := wav.Slice(clip.StartMs, clip.EndMs)
+	c.Header("Content-Type", "audio/wav")
+	c.Header("Content-Length", strconv.FormatInt(slice.Size(), 10))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="clip-%s.wav"`, clip.ID))
+	c.Status(http.StatusOK)
+	if _, err := slice.WriteTo(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
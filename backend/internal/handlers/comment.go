@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const maxCommentLength = 5000
+
+// mentionPattern matches "@" followed by an email address, e.g. "@sam@example.com"
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+type CommentHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	commentRepo    *repository.CommentRepository
+}
+
+func NewCommentHandler(transcriptRepo *repository.TranscriptRepository, commentRepo *repository.CommentRepository) *CommentHandler {
+	return &CommentHandler{
+		transcriptRepo: transcriptRepo,
+		commentRepo:    commentRepo,
+	}
+}
+
+type CreateCommentRequest struct {
+	Body      string  `json:"body" binding:"required"`
+	ParentID  *string `json:"parent_id"`
+	SegmentID *string `json:"segment_id"`
+	StartMs   *int64  `json:"start_ms"`
+	EndMs     *int64  `json:"end_ms"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// loadTranscript fetches the :id file's transcript, writing the error response itself on failure
+func (h *CommentHandler) loadTranscript(c *gin.Context, userID string) (*models.Transcript, bool) {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// validateBody trims a comment body, writing the error response itself when it is unusable
+func validateBody(c *gin.Context, body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Comment cannot be empty.",
+		})
+		return "", false
+	}
+	if len(body) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Comment must be less than 5000 characters.",
+		})
+		return "", false
+	}
+	return body, true
+}
+
+// mentionedUsers resolves the @email mentions in a comment body to user IDs, ignoring emails of
+// users who cannot see the transcript
+func (h *CommentHandler) mentionedUsers(transcriptID, body string) ([]string, error) {
+	var emails []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		emails = append(emails, match[1])
+	}
+
+	return h.commentRepo.MentionableUsers(transcriptID, emails)
+}
+
+// ListComments returns a transcript's comment threads in playback order.
+// ?status=open|resolved filters threads; the default returns all of them.
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", "all")
+	if status != "all" && status != "open" && status != "resolved" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status",
+			"message": "Status must be 'open', 'resolved' or 'all'.",
+		})
+		return
+	}
+	if status == "all" {
+		status = ""
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	threads, err := h.commentRepo.ListThreads(transcript.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve comments. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": threads,
+	})
+}
+
+// CreateComment starts a thread anchored to a segment and/or time range, or replies to one
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'body'.",
+		})
+		return
+	}
+
+	body, ok := validateBody(c, req.Body)
+	if !ok {
+		return
+	}
+
+	if req.ParentID != nil && (req.SegmentID != nil || req.StartMs != nil || req.EndMs != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Replies share their thread's anchor and cannot set 'segment_id', 'start_ms' or 'end_ms'.",
+		})
+		return
+	}
+	if req.ParentID == nil && req.SegmentID == nil && (req.StartMs == nil || req.EndMs == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "A comment must be anchored to a 'segment_id' or a 'start_ms'/'end_ms' range.",
+		})
+		return
+	}
+	if (req.StartMs != nil && *req.StartMs < 0) || (req.StartMs != nil && req.EndMs != nil && *req.EndMs < *req.StartMs) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "'end_ms' must not be before 'start_ms', and both must be non-negative.",
+		})
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	mentions, err := h.mentionedUsers(transcript.ID, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to resolve mentions. Please try again later.",
+		})
+		return
+	}
+
+	comment, err := h.commentRepo.CreateComment(&models.Comment{
+		TranscriptID: transcript.ID,
+		UserID:       userID,
+		ParentID:     req.ParentID,
+		SegmentID:    req.SegmentID,
+		StartMs:      req.StartMs,
+		EndMs:        req.EndMs,
+		Body:         body,
+	}, mentions)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "parent comment not found"):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Comment not found",
+				"message": "The comment you are replying to does not exist on this transcript.",
+			})
+		case strings.Contains(err.Error(), "segment not found"):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Segment not found",
+				"message": "The specified segment does not exist in this transcript.",
+			})
+		case strings.Contains(err.Error(), "reply to a reply"):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Replies can only be added to the first comment of a thread.",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to create comment. Please try again later.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateComment edits the body of the caller's own comment
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'body'.",
+		})
+		return
+	}
+
+	body, ok := validateBody(c, req.Body)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	mentions, err := h.mentionedUsers(transcript.ID, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to resolve mentions. Please try again later.",
+		})
+		return
+	}
+
+	comment, err := h.commentRepo.UpdateComment(c.Param("commentId"), transcript.ID, userID, body, mentions)
+	if err != nil {
+		h.respondCommentError(c, err, "Unable to update comment. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment removes the caller's own comment; deleting a thread's first comment removes the thread
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.commentRepo.DeleteComment(c.Param("commentId"), transcript.ID, userID); err != nil {
+		h.respondCommentError(c, err, "Unable to delete comment. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment deleted successfully",
+	})
+}
+
+// ResolveComment marks a thread as resolved
+func (h *CommentHandler) ResolveComment(c *gin.Context) {
+	h.setResolved(c, true)
+}
+
+// UnresolveComment reopens a resolved thread
+func (h *CommentHandler) UnresolveComment(c *gin.Context) {
+	h.setResolved(c, false)
+}
+
+func (h *CommentHandler) setResolved(c *gin.Context, resolved bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentRepo.SetResolved(c.Param("commentId"), transcript.ID, userID, resolved)
+	if err != nil {
+		h.respondCommentError(c, err, "Unable to update comment. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// respondCommentError maps comment repository errors to responses
+func (h *CommentHandler) respondCommentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case strings.Contains(err.Error(), "comment not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Comment not found",
+			"message": "The requested comment does not exist on this transcript.",
+		})
+	case strings.Contains(err.Error(), "only the author"):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "Only the author of a comment can change it.",
+		})
+	case strings.Contains(err.Error(), "only threads"):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Only a thread's first comment can be resolved.",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": fallback,
+		})
+	}
+}
+
+// ListMentions returns comments that @mention the current user
+func (h *CommentHandler) ListMentions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(c, 50, 200)
+	mentions, err := h.commentRepo.ListMentions(userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve mentions. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mentions": mentions,
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// versionETag is the strong ETag of a folder or file: its version in quotes. Clients send it back
+// in If-Match to make a change conditional on nobody having changed the item since. GETs whose
+// body holds more than the item (a folder with its contents) add a hash of the body after a dot,
+// "<version>.<hash>", which If-Match accepts the same way.
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersions reads the If-Match header as the versions the item must be at: nil when it is
+// absent or "*", and a version that never matches for tags the server did not issue (If-Match
+// uses strong comparison, so weak tags never match either)
+func ifMatchVersions(c *gin.Context) []int64 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			versions = append(versions, -1)
+			continue
+		}
+		value := tag[1 : len(tag)-1]
+		if dot := strings.IndexByte(value, '.'); dot >= 0 {
+			value = value[:dot]
+		}
+		version, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			version = -1
+		}
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+// respondFolder writes a folder with its ETag
+func respondFolder(c *gin.Context, status int, folder *models.Folder) {
+	c.Header("ETag", versionETag(folder.Version))
+	c.JSON(status, folder)
+}
+
+// respondFile writes a file with its ETag
+func respondFile(c *gin.Context, status int, file *models.File) {
+	c.Header("ETag", versionETag(file.Version))
+	c.JSON(status, file)
+}
+
+// respondFilePreconditionFailed writes the 412 for a failed If-Match on a file with the file as it
+// is now
+func respondFilePreconditionFailed(c *gin.Context, file *models.File) {
+	c.Header("ETag", versionETag(file.Version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "Precondition failed",
+		"message": "The file has changed since it was fetched. Review the current version and try again.",
+		"current": file,
+	})
+}
+
+// respondPreconditionFailed writes the 412 for a failed If-Match with the folder as it is now, or
+// a 404 if it has since been deleted
+func respondPreconditionFailed(c *gin.Context, folder *models.Folder) {
+	if folder == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Folder not found",
+			"message": "The specified folder does not exist or you don't have access to it.",
+		})
+		return
+	}
+	c.Header("ETag", versionETag(folder.Version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "Precondition failed",
+		"message": "The folder has changed since it was fetched. Review the current version and try again.",
+		"current": folder,
+	})
+}
+
+// respondCacheable writes a GET response with a weak ETag over its body, or 304 Not Modified when
+// the request's If-None-Match already has it
+func respondCacheable(c *gin.Context, body interface{}) {
+	respondTagged(c, body, func(hash string) string {
+		return `W/"` + hash + `"`
+	})
+}
+
+// respondVersioned is respondCacheable for a GET of a folder or file: its strong ETag carries the
+// item's version, so it can be sent back in If-Match, and a hash of the body
+func respondVersioned(c *gin.Context, version int64, body interface{}) {
+	respondTagged(c, body, func(hash string) string {
+		return `"` + strconv.FormatInt(version, 10) + "." + hash + `"`
+	})
+}
+
+func respondTagged(c *gin.Context, body interface{}, etagFor func(hash string) string) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Server error",
+			"message": "Unable to encode the response. Please try again later.",
+		})
+		return
+	}
+
+	sum := sha256.Sum256(raw)
+	etag := etagFor(hex.EncodeToString(sum[:16]))
+	c.Header("ETag", etag)
+
+	if noneMatch := c.GetHeader("If-None-Match"); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			// If-None-Match uses weak comparison
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+}
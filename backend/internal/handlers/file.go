@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type FileHandler struct {
+	fileRepo *repository.FileRepository
+}
+
+func NewFileHandler(fileRepo *repository.FileRepository) *FileHandler {
+	return &FileHandler{
+		fileRepo: fileRepo,
+	}
+}
+
+// GetFile returns a file with its ETag, or 304 Not Modified for a matching If-None-Match
+func (h *FileHandler) GetFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.fileRepo.GetFileByID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve file. Please try again later.",
+		})
+		return
+	}
+	if file == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "File not found",
+			"message": "The requested file does not exist or you don't have access to it.",
+		})
+		return
+	}
+
+	respondVersioned(c, file.Version, file)
+}
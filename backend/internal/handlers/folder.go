@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
 	"github.com/mouizahmed/justscribe-backend/internal/repository"
 )
 
@@ -42,9 +46,21 @@ func (h *FolderHandler) GetFolderData(c *gin.Context) {
 	// Get folder ID from URL parameter (empty string for root)
 	folderID := c.Param("id")
 
+	query, ok := parseFolderListQuery(c)
+	if !ok {
+		return
+	}
+
 	// Get complete folder data
-	folderData, err := h.folderRepo.GetFolderData(folderID, userIDStr)
+	folderData, err := h.folderRepo.GetFolderData(folderID, userIDStr, query)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid cursor") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid cursor",
+				"message": "The cursor is malformed or was issued for a different sort order.",
+			})
+			return
+		}
 		if err.Error() == "folder not found" {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Folder not found",
@@ -59,7 +75,11 @@ func (h *FolderHandler) GetFolderData(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, folderData)
+	if folderData.Folder != nil {
+		respondVersioned(c, folderData.Folder.Version, folderData)
+		return
+	}
+	respondCacheable(c, folderData)
 }
 
 // GetAllFolders handles requests for all user folders (for tree view)
@@ -93,14 +113,111 @@ func (h *FolderHandler) GetAllFolders(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondCacheable(c, gin.H{
 		"folders": folders,
 	})
 }
 
+const (
+	defaultFolderListLimit = 100
+	maxFolderListLimit     = 500
+)
+
+// parseFolderListQuery reads a folder listing's sort (name, created_at, updated_at, size,
+// duration), order (asc, desc), limit, cursor and filters (type, language, tag, created_after,
+// created_before), writing the error response itself when one is invalid
+func parseFolderListQuery(c *gin.Context) (repository.FolderListQuery, bool) {
+	limit, _ := parsePagination(c, defaultFolderListLimit, maxFolderListLimit)
+	query := repository.FolderListQuery{
+		Sort:     models.FolderSortName,
+		Limit:    limit,
+		Cursor:   c.Query("cursor"),
+		Language: strings.TrimSpace(c.Query("language")),
+		Tag:      strings.TrimSpace(c.Query("tag")),
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		switch models.FolderSort(sort) {
+		case models.FolderSortName, models.FolderSortCreatedAt, models.FolderSortUpdatedAt, models.FolderSortSize, models.FolderSortDuration:
+			query.Sort = models.FolderSort(sort)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid sort",
+				"message": "Sort must be one of name, created_at, updated_at, size or duration.",
+			})
+			return query, false
+		}
+	}
+
+	switch strings.ToLower(c.DefaultQuery("order", "asc")) {
+	case "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid order",
+			"message": "Order must be asc or desc.",
+		})
+		return query, false
+	}
+
+	for _, fileType := range strings.Split(c.Query("type"), ",") {
+		if fileType = strings.ToLower(strings.TrimSpace(fileType)); fileType != "" {
+			query.Types = append(query.Types, fileType)
+		}
+	}
+
+	for _, bound := range []struct {
+		param string
+		value **time.Time
+	}{
+		{"created_after", &query.CreatedAfter},
+		{"created_before", &query.CreatedBefore},
+	} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			// A bare date covers the whole day, so created_before includes it
+			parsed, err = time.Parse(time.DateOnly, raw)
+			if err == nil && bound.param == "created_before" {
+				parsed = parsed.AddDate(0, 0, 1)
+			}
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid date",
+				"message": "Parameter '" + bound.param + "' must be an RFC 3339 timestamp or a YYYY-MM-DD date.",
+			})
+			return query, false
+		}
+		*bound.value = &parsed
+	}
+
+	return query, true
+}
+
 type CreateFolderRequest struct {
 	Name     string  `json:"name" binding:"required"`
 	ParentID *string `json:"parent_id"`
+	// Conflict is fail (the default), rename or merge (return the existing folder)
+	Conflict string `json:"conflict"`
+}
+
+// parseConflictPolicy reads a request's conflict policy, writing the error response itself when it
+// is invalid
+func parseConflictPolicy(c *gin.Context, raw string, fallback repository.ConflictPolicy) (repository.ConflictPolicy, bool) {
+	policy, err := repository.ParseConflictPolicy(raw, fallback)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "'conflict' must be one of fail, rename or merge.",
+		})
+		return "", false
+	}
+	return policy, true
 }
 
 // CreateFolder handles folder creation
@@ -151,8 +268,13 @@ func (h *FolderHandler) CreateFolder(c *gin.Context) {
 		return
 	}
 
+	policy, ok := parseConflictPolicy(c, req.Conflict, repository.ConflictFail)
+	if !ok {
+		return
+	}
+
 	// Create folder
-	folder, err := h.folderRepo.CreateFolder(req.Name, req.ParentID, userIDStr)
+	folder, created, err := h.folderRepo.CreateFolder(req.Name, req.ParentID, userIDStr, policy)
 	if err != nil {
 		// Check for specific database errors
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "already exists") {
@@ -176,11 +298,17 @@ func (h *FolderHandler) CreateFolder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, folder)
+	if !created {
+		respondFolder(c, http.StatusOK, folder)
+		return
+	}
+	respondFolder(c, http.StatusCreated, folder)
 }
 
 type UpdateFolderRequest struct {
 	Name string `json:"name" binding:"required"`
+	// Conflict is fail (the default), rename or merge (into the same-named sibling, which is returned)
+	Conflict string `json:"conflict"`
 }
 
 // UpdateFolder handles folder updates (rename)
@@ -241,9 +369,18 @@ func (h *FolderHandler) UpdateFolder(c *gin.Context) {
 		return
 	}
 
+	policy, ok := parseConflictPolicy(c, req.Conflict, repository.ConflictFail)
+	if !ok {
+		return
+	}
+
 	// Update folder
-	folder, err := h.folderRepo.UpdateFolder(folderID, req.Name, userIDStr)
+	folder, err := h.folderRepo.UpdateFolder(folderID, req.Name, userIDStr, policy, ifMatchVersions(c))
 	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			h.respondFolderChanged(c, folderID, userIDStr)
+			return
+		}
 		// Check for specific database errors
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, gin.H{
@@ -266,7 +403,7 @@ func (h *FolderHandler) UpdateFolder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, folder)
+	respondFolder(c, http.StatusOK, folder)
 }
 
 // DeleteFolder handles folder deletion (soft delete)
@@ -300,9 +437,13 @@ func (h *FolderHandler) DeleteFolder(c *gin.Context) {
 		return
 	}
 
-	// Delete folder
-	err := h.folderRepo.DeleteFolder(folderID, userIDStr)
+	// Delete the folder and everything below it
+	result, err := h.folderRepo.DeleteFolder(folderID, userIDStr, ifMatchVersions(c))
 	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			h.respondFolderChanged(c, folderID, userIDStr)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Folder not found",
@@ -318,12 +459,17 @@ func (h *FolderHandler) DeleteFolder(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Folder deleted successfully",
+		"message":           "Folder deleted successfully",
+		"deletion_batch_id": result.BatchID,
+		"deleted_folders":   result.DeletedFolders,
+		"deleted_files":     result.DeletedFiles,
 	})
 }
 
 type MoveFolderRequest struct {
 	ParentID *string `json:"parent_id"`
+	// Conflict is fail (the default), rename or merge (into the same-named folder, which is returned)
+	Conflict string `json:"conflict"`
 }
 
 // MoveFolder handles moving a folder to a new parent location
@@ -373,9 +519,25 @@ func (h *FolderHandler) MoveFolder(c *gin.Context) {
 		newParentID = *req.ParentID
 	}
 
+	policy, ok := parseConflictPolicy(c, req.Conflict, repository.ConflictFail)
+	if !ok {
+		return
+	}
+
 	// Move folder
-	folder, err := h.folderRepo.MoveFolder(folderID, newParentID, userIDStr)
+	folder, err := h.folderRepo.MoveFolder(folderID, newParentID, userIDStr, policy, ifMatchVersions(c))
 	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			h.respondFolderChanged(c, folderID, userIDStr)
+			return
+		}
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Folder already exists",
+				"message": "A folder with this name already exists in the destination.",
+			})
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Folder not found",
@@ -404,5 +566,260 @@ func (h *FolderHandler) MoveFolder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, folder)
+	respondFolder(c, http.StatusOK, folder)
+}
+
+// respondFolderChanged answers a failed If-Match with the folder's current representation
+func (h *FolderHandler) respondFolderChanged(c *gin.Context, folderID, userID string) {
+	current, err := h.folderRepo.GetFolderByID(folderID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve folder. Please try again later.",
+		})
+		return
+	}
+	respondPreconditionFailed(c, current)
+}
+
+type CopyFolderRequest struct {
+	// ParentID is the destination folder; omitted or null copies to the root
+	ParentID           *string `json:"parent_id"`
+	IncludeFiles       bool    `json:"include_files"`
+	IncludeTranscripts bool    `json:"include_transcripts"`
+	// Conflict is rename (the default), fail or merge (into the same-named folder)
+	Conflict string `json:"conflict"`
+}
+
+// CopyFolder deep-copies a folder tree into a destination folder, optionally with its files and
+// their transcripts, and returns the new IDs keyed by the originals
+func (h *FolderHandler) CopyFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CopyFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body is missing required fields or has invalid format.",
+		})
+		return
+	}
+	if req.IncludeTranscripts && !req.IncludeFiles {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Copying transcripts requires 'include_files'.",
+		})
+		return
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+	policy, ok := parseConflictPolicy(c, req.Conflict, repository.ConflictRename)
+	if !ok {
+		return
+	}
+
+	result, err := h.folderRepo.CopyFolder(c.Param("id"), req.ParentID, userID, repository.CopyFolderOptions{
+		IncludeFiles:       req.IncludeFiles,
+		IncludeTranscripts: req.IncludeTranscripts,
+		Conflict:           policy,
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "destination folder"):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid destination",
+				"message": "The destination folder does not exist or you don't have access to it.",
+			})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Folder not found",
+				"message": "The specified folder does not exist or you don't have access to it.",
+			})
+		case strings.Contains(err.Error(), "descendants"):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid copy operation",
+				"message": "Cannot copy a folder into itself or its descendants.",
+			})
+		case strings.Contains(err.Error(), "already exists"):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Folder already exists",
+				"message": "A folder with this name already exists in the destination.",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to copy folder. Please try again later.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+const (
+	defaultFolderTreeDepth = 3
+	maxFolderTreeDepth     = 32
+)
+
+// GetFolderTree returns the nested folder tree below the root, or below a folder (:id) to expand
+// it lazily, down to max_depth levels with file counts, bytes and duration totalled per subtree
+func (h *FolderHandler) GetFolderTree(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	maxDepth := defaultFolderTreeDepth
+	if raw := c.Query("max_depth"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxFolderTreeDepth {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"message": "'max_depth' must be a number between 1 and 32.",
+			})
+			return
+		}
+		maxDepth = value
+	}
+
+	var rootID *string
+	if folderID := c.Param("id"); folderID != "" {
+		folder, err := h.folderRepo.GetFolderByID(folderID, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to retrieve folder. Please try again later.",
+			})
+			return
+		}
+		if folder == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Folder not found",
+				"message": "The requested folder does not exist or you don't have access to it.",
+			})
+			return
+		}
+		rootID = &folder.ID
+	}
+
+	nodes, smartFolders, err := h.folderRepo.GetFolderTree(userID, rootID, maxDepth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve folder tree. Please try again later.",
+		})
+		return
+	}
+
+	respondCacheable(c, models.FolderTreeResponse{
+		RootID:       rootID,
+		MaxDepth:     maxDepth,
+		Nodes:        nodes,
+		SmartFolders: smartFolders,
+	})
+}
+
+type CreateFolderPathRequest struct {
+	Path string `json:"path" binding:"required"`
+}
+
+// GetFolderByPath resolves a path like /Clients/Acme/2025 to its folder ("/" is the root)
+func (h *FolderHandler) GetFolderByPath(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	names, err := repository.SplitFolderPath(c.Query("path"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid path",
+			"message": strings.TrimPrefix(err.Error(), "invalid path: "),
+		})
+		return
+	}
+
+	folder, err := h.folderRepo.GetFolderByPath(userID, names)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to resolve folder path. Please try again later.",
+		})
+		return
+	}
+	if folder == nil && len(names) > 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Folder not found",
+			"message": "No folder exists at this path.",
+		})
+		return
+	}
+
+	result := models.FolderPathResult{
+		Folder: folder,
+		Path:   repository.JoinFolderPath(names),
+	}
+	if folder != nil {
+		respondVersioned(c, folder.Version, result)
+		return
+	}
+	respondCacheable(c, result)
+}
+
+// CreateFolderByPath creates the folder at a path and any missing folders above it, like mkdir -p.
+// It responds 201 when something was created and 200 when the whole path already existed.
+func (h *FolderHandler) CreateFolderByPath(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateFolderPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include a 'path'.",
+		})
+		return
+	}
+
+	names, err := repository.SplitFolderPath(req.Path)
+	if err == nil && len(names) == 0 {
+		err = fmt.Errorf("invalid path: the path must name at least one folder")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid path",
+			"message": strings.TrimPrefix(err.Error(), "invalid path: "),
+		})
+		return
+	}
+
+	result, err := h.folderRepo.CreateFolderPath(userID, names)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Folder already exists",
+				"message": "A folder along this path was created at the same time. Please try again.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to create folder path. Please try again later.",
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if result.CreatedFolders > 0 {
+		status = http.StatusCreated
+	}
+	c.Header("ETag", versionETag(result.Folder.Version))
+	c.JSON(status, result)
 }
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const (
+	maxFolderIconLength        = 16
+	maxFolderDescriptionLength = 1000
+)
+
+var folderColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+type UpdateFolderMetadataRequest struct {
+	// Each field is optional; an empty string clears it
+	Color       *string `json:"color"`
+	Icon        *string `json:"icon"`
+	Description *string `json:"description"`
+}
+
+// UpdateFolderMetadata sets a folder's color, icon and description
+func (h *FolderHandler) UpdateFolderMetadata(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateFolderMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must be valid JSON.",
+		})
+		return
+	}
+	if req.Color == nil && req.Icon == nil && req.Description == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Provide at least one of 'color', 'icon' or 'description'.",
+		})
+		return
+	}
+
+	update := repository.FolderMetadataUpdate{}
+	if req.Color != nil {
+		color := strings.ToLower(strings.TrimSpace(*req.Color))
+		if color != "" && !folderColorPattern.MatchString(color) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Color must be a hex color like #3b82f6.",
+			})
+			return
+		}
+		update.Color = &color
+	}
+	if req.Icon != nil {
+		icon := strings.TrimSpace(*req.Icon)
+		if utf8.RuneCountInString(icon) > maxFolderIconLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Icon must be at most 16 characters.",
+			})
+			return
+		}
+		update.Icon = &icon
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(description) > maxFolderDescriptionLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Description must be at most 1000 characters.",
+			})
+			return
+		}
+		update.Description = &description
+	}
+
+	folderID := c.Param("id")
+	folder, err := h.folderRepo.UpdateFolderMetadata(folderID, userID, update, ifMatchVersions(c))
+	h.respondFolderUpdate(c, folderID, userID, folder, err)
+}
+
+// PinFolder pins a folder to the sidebar's quick access list
+func (h *FolderHandler) PinFolder(c *gin.Context) {
+	h.setPinned(c, true)
+}
+
+// UnpinFolder removes a folder from the quick access list
+func (h *FolderHandler) UnpinFolder(c *gin.Context) {
+	h.setPinned(c, false)
+}
+
+func (h *FolderHandler) setPinned(c *gin.Context, pinned bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	folderID := c.Param("id")
+	folder, err := h.folderRepo.SetFolderPinned(folderID, userID, pinned, ifMatchVersions(c))
+	h.respondFolderUpdate(c, folderID, userID, folder, err)
+}
+
+func (h *FolderHandler) respondFolderUpdate(c *gin.Context, folderID, userID string, folder *models.Folder, err error) {
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "precondition failed"):
+			h.respondFolderChanged(c, folderID, userID)
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Folder not found",
+				"message": "The specified folder does not exist or you don't have access to it.",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to update folder. Please try again later.",
+			})
+		}
+		return
+	}
+
+	respondFolder(c, http.StatusOK, folder)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type JobHandler struct {
+	jobRepo *repository.JobRepository
+}
+
+func NewJobHandler(jobRepo *repository.JobRepository) *JobHandler {
+	return &JobHandler{
+		jobRepo: jobRepo,
+	}
+}
+
+// GetJob returns the status of a background job
+func (h *JobHandler) GetJob(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobRepo.GetJobByID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve job. Please try again later.",
+		})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Job not found",
+			"message": "The requested job does not exist or you don't have access to it.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/keywords"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const (
+	defaultKeywordLimit = 20
+	maxKeywordLimit     = 100
+	maxKeywordLength    = 100
+)
+
+type KeywordHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	keywordRepo    *repository.KeywordRepository
+}
+
+func NewKeywordHandler(transcriptRepo *repository.TranscriptRepository, keywordRepo *repository.KeywordRepository) *KeywordHandler {
+	return &KeywordHandler{
+		transcriptRepo: transcriptRepo,
+		keywordRepo:    keywordRepo,
+	}
+}
+
+type ConfirmKeywordRequest struct {
+	Keyword string `json:"keyword" binding:"required"`
+}
+
+// extract runs key-phrase extraction over the :id file's transcript and stores the result,
+// writing the error response itself on failure
+func (h *KeywordHandler) extract(c *gin.Context, userID string) bool {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return false
+	}
+
+	if err := h.keywordRepo.SaveFileKeywords(transcript.FileID, userID, keywords.Extract(transcript, keywords.DefaultLimit)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to save keywords. Please try again later.",
+		})
+		return false
+	}
+	return true
+}
+
+// respondFileKeywords replies with the :id file's top keywords
+func (h *KeywordHandler) respondFileKeywords(c *gin.Context, userID string) {
+	limit, _ := parsePagination(c, defaultKeywordLimit, maxKeywordLimit)
+	found, err := h.keywordRepo.TopKeywords(userID, repository.SearchScope{FileID: c.Param("id")}, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve keywords. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"file_id":  c.Param("id"),
+		"keywords": found,
+	})
+}
+
+// GetFileKeywords returns a file's key phrases, extracting them the first time they're asked for
+func (h *KeywordHandler) GetFileKeywords(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	extracted, err := h.keywordRepo.HasKeywords(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve keywords. Please try again later.",
+		})
+		return
+	}
+	if !extracted && !h.extract(c, userID) {
+		return
+	}
+
+	h.respondFileKeywords(c, userID)
+}
+
+// ExtractFileKeywords re-runs key-phrase extraction, e.g. after the transcript was edited
+func (h *KeywordHandler) ExtractFileKeywords(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if !h.extract(c, userID) {
+		return
+	}
+
+	h.respondFileKeywords(c, userID)
+}
+
+// GetTopKeywords ranks keywords across the user's library, or a folder and its subfolders (folder_id)
+func (h *KeywordHandler) GetTopKeywords(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := parsePagination(c, defaultKeywordLimit, maxKeywordLimit)
+	scope := repository.SearchScope{FolderID: c.Query("folder_id")}
+
+	found, err := h.keywordRepo.TopKeywords(userID, scope, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve keywords. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"folder_id": scope.FolderID,
+		"keywords":  found,
+	})
+}
+
+// GetKeywordFiles lists the files mentioning a keyword (/api/keywords/files?keyword=)
+func (h *KeywordHandler) GetKeywordFiles(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	keyword := keywords.Normalize(c.Query("keyword"))
+	if keyword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "Query parameter 'keyword' is required.",
+		})
+		return
+	}
+
+	limit, offset := parsePagination(c, defaultSearchLimit, maxSearchLimit)
+	scope := repository.SearchScope{FolderID: c.Query("folder_id")}
+
+	files, total, err := h.keywordRepo.FilesForKeyword(userID, keyword, scope, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve files for this keyword. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.KeywordFilesResponse{
+		Keyword: keyword,
+		Files:   files,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// ConfirmKeyword records a keyword the user agrees describes the file in the file's tags
+func (h *KeywordHandler) ConfirmKeyword(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ConfirmKeywordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'keyword'.",
+		})
+		return
+	}
+
+	keyword, ok := validateKeyword(c, req.Keyword)
+	if !ok {
+		return
+	}
+
+	file, err := h.keywordRepo.ConfirmKeyword(c.Param("id"), userID, keyword, ifMatchVersions(c))
+	respondTagUpdate(c, file, err)
+}
+
+// UnconfirmKeyword removes a keyword from the file's tags (/api/files/:id/keywords/confirm?keyword=)
+func (h *KeywordHandler) UnconfirmKeyword(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	keyword, ok := validateKeyword(c, c.Query("keyword"))
+	if !ok {
+		return
+	}
+
+	file, err := h.keywordRepo.UnconfirmKeyword(c.Param("id"), userID, keyword, ifMatchVersions(c))
+	respondTagUpdate(c, file, err)
+}
+
+func validateKeyword(c *gin.Context, raw string) (string, bool) {
+	keyword := keywords.Normalize(raw)
+	if keyword == "" || len(keyword) > maxKeywordLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Keyword must be between 1 and 100 characters.",
+		})
+		return "", false
+	}
+	return keyword, true
+}
+
+func respondTagUpdate(c *gin.Context, file *models.File, err error) {
+	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			respondFilePreconditionFailed(c, file)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "File not found",
+				"message": "The requested file does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to update the file's keywords. Please try again later.",
+		})
+		return
+	}
+
+	respondFile(c, http.StatusOK, file)
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/redact"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type RedactionHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	redactionRepo  *repository.RedactionRepository
+	redactor       *redact.Redactor
+}
+
+func NewRedactionHandler(transcriptRepo *repository.TranscriptRepository, redactionRepo *repository.RedactionRepository, redactor *redact.Redactor) *RedactionHandler {
+	return &RedactionHandler{
+		transcriptRepo: transcriptRepo,
+		redactionRepo:  redactionRepo,
+		redactor:       redactor,
+	}
+}
+
+type CreateRedactionTermRequest struct {
+	Term string `json:"term" binding:"required"`
+}
+
+// ListTerms returns the user's custom redaction terms
+func (h *RedactionHandler) ListTerms(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	terms, err := h.redactionRepo.ListTerms(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve redaction terms. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"terms": terms,
+	})
+}
+
+// CreateTerm adds a word or phrase that is always redacted from the user's transcripts
+func (h *RedactionHandler) CreateTerm(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateRedactionTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Term) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include a non-empty 'term'.",
+		})
+		return
+	}
+	term := strings.TrimSpace(req.Term)
+	if len(term) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Redaction term must be less than 200 characters.",
+		})
+		return
+	}
+
+	created, err := h.redactionRepo.CreateTerm(userID, term)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Duplicate term",
+				"message": "This redaction term already exists.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to create redaction term. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// DeleteTerm removes a custom redaction term
+func (h *RedactionHandler) DeleteTerm(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.redactionRepo.DeleteTerm(c.Param("id"), userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Term not found",
+				"message": "The requested redaction term does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to delete redaction term. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Redaction term deleted successfully",
+	})
+}
+
+// loadTranscript fetches the :id file's transcript, writing the error response itself on failure.
+// Transcripts are only ever loaded for their owner, which is what limits reveal and restore to them.
+func (h *RedactionHandler) loadTranscript(c *gin.Context, userID string) (*models.Transcript, bool) {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// requireReversible rejects operations that need the redaction key when it isn't configured
+func (h *RedactionHandler) requireReversible(c *gin.Context) bool {
+	if h.redactor.Reversible() {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "Redaction unavailable",
+		"message": "Stored redaction is not configured on this server.",
+	})
+	return false
+}
+
+// GetRedactedTranscript returns the transcript with PII replaced by placeholders, without changing what is stored
+func (h *RedactionHandler) GetRedactedTranscript(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	results, err := h.redactor.Detect(c.Request.Context(), userID, transcript)
+	if err != nil {
+		log.Printf("Error detecting PII for file %s: %v", transcript.FileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Redaction failed",
+			"message": "Unable to redact the transcript. Please try again later.",
+		})
+		return
+	}
+	redact.Apply(transcript, results)
+
+	c.JSON(http.StatusOK, gin.H{
+		"transcript":    transcript,
+		"entity_counts": redact.CountEntities(results),
+	})
+}
+
+// ApplyRedactions permanently redacts PII from the stored transcript and its translations,
+// keeping the originals encrypted so the owner can reveal or restore them
+func (h *RedactionHandler) ApplyRedactions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	if !h.requireReversible(c) {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	transcripts := []*models.Transcript{transcript}
+	variants, err := h.transcriptRepo.ListTranscriptVariants(transcript.FileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve translations. Please try again later.",
+		})
+		return
+	}
+	for _, variant := range variants {
+		loaded, err := h.transcriptRepo.GetTranscriptVariant(transcript.FileID, userID, *variant.Language)
+		if err != nil || loaded == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to retrieve translations. Please try again later.",
+			})
+			return
+		}
+		transcripts = append(transcripts, loaded)
+	}
+
+	var results []redact.SegmentResult
+	for _, t := range transcripts {
+		found, err := h.redactor.Detect(c.Request.Context(), userID, t)
+		if err != nil {
+			log.Printf("Error detecting PII for file %s: %v", transcript.FileID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Redaction failed",
+				"message": "Unable to redact the transcript. Please try again later.",
+			})
+			return
+		}
+		results = append(results, found...)
+	}
+
+	sealed, err := h.redactor.Seal(results)
+	if err != nil {
+		log.Printf("Error encrypting redacted originals for file %s: %v", transcript.FileID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Redaction failed",
+			"message": "Unable to redact the transcript. Please try again later.",
+		})
+		return
+	}
+
+	counts := redact.CountEntities(results)
+	if err := h.redactionRepo.ApplyRedactions(transcript.ID, userID, sealed, counts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to store redactions. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Transcript redacted successfully",
+		"segments_redacted": len(sealed),
+		"entity_counts":     counts,
+	})
+}
+
+// ListRedactions returns where placeholders were put in the stored transcript, without the originals
+func (h *RedactionHandler) ListRedactions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	redactions, err := h.redactionRepo.GetRedactions(transcript.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve redactions. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"redacted_at": transcript.RedactedAt,
+		"redactions":  redactions,
+	})
+}
+
+// openRedactions decrypts every stored redaction of the transcript, writing the error response itself on failure
+func (h *RedactionHandler) openRedactions(c *gin.Context, transcript *models.Transcript) ([]models.SegmentRedaction, []repository.RestoredSegment, bool) {
+	redactions, err := h.redactionRepo.GetRedactions(transcript.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve redactions. Please try again later.",
+		})
+		return nil, nil, false
+	}
+
+	originals := make([]repository.RestoredSegment, 0, len(redactions))
+	for _, redaction := range redactions {
+		text, words, err := h.redactor.Open(redaction)
+		if err != nil {
+			log.Printf("Error decrypting redaction %s: %v", redaction.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Decryption failed",
+				"message": "Unable to decrypt the redacted originals.",
+			})
+			return nil, nil, false
+		}
+		originals = append(originals, repository.RestoredSegment{SegmentID: redaction.SegmentID, Text: text, Words: words})
+	}
+	return redactions, originals, true
+}
+
+// RevealRedactions shows the owner the original text behind each redaction. Every reveal is audited.
+func (h *RedactionHandler) RevealRedactions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	if !h.requireReversible(c) {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	redactions, originals, ok := h.openRedactions(c, transcript)
+	if !ok {
+		return
+	}
+
+	counts := make(map[string]int)
+	revealed := make([]models.RevealedSegment, 0, len(redactions))
+	for i, redaction := range redactions {
+		for _, entity := range redaction.Entities {
+			counts[entity.Type]++
+		}
+		revealed = append(revealed, models.RevealedSegment{
+			SegmentID:    redaction.SegmentID,
+			RedactedText: redaction.RedactedText,
+			OriginalText: originals[i].Text,
+			Entities:     redaction.Entities,
+		})
+	}
+
+	if err := h.redactionRepo.LogAudit(transcript.ID, userID, models.RedactionActionReveal, len(revealed), counts); err != nil {
+		// Never hand out originals without a record of it
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to record the reveal. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"segments": revealed,
+	})
+}
+
+// RestoreRedactions reverses all redactions, writing the originals back into the transcript
+func (h *RedactionHandler) RestoreRedactions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	if !h.requireReversible(c) {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	redactions, originals, ok := h.openRedactions(c, transcript)
+	if !ok {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, redaction := range redactions {
+		for _, entity := range redaction.Entities {
+			counts[entity.Type]++
+		}
+	}
+
+	if err := h.redactionRepo.RestoreSegments(transcript.ID, userID, originals, counts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to restore the transcript. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Transcript restored successfully",
+		"segments_restored": len(originals),
+	})
+}
+
+// GetAuditLog returns the transcript's redaction audit trail
+func (h *RedactionHandler) GetAuditLog(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(c, 50, 200)
+	entries, err := h.redactionRepo.GetAuditLog(transcript.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve audit log. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const (
+	defaultSegmentThreshold = 0.6
+	defaultWordThreshold    = 0.5
+	defaultReviewLimit      = 50
+	maxReviewLimit          = 200
+)
+
+type ReviewHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	reviewRepo     *repository.ReviewRepository
+}
+
+func NewReviewHandler(transcriptRepo *repository.TranscriptRepository, reviewRepo *repository.ReviewRepository) *ReviewHandler {
+	return &ReviewHandler{
+		transcriptRepo: transcriptRepo,
+		reviewRepo:     reviewRepo,
+	}
+}
+
+type ReviewItemRequest struct {
+	SegmentID string `json:"segment_id" binding:"required"`
+	// WordIndex selects a word within the segment; omit it to act on the whole segment
+	WordIndex *int `json:"word_index"`
+}
+
+type CorrectReviewItemRequest struct {
+	ReviewItemRequest
+	Text string `json:"text" binding:"required"`
+}
+
+// parseReviewThresholds reads the segment_threshold and word_threshold query parameters,
+// writing the error response itself if either is out of range
+func parseReviewThresholds(c *gin.Context) (repository.ReviewThresholds, bool) {
+	thresholds := repository.ReviewThresholds{
+		Segment: defaultSegmentThreshold,
+		Word:    defaultWordThreshold,
+	}
+
+	params := []struct {
+		name   string
+		target *float64
+	}{
+		{"segment_threshold", &thresholds.Segment},
+		{"word_threshold", &thresholds.Word},
+	}
+	for _, param := range params {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || value > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"message": "'" + param.name + "' must be a number between 0 and 1.",
+			})
+			return thresholds, false
+		}
+		*param.target = value
+	}
+
+	return thresholds, true
+}
+
+// loadTranscript fetches the :id file's original transcript, writing the error response itself on failure
+func (h *ReviewHandler) loadTranscript(c *gin.Context, userID string) (*models.Transcript, bool) {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// GetQueue lists low-confidence segments and words, lowest confidence first, across a file
+// (file_id), a folder and its subfolders (folder_id) or all of the user's files
+func (h *ReviewHandler) GetQueue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	thresholds, ok := parseReviewThresholds(c)
+	if !ok {
+		return
+	}
+
+	kind := strings.ToLower(c.DefaultQuery("kind", "all"))
+	switch kind {
+	case "all":
+		kind = ""
+	case models.ReviewItemSegment, models.ReviewItemWord:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "'kind' must be 'all', 'segment' or 'word'.",
+		})
+		return
+	}
+
+	limit, offset := parsePagination(c, defaultReviewLimit, maxReviewLimit)
+	query := repository.ReviewQuery{
+		Scope: repository.SearchScope{
+			FolderID: c.Query("folder_id"),
+			FileID:   c.Query("file_id"),
+		},
+		Thresholds: thresholds,
+		Kind:       kind,
+	}
+
+	items, total, err := h.reviewRepo.ListQueue(userID, query, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve the review queue. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReviewQueueResponse{
+		Items:            items,
+		Total:            total,
+		Limit:            limit,
+		Offset:           offset,
+		SegmentThreshold: thresholds.Segment,
+		WordThreshold:    thresholds.Word,
+	})
+}
+
+// AcceptItem marks a queued segment or word as correct as transcribed
+func (h *ReviewHandler) AcceptItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'segment_id' and optionally 'word_index'.",
+		})
+		return
+	}
+
+	thresholds, ok := parseReviewThresholds(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	decision, err := h.reviewRepo.Accept(transcript.ID, req.SegmentID, userID, req.WordIndex)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	h.respondDecision(c, userID, thresholds, decision, nil)
+}
+
+// CorrectItem replaces a queued segment's text or a single word
+func (h *ReviewHandler) CorrectItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CorrectReviewItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'segment_id', a non-empty 'text' and optionally 'word_index'.",
+		})
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if req.WordIndex != nil && strings.ContainsAny(text, " \t\n") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "A word correction must be a single word. Correct the whole segment to change more.",
+		})
+		return
+	}
+
+	thresholds, ok := parseReviewThresholds(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	decision, segment, err := h.reviewRepo.Correct(transcript.ID, req.SegmentID, userID, req.WordIndex, text)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	h.respondDecision(c, userID, thresholds, decision, segment)
+}
+
+// respondDecision replies with a decision and how many items are left in the file's queue
+func (h *ReviewHandler) respondDecision(c *gin.Context, userID string, thresholds repository.ReviewThresholds, decision *models.ReviewDecision, segment *models.Segment) {
+	remaining, err := h.reviewRepo.CountQueue(c.Param("id"), userID, thresholds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "The decision was saved but the remaining queue could not be counted.",
+		})
+		return
+	}
+
+	response := gin.H{
+		"decision":  decision,
+		"remaining": remaining,
+	}
+	if segment != nil {
+		response["segment"] = segment
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "segment not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Segment not found",
+			"message": "The specified segment does not exist in this transcript.",
+		})
+	case strings.Contains(err.Error(), "word not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Word not found",
+			"message": "The segment has no word at the specified index.",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to save the review decision. Please try again later.",
+		})
+	}
+}
+
+// CompleteReview marks a file as reviewed, provided nothing is left in its queue
+func (h *ReviewHandler) CompleteReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	thresholds, ok := parseReviewThresholds(c)
+	if !ok {
+		return
+	}
+
+	file, remaining, err := h.reviewRepo.MarkReviewed(c.Param("id"), userID, thresholds, ifMatchVersions(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			respondFilePreconditionFailed(c, file)
+			return
+		}
+		if strings.Contains(err.Error(), "review queue not empty") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "Review incomplete",
+				"message":   "Accept or correct every queued item before marking the file reviewed.",
+				"remaining": remaining,
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "File not found",
+				"message": "The requested file does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to mark the file reviewed. Please try again later.",
+		})
+		return
+	}
+
+	respondFile(c, http.StatusOK, file)
+}
+
+// ReopenReview clears a file's reviewed status
+func (h *ReviewHandler) ReopenReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.reviewRepo.ReopenReview(c.Param("id"), userID, ifMatchVersions(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			respondFilePreconditionFailed(c, file)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "File not found",
+				"message": "The requested file does not exist or you don't have access to it.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to reopen the review. Please try again later.",
+		})
+		return
+	}
+
+	respondFile(c, http.StatusOK, file)
+}
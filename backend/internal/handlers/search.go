@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+type SearchHandler struct {
+	searchRepo *repository.SearchRepository
+	folderRepo *repository.FolderRepository
+}
+
+func NewSearchHandler(searchRepo *repository.SearchRepository, folderRepo *repository.FolderRepository) *SearchHandler {
+	return &SearchHandler{
+		searchRepo: searchRepo,
+		folderRepo: folderRepo,
+	}
+}
+
+// parsePagination reads limit/offset query parameters, clamping them to sane bounds
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (int, int) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// Search handles full-text transcript search (/api/search?q=)
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "Search query 'q' is required.",
+		})
+		return
+	}
+
+	limit, offset := parsePagination(c, defaultSearchLimit, maxSearchLimit)
+	scope := repository.SearchScope{
+		FolderID: c.Query("folder_id"),
+		FileID:   c.Query("file_id"),
+	}
+
+	hits, total, err := h.searchRepo.SearchSegments(userID, query, scope, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to search transcripts. Please try again later.",
+		})
+		return
+	}
+
+	resolver := newBreadcrumbResolver(h.folderRepo, userID)
+	for i := range hits {
+		hits[i].Breadcrumbs, err = resolver.resolve(hits[i].FolderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to resolve folder locations. Please try again later.",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SearchResponse{
+		Query:   query,
+		Results: hits,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// breadcrumbResolver looks up folder paths for search results, hitting the database once per folder
+type breadcrumbResolver struct {
+	folderRepo *repository.FolderRepository
+	userID     string
+	cache      map[string][]models.Breadcrumb
+}
+
+func newBreadcrumbResolver(folderRepo *repository.FolderRepository, userID string) *breadcrumbResolver {
+	return &breadcrumbResolver{
+		folderRepo: folderRepo,
+		userID:     userID,
+		cache:      make(map[string][]models.Breadcrumb),
+	}
+}
+
+func (r *breadcrumbResolver) resolve(folderID *string) ([]models.Breadcrumb, error) {
+	id := ""
+	if folderID != nil {
+		id = *folderID
+	}
+
+	if breadcrumbs, cached := r.cache[id]; cached {
+		return breadcrumbs, nil
+	}
+
+	breadcrumbs, err := r.folderRepo.GetBreadcrumbs(id, r.userID)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[id] = breadcrumbs
+	return breadcrumbs, nil
+}
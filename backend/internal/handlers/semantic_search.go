@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/semantic"
+)
+
+const (
+	searchModeSemantic = "semantic"
+	searchModeHybrid   = "hybrid"
+
+	defaultMinSimilarity = 0.2
+)
+
+type SemanticSearchHandler struct {
+	searchRepo     *repository.SearchRepository
+	embeddingRepo  *repository.EmbeddingRepository
+	transcriptRepo *repository.TranscriptRepository
+	folderRepo     *repository.FolderRepository
+	embedder       semantic.Embedder
+	indexer        *semantic.Indexer
+}
+
+func NewSemanticSearchHandler(
+	searchRepo *repository.SearchRepository,
+	embeddingRepo *repository.EmbeddingRepository,
+	transcriptRepo *repository.TranscriptRepository,
+	folderRepo *repository.FolderRepository,
+	embedder semantic.Embedder,
+) *SemanticSearchHandler {
+	return &SemanticSearchHandler{
+		searchRepo:     searchRepo,
+		embeddingRepo:  embeddingRepo,
+		transcriptRepo: transcriptRepo,
+		folderRepo:     folderRepo,
+		embedder:       embedder,
+		indexer:        semantic.NewIndexer(embedder, embeddingRepo),
+	}
+}
+
+// IndexFile (re)builds the embeddings for a file's transcript
+func (h *SemanticSearchHandler) IndexFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	chunks, err := h.indexer.IndexTranscript(c.Request.Context(), transcript)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Indexing failed",
+			"message": "Unable to index transcript for semantic search. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transcript_id": transcript.ID,
+		"chunks":        chunks,
+	})
+}
+
+// Search handles semantic transcript search (/api/search/semantic?q=), optionally fused with keyword hits
+func (h *SemanticSearchHandler) Search(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "Search query 'q' is required.",
+		})
+		return
+	}
+
+	mode := strings.ToLower(c.DefaultQuery("mode", searchModeHybrid))
+	if mode != searchModeSemantic && mode != searchModeHybrid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "Search mode must be 'semantic' or 'hybrid'.",
+		})
+		return
+	}
+
+	minSimilarity := defaultMinSimilarity
+	if raw := c.Query("min_similarity"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < -1 || value > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"message": "'min_similarity' must be a number between -1 and 1.",
+			})
+			return
+		}
+		minSimilarity = value
+	}
+
+	limit, _ := parsePagination(c, defaultSearchLimit, maxSearchLimit)
+	scope := repository.SearchScope{
+		FolderID: c.Query("folder_id"),
+		FileID:   c.Query("file_id"),
+	}
+
+	vectors, err := h.embedder.Embed(c.Request.Context(), []string{query})
+	if err != nil || len(vectors) != 1 {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Embedding failed",
+			"message": "Unable to process the search query. Please try again later.",
+		})
+		return
+	}
+
+	// Over-fetch candidates so fusion has room to reorder before truncating
+	candidates := limit
+	if mode == searchModeHybrid {
+		candidates = limit * 3
+	}
+
+	hits, err := h.embeddingRepo.SearchSimilar(userID, vectors[0], scope, candidates, minSimilarity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to search transcripts. Please try again later.",
+		})
+		return
+	}
+
+	if mode == searchModeHybrid {
+		keywordHits, _, err := h.searchRepo.SearchSegments(userID, query, scope, candidates, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to search transcripts. Please try again later.",
+			})
+			return
+		}
+		hits = semantic.FuseHybrid(hits, keywordHits)
+	}
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	resolver := newBreadcrumbResolver(h.folderRepo, userID)
+	for i := range hits {
+		hits[i].Breadcrumbs, err = resolver.resolve(hits[i].FolderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to resolve folder locations. Please try again later.",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SemanticSearchResponse{
+		Query:   query,
+		Mode:    mode,
+		Results: hits,
+		Total:   len(hits),
+	})
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+const (
+	maxSmartFolderNameLength   = 255
+	maxSmartFolderFilterValues = 20
+	maxSmartFolderTextLength   = 200
+)
+
+type SmartFolderHandler struct {
+	smartFolderRepo *repository.SmartFolderRepository
+	folderRepo      *repository.FolderRepository
+}
+
+func NewSmartFolderHandler(smartFolderRepo *repository.SmartFolderRepository, folderRepo *repository.FolderRepository) *SmartFolderHandler {
+	return &SmartFolderHandler{
+		smartFolderRepo: smartFolderRepo,
+		folderRepo:      folderRepo,
+	}
+}
+
+type CreateSmartFolderRequest struct {
+	Name     string                    `json:"name" binding:"required"`
+	ParentID *string                   `json:"parent_id"`
+	Color    *string                   `json:"color"`
+	Icon     *string                   `json:"icon"`
+	Filters  models.SmartFolderFilters `json:"filters"`
+}
+
+type UpdateSmartFolderRequest struct {
+	// Each field is optional; an empty parent_id moves the smart folder to the root and an empty
+	// color or icon clears it
+	Name     *string                    `json:"name"`
+	ParentID *string                    `json:"parent_id"`
+	Color    *string                    `json:"color"`
+	Icon     *string                    `json:"icon"`
+	Filters  *models.SmartFolderFilters `json:"filters"`
+}
+
+// ListSmartFolders returns the current user's smart folders
+func (h *SmartFolderHandler) ListSmartFolders(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	smartFolders, err := h.smartFolderRepo.ListSmartFolders(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve smart folders. Please try again later.",
+		})
+		return
+	}
+
+	respondCacheable(c, gin.H{
+		"smart_folders": smartFolders,
+	})
+}
+
+// CreateSmartFolder saves a search as a smart folder
+func (h *SmartFolderHandler) CreateSmartFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateSmartFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Smart folder name is required and must be valid.",
+		})
+		return
+	}
+
+	name, message := normalizeSmartFolderName(req.Name)
+	if message == "" {
+		message = normalizeSmartFolderAppearance(req.Color, req.Icon)
+	}
+	if message == "" {
+		message = normalizeSmartFolderFilters(&req.Filters)
+	}
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": message,
+		})
+		return
+	}
+
+	var parentID *string
+	if req.ParentID != nil && strings.TrimSpace(*req.ParentID) != "" {
+		id := strings.TrimSpace(*req.ParentID)
+		parentID = &id
+	}
+	var color, icon *string
+	if req.Color != nil && *req.Color != "" {
+		color = req.Color
+	}
+	if req.Icon != nil && *req.Icon != "" {
+		icon = req.Icon
+	}
+
+	smartFolder, err := h.smartFolderRepo.CreateSmartFolder(userID, name, parentID, color, icon, req.Filters)
+	if err != nil {
+		respondSmartFolderError(c, err, "Unable to create smart folder. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusCreated, smartFolder)
+}
+
+// GetSmartFolder returns a smart folder with one page of the files matching its filters. It takes
+// the same sort, order, limit, cursor and filter parameters as a folder listing.
+func (h *SmartFolderHandler) GetSmartFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	smartFolder, err := h.smartFolderRepo.GetSmartFolder(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve smart folder. Please try again later.",
+		})
+		return
+	}
+	if smartFolder == nil {
+		respondSmartFolderNotFound(c)
+		return
+	}
+
+	query, ok := parseFolderListQuery(c)
+	if !ok {
+		return
+	}
+	query.Smart = &smartFolder.Filters
+
+	page, err := h.folderRepo.GetFolderContents("", userID, query)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid cursor") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid cursor",
+				"message": "The cursor is malformed or was issued for a different sort order.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve smart folder contents. Please try again later.",
+		})
+		return
+	}
+
+	response := models.SmartFolderDataResponse{
+		SmartFolder: smartFolder,
+		Contents:    page.Contents,
+		NextCursor:  page.NextCursor,
+	}
+	response.Stats.TotalFiles = page.TotalFiles
+	response.Stats.TotalFolders = page.TotalFolders
+
+	respondCacheable(c, response)
+}
+
+// UpdateSmartFolder renames, moves or changes the filters of a smart folder
+func (h *SmartFolderHandler) UpdateSmartFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSmartFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must be valid JSON.",
+		})
+		return
+	}
+	if req.Name == nil && req.ParentID == nil && req.Color == nil && req.Icon == nil && req.Filters == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Provide at least one of 'name', 'parent_id', 'color', 'icon' or 'filters'.",
+		})
+		return
+	}
+
+	update := repository.SmartFolderUpdate{
+		Color:   req.Color,
+		Icon:    req.Icon,
+		Filters: req.Filters,
+	}
+	message := normalizeSmartFolderAppearance(req.Color, req.Icon)
+	if message == "" && req.Name != nil {
+		var name string
+		name, message = normalizeSmartFolderName(*req.Name)
+		update.Name = &name
+	}
+	if message == "" && req.Filters != nil {
+		message = normalizeSmartFolderFilters(req.Filters)
+	}
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": message,
+		})
+		return
+	}
+	if req.ParentID != nil {
+		parentID := strings.TrimSpace(*req.ParentID)
+		update.ParentID = &parentID
+	}
+
+	smartFolder, err := h.smartFolderRepo.UpdateSmartFolder(c.Param("id"), userID, update)
+	if err != nil {
+		respondSmartFolderError(c, err, "Unable to update smart folder. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, smartFolder)
+}
+
+// DeleteSmartFolder deletes a smart folder, leaving the files it listed alone
+func (h *SmartFolderHandler) DeleteSmartFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.smartFolderRepo.DeleteSmartFolder(c.Param("id"), userID); err != nil {
+		respondSmartFolderError(c, err, "Unable to delete smart folder. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Smart folder deleted successfully",
+	})
+}
+
+// normalizeSmartFolderName trims a smart folder name, returning a validation message when it is
+// empty or too long
+func normalizeSmartFolderName(raw string) (string, string) {
+	name := strings.TrimSpace(raw)
+	if name == "" || utf8.RuneCountInString(name) > maxSmartFolderNameLength {
+		return "", "Smart folder name must be between 1 and 255 characters."
+	}
+	return name, ""
+}
+
+// normalizeSmartFolderAppearance trims and checks a color and icon in place, with the same rules
+// as folder metadata
+func normalizeSmartFolderAppearance(color, icon *string) string {
+	if color != nil {
+		*color = strings.ToLower(strings.TrimSpace(*color))
+		if *color != "" && !folderColorPattern.MatchString(*color) {
+			return "Color must be a hex color like #3b82f6."
+		}
+	}
+	if icon != nil {
+		*icon = strings.TrimSpace(*icon)
+		if utf8.RuneCountInString(*icon) > maxFolderIconLength {
+			return "Icon must be at most 16 characters."
+		}
+	}
+	return ""
+}
+
+// normalizeSmartFolderFilters trims and lowercases filter values in place, returning a validation
+// message when the filters are invalid
+func normalizeSmartFolderFilters(filters *models.SmartFolderFilters) string {
+	if filters.FolderID != nil {
+		folderID := strings.TrimSpace(*filters.FolderID)
+		filters.FolderID = nil
+		if folderID != "" {
+			filters.FolderID = &folderID
+		}
+	}
+
+	lists := []struct {
+		values *[]string
+		field  string
+	}{
+		{&filters.Languages, "languages"},
+		{&filters.Tags, "tags"},
+		{&filters.Services, "services"},
+	}
+	for _, list := range lists {
+		var values []string
+		seen := make(map[string]bool)
+		for _, value := range *list.values {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value == "" || seen[value] {
+				continue
+			}
+			seen[value] = true
+			values = append(values, value)
+		}
+		if len(values) > maxSmartFolderFilterValues {
+			return "'" + list.field + "' may have at most 20 values."
+		}
+		*list.values = values
+	}
+
+	if (filters.MinDurationMs != nil && *filters.MinDurationMs < 0) || (filters.MaxDurationMs != nil && *filters.MaxDurationMs < 0) {
+		return "Durations must not be negative."
+	}
+	if filters.MinDurationMs != nil && filters.MaxDurationMs != nil && *filters.MinDurationMs > *filters.MaxDurationMs {
+		return "'min_duration_ms' must not be greater than 'max_duration_ms'."
+	}
+
+	if filters.CreatedAfter != nil && filters.CreatedBefore != nil && !filters.CreatedAfter.Before(*filters.CreatedBefore) {
+		return "'created_after' must be before 'created_before'."
+	}
+	filters.CreatedWithin = models.SmartFolderPeriod(strings.ToLower(strings.TrimSpace(string(filters.CreatedWithin))))
+	switch filters.CreatedWithin {
+	case "", models.SmartFolderPeriodToday, models.SmartFolderPeriodThisWeek, models.SmartFolderPeriodThisMonth,
+		models.SmartFolderPeriodThisQuarter, models.SmartFolderPeriodThisYear:
+	default:
+		return "'created_within' must be one of today, this_week, this_month, this_quarter or this_year."
+	}
+
+	filters.Text = strings.TrimSpace(filters.Text)
+	if utf8.RuneCountInString(filters.Text) > maxSmartFolderTextLength {
+		return "'text' must be at most 200 characters."
+	}
+
+	return ""
+}
+
+func respondSmartFolderNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error":   "Smart folder not found",
+		"message": "The requested smart folder does not exist or you don't have access to it.",
+	})
+}
+
+func respondSmartFolderError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "smart folder not found"):
+		respondSmartFolderNotFound(c)
+	case strings.Contains(err.Error(), "folder not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Folder not found",
+			"message": "The specified folder does not exist or you don't have access to it.",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": message,
+		})
+	}
+}
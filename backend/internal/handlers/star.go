@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type StarHandler struct {
+	starRepo *repository.StarRepository
+}
+
+func NewStarHandler(starRepo *repository.StarRepository) *StarHandler {
+	return &StarHandler{
+		starRepo: starRepo,
+	}
+}
+
+// StarFolder stars a folder for the current user
+func (h *StarHandler) StarFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.starRepo.StarFolder(c.Param("id"), userID); err != nil {
+		respondStarError(c, err, "Folder not found", "The specified folder does not exist or you don't have access to it.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Folder starred",
+	})
+}
+
+// UnstarFolder removes the current user's star from a folder
+func (h *StarHandler) UnstarFolder(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.starRepo.UnstarFolder(c.Param("id"), userID); err != nil {
+		respondStarError(c, err, "Folder not found", "The specified folder does not exist or you don't have access to it.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Folder unstarred",
+	})
+}
+
+// StarFile stars a file for the current user
+func (h *StarHandler) StarFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.starRepo.StarFile(c.Param("id"), userID); err != nil {
+		respondStarError(c, err, "File not found", "The requested file does not exist or you don't have access to it.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "File starred",
+	})
+}
+
+// UnstarFile removes the current user's star from a file
+func (h *StarHandler) UnstarFile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.starRepo.UnstarFile(c.Param("id"), userID); err != nil {
+		respondStarError(c, err, "File not found", "The requested file does not exist or you don't have access to it.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "File unstarred",
+	})
+}
+
+// GetQuickAccess returns the sidebar's pinned folders and starred folders and files
+func (h *StarHandler) GetQuickAccess(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	quickAccess, err := h.starRepo.GetQuickAccess(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve quick access items. Please try again later.",
+		})
+		return
+	}
+
+	respondCacheable(c, quickAccess)
+}
+
+func respondStarError(c *gin.Context, err error, notFound, notFoundMessage string) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   notFound,
+			"message": notFoundMessage,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Database error",
+		"message": "Unable to update star. Please try again later.",
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+type SummaryHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	summaryRepo    *repository.SummaryRepository
+	jobRepo        *repository.JobRepository
+}
+
+func NewSummaryHandler(transcriptRepo *repository.TranscriptRepository, summaryRepo *repository.SummaryRepository, jobRepo *repository.JobRepository) *SummaryHandler {
+	return &SummaryHandler{
+		transcriptRepo: transcriptRepo,
+		summaryRepo:    summaryRepo,
+		jobRepo:        jobRepo,
+	}
+}
+
+// GetSummary returns the stored summary, key points and action items for a file's transcript
+func (h *SummaryHandler) GetSummary(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	summary, err := h.summaryRepo.GetSummary(transcript.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve summary. Please try again later.",
+		})
+		return
+	}
+	if summary == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Summary not found",
+			"message": "This transcript has not been summarized yet.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// CreateSummary queues a summarize job for a file's transcript
+func (h *SummaryHandler) CreateSummary(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	fileID := c.Param("id")
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(fileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	// Reuse an in-flight job instead of summarizing the same transcript twice
+	job, err := h.jobRepo.GetActiveJob(fileID, userID, models.JobTypeSummarize)
+	if err == nil && job == nil {
+		job, err = h.jobRepo.CreateJob(userID, &fileID, models.JobTypeSummarize, nil)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to queue summary. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
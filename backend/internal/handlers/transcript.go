@@ -0,0 +1,499 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/export"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/redact"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/translate"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+type TranscriptHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	redactor       *redact.Redactor
+}
+
+func NewTranscriptHandler(transcriptRepo *repository.TranscriptRepository, redactor *redact.Redactor) *TranscriptHandler {
+	return &TranscriptHandler{
+		transcriptRepo: transcriptRepo,
+		redactor:       redactor,
+	}
+}
+
+// loadTranscript fetches the transcript for the :id file, writing the error response itself on failure
+func (h *TranscriptHandler) loadTranscript(c *gin.Context, userID string) (*models.Transcript, bool) {
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// loadTranslation fetches the :id file's translation into lang, writing the error response itself on failure
+func (h *TranscriptHandler) loadTranslation(c *gin.Context, userID, lang string) (*models.Transcript, bool) {
+	language, err := translate.NormalizeLanguage(lang)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid language",
+			"message": "Language must be a code such as 'es' or 'pt-BR'.",
+		})
+		return nil, false
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptVariant(c.Param("id"), userID, language)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve translation. Please try again later.",
+		})
+		return nil, false
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Translation not found",
+			"message": "This transcript has not been translated into that language.",
+		})
+		return nil, false
+	}
+	return transcript, true
+}
+
+// GetTranscript returns a file's transcript with speaker names resolved from the roster.
+// ?lang= returns a stored translation instead of the original.
+func (h *TranscriptHandler) GetTranscript(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var transcript *models.Transcript
+	if lang := c.Query("lang"); lang != "" {
+		transcript, ok = h.loadTranslation(c, userID, lang)
+	} else {
+		transcript, ok = h.loadTranscript(c, userID)
+	}
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
+}
+
+// ExportTranscript streams the transcript as txt, srt, vtt or json. ?lang= exports a
+// translation instead; adding bilingual=true pairs each original line with its translation.
+// redact=true replaces PII with placeholders in everything exported and leaves out chapters, whose
+// titles come from the raw text. track=chapters exports the chapters as a WebVTT chapters track.
+func (h *TranscriptHandler) ExportTranscript(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid export format",
+			"message": "Supported formats are txt, srt, vtt and json.",
+		})
+		return
+	}
+
+	chapterTrack := c.Query("track") == "chapters"
+	redact := c.Query("redact") == "true"
+	if chapterTrack && (format != export.FormatVTT || redact) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid export format",
+			"message": "Chapter tracks are only available as unredacted vtt.",
+		})
+		return
+	}
+
+	lang := c.Query("lang")
+	bilingual := c.Query("bilingual") == "true"
+	if bilingual && lang == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Bilingual exports require a 'lang' to pair with the original.",
+		})
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	if chapterTrack {
+		if len(transcript.Chapters) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Chapters not found",
+				"message": "This transcript has not been chaptered yet.",
+			})
+			return
+		}
+
+		c.Header("Content-Type", format.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="chapters-%s.vtt"`, transcript.FileID))
+		c.Status(http.StatusOK)
+		if err := export.WriteChapters(c.Writer, transcript); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	var translation *models.Transcript
+	if lang != "" {
+		translation, ok = h.loadTranslation(c, userID, lang)
+		if !ok {
+			return
+		}
+	}
+
+	if redact {
+		for _, t := range []*models.Transcript{transcript, translation} {
+			if t == nil {
+				continue
+			}
+			if err := h.redactor.Redacted(c.Request.Context(), userID, t); err != nil {
+				log.Printf("Error redacting export for file %s: %v", transcript.FileID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "Redaction failed",
+					"message": "Unable to redact the transcript. Please try again later.",
+				})
+				return
+			}
+		}
+	}
+
+	filename := fmt.Sprintf("transcript-%s.%s", transcript.FileID, format)
+	if translation != nil {
+		filename = fmt.Sprintf("transcript-%s.%s.%s", transcript.FileID, *translation.Language, format)
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	switch {
+	case bilingual:
+		err = export.WriteBilingual(c.Writer, transcript, translation, format)
+	case translation != nil:
+		err = export.Write(c.Writer, translation, format)
+	default:
+		err = export.Write(c.Writer, transcript, format)
+	}
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+// GetSpeakers returns the speaker roster for a file's transcript
+func (h *TranscriptHandler) GetSpeakers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"speakers": transcript.Speakers,
+	})
+}
+
+type UpdateSpeakerRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// UpdateSpeaker renames a speaker or changes its color
+func (h *TranscriptHandler) UpdateSpeaker(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Name == nil && req.Color == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'name' and/or 'color'.",
+		})
+		return
+	}
+
+	if req.Name != nil {
+		trimmed := strings.TrimSpace(*req.Name)
+		req.Name = &trimmed
+		if len(trimmed) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Speaker name must be less than 100 characters.",
+			})
+			return
+		}
+	}
+
+	if req.Color != nil && !hexColorPattern.MatchString(*req.Color) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Speaker color must be a hex color such as #2563EB.",
+		})
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	speaker, err := h.transcriptRepo.UpdateSpeaker(transcript.ID, c.Param("speakerId"), req.Name, req.Color)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Speaker not found",
+				"message": "The specified speaker does not exist in this transcript.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to update speaker. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, speaker)
+}
+
+type MergeSpeakersRequest struct {
+	SourceID string `json:"source_id" binding:"required"`
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+// MergeSpeakers folds one speaker into another across the whole transcript
+func (h *TranscriptHandler) MergeSpeakers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MergeSpeakersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'source_id' and 'target_id'.",
+		})
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	speaker, err := h.transcriptRepo.MergeSpeakers(transcript.ID, req.SourceID, req.TargetID)
+	if err != nil {
+		if strings.Contains(err.Error(), "itself") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid merge operation",
+				"message": "Cannot merge a speaker into itself.",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Speaker not found",
+				"message": "Both speakers must exist in this transcript.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to merge speakers. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, speaker)
+}
+
+type SplitSpeakerRequest struct {
+	StartMs *int64  `json:"start_ms" binding:"required"`
+	EndMs   *int64  `json:"end_ms" binding:"required"`
+	Name    *string `json:"name"`
+}
+
+// SplitSpeaker moves a speaker's segments within a time range to a new speaker
+func (h *TranscriptHandler) SplitSpeaker(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SplitSpeakerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'start_ms' and 'end_ms'.",
+		})
+		return
+	}
+
+	if *req.StartMs < 0 || *req.EndMs <= *req.StartMs {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "'end_ms' must be after 'start_ms', and both must be non-negative.",
+		})
+		return
+	}
+
+	if req.Name != nil {
+		trimmed := strings.TrimSpace(*req.Name)
+		req.Name = &trimmed
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	result, err := h.transcriptRepo.SplitSpeaker(transcript.ID, c.Param("speakerId"), *req.StartMs, *req.EndMs, req.Name)
+	if err != nil {
+		if strings.Contains(err.Error(), "no segments") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Nothing to split",
+				"message": "This speaker has no segments starting in the given time range.",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Speaker not found",
+				"message": "The specified speaker does not exist in this transcript.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to split speaker. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+type UpdateSegmentRequest struct {
+	Text    *string `json:"text"`
+	StartMs *int64  `json:"start_ms"`
+	EndMs   *int64  `json:"end_ms"`
+}
+
+// UpdateSegment corrects a segment's text or timing, keeping the previous version as a revision
+func (h *TranscriptHandler) UpdateSegment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Text == nil && req.StartMs == nil && req.EndMs == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'text', 'start_ms' and/or 'end_ms'.",
+		})
+		return
+	}
+
+	if req.Text != nil {
+		trimmed := strings.TrimSpace(*req.Text)
+		req.Text = &trimmed
+		if trimmed == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Segment text cannot be empty.",
+			})
+			return
+		}
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	segment, err := h.transcriptRepo.UpdateSegment(transcript.ID, c.Param("segmentId"), userID, req.Text, req.StartMs, req.EndMs)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid segment timing") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "'end_ms' must be after 'start_ms', and both must be non-negative.",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Segment not found",
+				"message": "The specified segment does not exist in this transcript.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to update segment. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, segment)
+}
+
+// GetSegmentRevisions returns a segment's edit history
+func (h *TranscriptHandler) GetSegmentRevisions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	transcript, ok := h.loadTranscript(c, userID)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.transcriptRepo.GetSegmentRevisions(transcript.ID, c.Param("segmentId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve revisions. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+	})
+}
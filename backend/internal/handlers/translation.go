@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/translate"
+)
+
+type TranslationHandler struct {
+	transcriptRepo *repository.TranscriptRepository
+	jobRepo        *repository.JobRepository
+}
+
+func NewTranslationHandler(transcriptRepo *repository.TranscriptRepository, jobRepo *repository.JobRepository) *TranslationHandler {
+	return &TranslationHandler{
+		transcriptRepo: transcriptRepo,
+		jobRepo:        jobRepo,
+	}
+}
+
+type CreateTranslationRequest struct {
+	TargetLanguage string `json:"target_language" binding:"required"`
+}
+
+// ListTranslations returns the languages a file's transcript has been translated into
+func (h *TranslationHandler) ListTranslations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := h.transcriptRepo.ListTranscriptVariants(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve translations. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"translations": translations,
+	})
+}
+
+// CreateTranslation queues a translate job for a file's transcript
+func (h *TranslationHandler) CreateTranslation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include 'target_language'.",
+		})
+		return
+	}
+
+	target, err := translate.NormalizeLanguage(req.TargetLanguage)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid language",
+			"message": "Language must be a code such as 'es' or 'pt-BR'.",
+		})
+		return
+	}
+
+	fileID := c.Param("id")
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(fileID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	if transcript.Language != nil && translate.BaseLanguage(*transcript.Language) == translate.BaseLanguage(target) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "The transcript is already in that language.",
+		})
+		return
+	}
+
+	// Reuse an in-flight job instead of translating into the same language twice
+	payload := models.TranslatePayload{TargetLanguage: target}
+	job, err := h.jobRepo.GetActiveJobWithPayload(fileID, userID, models.JobTypeTranslate, payload)
+	if err == nil && job == nil {
+		job, err = h.jobRepo.CreateJob(userID, &fileID, models.JobTypeTranslate, payload)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to queue translation. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/storage"
+	"github.com/mouizahmed/justscribe-backend/internal/trash"
+)
+
+const (
+	defaultTrashLimit = 50
+	maxTrashLimit     = 200
+)
+
+type TrashHandler struct {
+	trashRepo *repository.TrashRepository
+	userRepo  *repository.UserRepository
+	store     storage.Storage
+}
+
+func NewTrashHandler(trashRepo *repository.TrashRepository, userRepo *repository.UserRepository, store storage.Storage) *TrashHandler {
+	return &TrashHandler{
+		trashRepo: trashRepo,
+		userRepo:  userRepo,
+		store:     store,
+	}
+}
+
+type RestoreRequest struct {
+	// ToRoot restores the item to the root instead of recreating its deleted parent folders
+	ToRoot bool `json:"to_root"`
+}
+
+// ListTrash lists the user's deleted folders and files with where they were deleted from and when
+// they will be purged
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve user information. Please try again later.",
+		})
+		return
+	}
+
+	limit, offset := parsePagination(c, defaultTrashLimit, maxTrashLimit)
+	items, total, err := h.trashRepo.ListTrash(userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve trash. Please try again later.",
+		})
+		return
+	}
+
+	retention := user.Plan.TrashRetention()
+	for i := range items {
+		items[i].ExpiresAt = items[i].DeletedAt.Add(retention)
+	}
+
+	c.JSON(http.StatusOK, models.TrashListResponse{
+		Items:         items,
+		Total:         total,
+		Limit:         limit,
+		Offset:        offset,
+		RetentionDays: int(retention.Hours() / 24),
+	})
+}
+
+// RestoreFolder restores a folder and everything deleted with it
+func (h *TrashHandler) RestoreFolder(c *gin.Context) {
+	h.restore(c, h.trashRepo.RestoreFolder)
+}
+
+// RestoreFile restores a single file
+func (h *TrashHandler) RestoreFile(c *gin.Context) {
+	h.restore(c, h.trashRepo.RestoreFile)
+}
+
+func (h *TrashHandler) restore(c *gin.Context, restore func(id, userID string, toRoot bool) (*models.RestoreResult, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; without one the item goes back where it was
+	var req RestoreRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"message": "Request body must be valid JSON.",
+			})
+			return
+		}
+	}
+
+	result, err := restore(c.Param("id"), userID, req.ToRoot)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Item not found",
+				"message": "The requested item is not in your trash.",
+			})
+		case strings.Contains(err.Error(), "already exists"):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Name conflict",
+				"message": "An item with the same name already exists in the original location. Rename it or restore to the root.",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to restore item. Please try again later.",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteFolder permanently deletes a trashed folder and everything below it
+func (h *TrashHandler) DeleteFolder(c *gin.Context) {
+	h.purge(c, h.trashRepo.DeleteFolderPermanently)
+}
+
+// DeleteFile permanently deletes a trashed file
+func (h *TrashHandler) DeleteFile(c *gin.Context) {
+	h.purge(c, h.trashRepo.DeleteFilePermanently)
+}
+
+// EmptyTrash permanently deletes everything in the user's trash
+func (h *TrashHandler) EmptyTrash(c *gin.Context) {
+	h.purge(c, func(_, userID string) (*models.PurgeResult, error) {
+		return h.trashRepo.EmptyTrash(userID)
+	})
+}
+
+func (h *TrashHandler) purge(c *gin.Context, purge func(id, userID string) (*models.PurgeResult, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := purge(c.Param("id"), userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Item not found",
+				"message": "The requested item is not in your trash.",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to permanently delete. Please try again later.",
+		})
+		return
+	}
+
+	trash.DeleteMedia(c.Request.Context(), h.store, result.StorageKeys)
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/transcribe"
+)
+
+const (
+	maxVocabularyTermLength = 100
+	maxSoundsLike           = 20
+	maxVocabularyBoost      = 10
+	defaultVocabularyBoost  = 1
+)
+
+type VocabularyHandler struct {
+	vocabularyRepo *repository.VocabularyRepository
+	transcriptRepo *repository.TranscriptRepository
+	fileRepo       *repository.FileRepository
+	userRepo       *repository.UserRepository
+}
+
+func NewVocabularyHandler(vocabularyRepo *repository.VocabularyRepository, transcriptRepo *repository.TranscriptRepository, fileRepo *repository.FileRepository, userRepo *repository.UserRepository) *VocabularyHandler {
+	return &VocabularyHandler{
+		vocabularyRepo: vocabularyRepo,
+		transcriptRepo: transcriptRepo,
+		fileRepo:       fileRepo,
+		userRepo:       userRepo,
+	}
+}
+
+type CreateVocabularyTermRequest struct {
+	Term       string   `json:"term" binding:"required"`
+	FolderID   *string  `json:"folder_id"`
+	SoundsLike []string `json:"sounds_like"`
+	Boost      *float64 `json:"boost"`
+}
+
+type UpdateVocabularyTermRequest struct {
+	Term       *string  `json:"term"`
+	SoundsLike []string `json:"sounds_like"`
+	Boost      *float64 `json:"boost"`
+}
+
+// requirePlan checks the user's plan includes custom vocabulary, writing the error response itself if not
+func (h *VocabularyHandler) requirePlan(c *gin.Context, userID string) bool {
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "User not found",
+				"message": "User account not found.",
+			})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve user information. Please try again later.",
+		})
+		return false
+	}
+	if !user.Plan.HasCustomVocabulary() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Plan upgrade required",
+			"message": "Custom vocabulary is available on the Professional and Business plans.",
+		})
+		return false
+	}
+	return true
+}
+
+// folderQuery reads the optional folder_id query parameter
+func folderQuery(c *gin.Context) *string {
+	if folderID := c.Query("folder_id"); folderID != "" {
+		return &folderID
+	}
+	return nil
+}
+
+// ListTerms returns the account vocabulary, or a folder's own terms (folder_id)
+func (h *VocabularyHandler) ListTerms(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	folderID := folderQuery(c)
+	terms, err := h.vocabularyRepo.ListTerms(userID, folderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve vocabulary. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"folder_id": folderID,
+		"terms":     terms,
+	})
+}
+
+// GetEffectiveTerms returns the vocabulary transcription uses for a folder: the account terms plus
+// those inherited from the folder and its ancestors
+func (h *VocabularyHandler) GetEffectiveTerms(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	folderID := folderQuery(c)
+	terms, err := h.vocabularyRepo.EffectiveTerms(userID, folderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve vocabulary. Please try again later.",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"folder_id": folderID,
+		"terms":     terms,
+	})
+}
+
+// CreateTerm adds a term to the account vocabulary, or to a folder's when folder_id is given
+func (h *VocabularyHandler) CreateTerm(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateVocabularyTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must include a non-empty 'term'.",
+		})
+		return
+	}
+
+	term, ok := validateVocabularyTerm(c, req.Term)
+	if !ok {
+		return
+	}
+	soundsLike, ok := validateSoundsLike(c, req.SoundsLike)
+	if !ok {
+		return
+	}
+	boost := float64(defaultVocabularyBoost)
+	if req.Boost != nil {
+		if !validateBoost(c, *req.Boost) {
+			return
+		}
+		boost = *req.Boost
+	}
+	if req.FolderID != nil && *req.FolderID == "" {
+		req.FolderID = nil
+	}
+
+	if !h.requirePlan(c, userID) {
+		return
+	}
+
+	created, err := h.vocabularyRepo.CreateTerm(userID, req.FolderID, term, soundsLike, boost)
+	if err != nil {
+		respondVocabularyError(c, err, "Unable to create vocabulary term. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateTerm changes a term's spelling, sounds-like hints or boost
+func (h *VocabularyHandler) UpdateTerm(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateVocabularyTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Request body must be valid JSON.",
+		})
+		return
+	}
+	if req.Term == nil && req.SoundsLike == nil && req.Boost == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"message": "Provide at least one of 'term', 'sounds_like' or 'boost'.",
+		})
+		return
+	}
+
+	if req.Term != nil {
+		term, ok := validateVocabularyTerm(c, *req.Term)
+		if !ok {
+			return
+		}
+		req.Term = &term
+	}
+	if req.SoundsLike != nil {
+		soundsLike, ok := validateSoundsLike(c, req.SoundsLike)
+		if !ok {
+			return
+		}
+		req.SoundsLike = soundsLike
+	}
+	if req.Boost != nil && !validateBoost(c, *req.Boost) {
+		return
+	}
+
+	if !h.requirePlan(c, userID) {
+		return
+	}
+
+	updated, err := h.vocabularyRepo.UpdateTerm(c.Param("id"), userID, req.Term, req.SoundsLike, req.Boost)
+	if err != nil {
+		respondVocabularyError(c, err, "Unable to update vocabulary term. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteTerm removes a vocabulary term. Deleting stays available after a downgrade.
+func (h *VocabularyHandler) DeleteTerm(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.vocabularyRepo.DeleteTerm(c.Param("id"), userID); err != nil {
+		respondVocabularyError(c, err, "Unable to delete vocabulary term. Please try again later.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Vocabulary term deleted successfully",
+	})
+}
+
+// ApplyVocabulary normalizes an existing transcript to the glossary spelling of the vocabulary that
+// applies to the file's folder, recording a revision for every segment it changes
+func (h *VocabularyHandler) ApplyVocabulary(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if !h.requirePlan(c, userID) {
+		return
+	}
+
+	file, err := h.fileRepo.GetFileByID(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve file. Please try again later.",
+		})
+		return
+	}
+	if file == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "File not found",
+			"message": "The requested file does not exist or you don't have access to it.",
+		})
+		return
+	}
+
+	transcript, err := h.transcriptRepo.GetTranscriptByFileID(file.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve transcript. Please try again later.",
+		})
+		return
+	}
+	if transcript == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Transcript not found",
+			"message": "The requested file has no transcript or you don't have access to it.",
+		})
+		return
+	}
+
+	terms, err := h.vocabularyRepo.EffectiveTerms(userID, file.FolderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": "Unable to retrieve vocabulary. Please try again later.",
+		})
+		return
+	}
+
+	glossary := transcribe.NewGlossary(transcribe.HintsFor(terms))
+	var edits []models.Segment
+	corrections := 0
+	for _, segment := range transcript.Segments {
+		if n := glossary.Correct(&segment); n > 0 {
+			corrections += n
+			edits = append(edits, segment)
+		}
+	}
+
+	if len(edits) > 0 {
+		if err := h.transcriptRepo.ApplySegmentEdits(transcript.ID, userID, edits); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Database error",
+				"message": "Unable to save the corrected transcript. Please try again later.",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"file_id":          file.ID,
+		"terms":            len(terms),
+		"corrections":      corrections,
+		"segments_changed": len(edits),
+	})
+}
+
+func validateVocabularyTerm(c *gin.Context, raw string) (string, bool) {
+	term := strings.Join(strings.Fields(raw), " ")
+	if term == "" || len(term) > maxVocabularyTermLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Term must be between 1 and 100 characters.",
+		})
+		return "", false
+	}
+	return term, true
+}
+
+// validateSoundsLike trims the sounds-like hints and drops blanks
+func validateSoundsLike(c *gin.Context, raw []string) ([]string, bool) {
+	soundsLike := make([]string, 0, len(raw))
+	for _, variant := range raw {
+		variant = strings.Join(strings.Fields(variant), " ")
+		if variant == "" {
+			continue
+		}
+		if len(variant) > maxVocabularyTermLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Validation failed",
+				"message": "Each sounds-like hint must be less than 100 characters.",
+			})
+			return nil, false
+		}
+		soundsLike = append(soundsLike, variant)
+	}
+	if len(soundsLike) > maxSoundsLike {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "A term can have at most 20 sounds-like hints.",
+		})
+		return nil, false
+	}
+	return soundsLike, true
+}
+
+func validateBoost(c *gin.Context, boost float64) bool {
+	if boost < 0 || boost > maxVocabularyBoost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Validation failed",
+			"message": "Boost must be between 0 and 10.",
+		})
+		return false
+	}
+	return true
+}
+
+func respondVocabularyError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "folder not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Folder not found",
+			"message": "The requested folder does not exist or you don't have access to it.",
+		})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Term not found",
+			"message": "The requested vocabulary term does not exist or you don't have access to it.",
+		})
+	case strings.Contains(err.Error(), "already exists"):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Duplicate term",
+			"message": "This term is already in the vocabulary.",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Database error",
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// maxJobAttempts is how many times a job whose worker stopped mid-run is claimed before it is failed
+const maxJobAttempts = 3
+
+// Handler processes one claimed job. Returning an error marks the job failed.
+type Handler func(ctx context.Context, job *models.Job) error
+
+// Runner polls the jobs table and dispatches claimed jobs to registered handlers
+type Runner struct {
+	jobRepo      *repository.JobRepository
+	handlers     map[models.JobType]Handler
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	// staleAfter is how long a job may stay running before it is taken to be abandoned by a
+	// crashed or restarted worker and claimed again. It is longer than jobTimeout so a live
+	// worker always gets to record its own timeout first.
+	staleAfter time.Duration
+}
+
+func NewRunner(jobRepo *repository.JobRepository) *Runner {
+	return &Runner{
+		jobRepo:      jobRepo,
+		handlers:     make(map[models.JobType]Handler),
+		pollInterval: 2 * time.Second,
+		jobTimeout:   10 * time.Minute,
+		staleAfter:   11 * time.Minute,
+	}
+}
+
+// Register sets the handler for a job type. It must be called before Run.
+func (r *Runner) Register(jobType models.JobType, handler Handler) {
+	r.handlers[jobType] = handler
+}
+
+// Run processes jobs until the context is cancelled
+func (r *Runner) Run(ctx context.Context) {
+	types := make([]models.JobType, 0, len(r.handlers))
+	for jobType := range r.handlers {
+		types = append(types, jobType)
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if failed, err := r.jobRepo.FailStaleJobs(types, r.staleAfter, maxJobAttempts); err != nil {
+			log.Printf("Error failing stale jobs: %v", err)
+		} else if failed > 0 {
+			log.Printf("Failed %d jobs abandoned after %d attempts", failed, maxJobAttempts)
+		}
+
+		// Drain the queue before waiting for the next tick
+		for r.runNext(ctx, types) {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNext claims and runs a single job, reporting whether one was found
+func (r *Runner) runNext(ctx context.Context, types []models.JobType) bool {
+	job, err := r.jobRepo.ClaimNextJob(types, r.staleAfter, maxJobAttempts)
+	if err != nil {
+		log.Printf("Error claiming job: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, r.jobTimeout)
+	defer cancel()
+
+	if err := r.handle(jobCtx, job); err != nil {
+		log.Printf("Job %s (%s) failed: %v", job.ID, job.Type, err)
+		if err := r.jobRepo.FailJob(job.ID, job.Attempts, err.Error()); err != nil {
+			log.Printf("Error marking job %s failed: %v", job.ID, err)
+		}
+		return true
+	}
+
+	if err := r.jobRepo.CompleteJob(job.ID, job.Attempts); err != nil {
+		log.Printf("Error marking job %s complete: %v", job.ID, err)
+	}
+	return true
+}
+
+// handle runs the job's handler, turning panics into job failures
+func (r *Runner) handle(ctx context.Context, job *models.Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job handler panicked: %v", recovered)
+		}
+	}()
+	return r.handlers[job.Type](ctx, job)
+}
@@ -0,0 +1,126 @@
+package keywords
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+const (
+	// maxPhraseWords drops the long runs RAKE finds in rambling speech
+	maxPhraseWords = 3
+	// minOccurrences keeps only topics that recur in a file
+	minOccurrences = 2
+	// DefaultLimit is how many phrases are kept per file
+	DefaultLimit = 30
+)
+
+// phraseDelimiter splits text wherever a phrase can't continue: punctuation and spaced dashes
+var phraseDelimiter = regexp.MustCompile(`[^\p{L}\p{N}'\s-]+|\s+-+\s+`)
+
+// Extract finds a transcript's recurring key phrases with RAKE: candidates are runs of content
+// words between stopwords and punctuation, each word is scored by its degree over its frequency,
+// and a phrase scores the sum of its words. Phrases are ranked by score and how often they recur,
+// and scores are normalized so the top phrase scores 1.
+func Extract(transcript *models.Transcript, limit int) []models.Keyword {
+	var candidates [][]string
+	for _, segment := range transcript.Segments {
+		for _, chunk := range phraseDelimiter.Split(segment.Text, -1) {
+			candidates = append(candidates, splitCandidates(chunk)...)
+		}
+	}
+
+	frequency := make(map[string]int)
+	degree := make(map[string]int)
+	occurrences := make(map[string]int)
+	words := make(map[string][]string)
+	for _, candidate := range candidates {
+		for _, word := range candidate {
+			frequency[word]++
+			degree[word] += len(candidate)
+		}
+		phrase := strings.Join(candidate, " ")
+		occurrences[phrase]++
+		words[phrase] = candidate
+	}
+
+	keywords := make([]models.Keyword, 0, len(occurrences))
+	for phrase, count := range occurrences {
+		if count < minOccurrences {
+			continue
+		}
+		var score float64
+		for _, word := range words[phrase] {
+			score += float64(degree[word]) / float64(frequency[word])
+		}
+		keywords = append(keywords, models.Keyword{
+			Keyword:     phrase,
+			Score:       score * (1 + math.Log(float64(count))),
+			Occurrences: count,
+			FileCount:   1,
+		})
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Keyword < keywords[j].Keyword
+	})
+	if limit > 0 && len(keywords) > limit {
+		keywords = keywords[:limit]
+	}
+
+	if len(keywords) > 0 {
+		top := keywords[0].Score
+		for i := range keywords {
+			keywords[i].Score = math.Round(keywords[i].Score/top*10000) / 10000
+		}
+	}
+
+	return keywords
+}
+
+// splitCandidates breaks a chunk of text into candidate phrases at stopwords and numbers
+func splitCandidates(chunk string) [][]string {
+	var candidates [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 && len(current) <= maxPhraseWords &&
+			(len(current) > 1 || len([]rune(current[0])) > 2) {
+			candidates = append(candidates, current)
+		}
+		current = nil
+	}
+
+	for _, token := range textutil.Tokenize(chunk) {
+		token = strings.Trim(token, "'")
+		if token == "" || textutil.IsStopword(token) || len([]rune(token)) < 2 || isNumber(token) {
+			flush()
+			continue
+		}
+		current = append(current, token)
+	}
+	flush()
+
+	return candidates
+}
+
+// Normalize folds a keyword the way extracted phrases are stored: lowercase, single-spaced
+func Normalize(keyword string) string {
+	return strings.Join(strings.Fields(strings.ToLower(keyword)), " ")
+}
+
+func isNumber(token string) bool {
+	for _, r := range token {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
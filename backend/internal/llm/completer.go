@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Completer generates chat completions, optionally streaming the reply as it is produced
+type Completer interface {
+	Complete(ctx context.Context, messages []Message) (string, error)
+	// Stream calls onDelta with each piece of the reply and returns the full reply
+	Stream(ctx context.Context, messages []Message, onDelta func(delta string) error) (string, error)
+}
+
+// sourceLinePattern matches "[1] (02:18) Speaker: text", with the time and speaker optional
+var sourceLinePattern = regexp.MustCompile(`(?m)^\[(\d+)\]\s*(?:\([^)]*\)\s*)?(?:[^:\n]{1,60}:\s+)?(.+)$`)
+
+// StubCompleter is a deterministic, offline Completer for tests and local development.
+// It answers by quoting the first numbered source line ("[1] (02:18) Speaker: text") in the last
+// message, cited as [1], and streams the reply word by word.
+type StubCompleter struct{}
+
+func NewStubCompleter() *StubCompleter {
+	return &StubCompleter{}
+}
+
+func (s *StubCompleter) Complete(ctx context.Context, messages []Message) (string, error) {
+	return s.Stream(ctx, messages, func(string) error { return nil })
+}
+
+func (s *StubCompleter) Stream(ctx context.Context, messages []Message, onDelta func(delta string) error) (string, error) {
+	reply := s.reply(messages)
+
+	var full strings.Builder
+	for i, word := range strings.Fields(reply) {
+		if err := ctx.Err(); err != nil {
+			return full.String(), err
+		}
+		if i > 0 {
+			word = " " + word
+		}
+		full.WriteString(word)
+		if err := onDelta(word); err != nil {
+			return full.String(), err
+		}
+	}
+	return full.String(), nil
+}
+
+func (s *StubCompleter) reply(messages []Message) string {
+	if len(messages) == 0 {
+		return "I don't have enough information to answer that."
+	}
+
+	match := sourceLinePattern.FindStringSubmatch(messages[len(messages)-1].Content)
+	if match == nil {
+		return "I couldn't find anything about that in the transcript."
+	}
+	return fmt.Sprintf("According to the transcript: \"%s\" [%s]", strings.TrimSpace(match[2]), match[1])
+}
@@ -0,0 +1,182 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// completeTimeout bounds a non-streamed completion. Streamed completions are bounded only by the
+// caller's context, since a long answer can take longer than this to arrive.
+const completeTimeout = 2 * time.Minute
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OpenAIClient talks to any OpenAI-compatible /chat/completions endpoint
+type OpenAIClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewOpenAIClient(baseURL, apiKey, model string) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAIClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{},
+	}
+}
+
+// Model returns the configured model name
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// Complete sends the conversation and returns the assistant's full reply
+func (c *OpenAIClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, completeTimeout)
+	defer cancel()
+
+	resp, err := c.post(ctx, map[string]interface{}{
+		"model":       c.model,
+		"messages":    messages,
+		"temperature": 0.2,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode completion response: %w", err)
+	}
+	if len(payload.Choices) == 0 {
+		return "", fmt.Errorf("completion response has no choices")
+	}
+
+	return payload.Choices[0].Message.Content, nil
+}
+
+// Stream requests a streamed completion and forwards each content delta
+func (c *OpenAIClient) Stream(ctx context.Context, messages []Message, onDelta func(delta string) error) (string, error) {
+	resp, err := c.post(ctx, map[string]interface{}{
+		"model":       c.model,
+		"messages":    messages,
+		"temperature": 0.2,
+		"stream":      true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return full.String(), fmt.Errorf("failed to decode completion stream: %w", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		full.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return full.String(), err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return full.String(), fmt.Errorf("completion stream interrupted: %w", err)
+	}
+
+	return full.String(), nil
+}
+
+// post sends a request body to /chat/completions and checks the status code
+func (c *OpenAIClient) post(ctx context.Context, body map[string]interface{}) (*http.Response, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build completion request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("completion request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("completion request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return resp, nil
+}
+
+// ExtractJSON strips markdown code fences and surrounding prose from a model reply
+func ExtractJSON(reply string) string {
+	reply = strings.TrimSpace(reply)
+	start := strings.IndexAny(reply, "{[")
+	end := strings.LastIndexAny(reply, "}]")
+	if start < 0 || end < start {
+		return reply
+	}
+	return reply[start : end+1]
+}
@@ -0,0 +1,69 @@
+package models
+
+// TranscriptAnalytics describes how a conversation was shared between its speakers
+type TranscriptAnalytics struct {
+	FileID       string `json:"file_id"`
+	TranscriptID string `json:"transcript_id"`
+	// DurationMs runs from the start of the recording to the end of the last segment
+	DurationMs int64 `json:"duration_ms"`
+	// SpeechMs is the time at least one speaker was talking; OverlapMs the time two or more were
+	SpeechMs      int64              `json:"speech_ms"`
+	OverlapMs     int64              `json:"overlap_ms"`
+	SilenceMs     int64              `json:"silence_ms"`
+	SilenceRatio  float64            `json:"silence_ratio"`
+	Turns         int                `json:"turns"`
+	Interruptions int                `json:"interruptions"`
+	Speakers      []SpeakerAnalytics `json:"speakers"`
+}
+
+// SpeakerAnalytics is one speaker's share of a conversation. Segments without a speaker are
+// reported under a nil SpeakerID.
+type SpeakerAnalytics struct {
+	SpeakerID *string `json:"speaker_id"`
+	Name      string  `json:"name"`
+	// Named is true once a reviewer has given the speaker a name; folder rollups merge named speakers across files
+	Named                 bool       `json:"named"`
+	TalkTimeMs            int64      `json:"talk_time_ms"`
+	TalkShare             float64    `json:"talk_share"`
+	Turns                 int        `json:"turns"`
+	Words                 int        `json:"words"`
+	WordsPerMinute        float64    `json:"words_per_minute"`
+	LongestMonologue      *Monologue `json:"longest_monologue,omitempty"`
+	InterruptionsMade     int        `json:"interruptions_made"`
+	InterruptionsReceived int        `json:"interruptions_received"`
+}
+
+// Monologue is an uninterrupted run of segments from one speaker
+type Monologue struct {
+	FileID     string `json:"file_id,omitempty"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// FolderAnalytics rolls up the analytics of every transcript in a folder and its subfolders
+type FolderAnalytics struct {
+	FolderID      string             `json:"folder_id"`
+	FileCount     int                `json:"file_count"`
+	DurationMs    int64              `json:"duration_ms"`
+	SpeechMs      int64              `json:"speech_ms"`
+	OverlapMs     int64              `json:"overlap_ms"`
+	SilenceMs     int64              `json:"silence_ms"`
+	SilenceRatio  float64            `json:"silence_ratio"`
+	Turns         int                `json:"turns"`
+	Interruptions int                `json:"interruptions"`
+	Speakers      []SpeakerAnalytics `json:"speakers"`
+	Files         []FileAnalytics    `json:"files"`
+}
+
+// FileAnalytics summarizes one file within a folder rollup
+type FileAnalytics struct {
+	FileID          string  `json:"file_id"`
+	FileName        string  `json:"file_name"`
+	DurationMs      int64   `json:"duration_ms"`
+	SpeakerCount    int     `json:"speaker_count"`
+	DominantSpeaker string  `json:"dominant_speaker,omitempty"`
+	DominantShare   float64 `json:"dominant_share"`
+	SilenceRatio    float64 `json:"silence_ratio"`
+	Interruptions   int     `json:"interruptions"`
+}
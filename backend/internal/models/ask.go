@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Citation points an answer back to the transcript passage it relied on
+type Citation struct {
+	Index      int      `json:"index"`
+	SegmentIDs []string `json:"segment_ids"`
+	Speaker    string   `json:"speaker,omitempty"`
+	Text       string   `json:"text"`
+	StartMs    int64    `json:"start_ms"`
+	EndMs      int64    `json:"end_ms"`
+}
+
+// AskMessage is one turn of a file's "ask about the meeting" conversation
+type AskMessage struct {
+	ID        string     `json:"id" db:"id"`
+	FileID    string     `json:"file_id" db:"file_id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	Role      string     `json:"role" db:"role"`
+	Content   string     `json:"content" db:"content"`
+	Citations []Citation `json:"citations,omitempty" db:"citations"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Chapter is a topical section of a transcript
+type Chapter struct {
+	ID             string    `json:"id" db:"id"`
+	TranscriptID   string    `json:"transcript_id" db:"transcript_id"`
+	Index          int       `json:"index" db:"chapter_index"`
+	Title          string    `json:"title" db:"title"`
+	StartMs        int64     `json:"start_ms" db:"start_ms"`
+	EndMs          int64     `json:"end_ms" db:"end_ms"`
+	StartSegmentID *string   `json:"start_segment_id,omitempty" db:"start_segment_id"`
+	Generator      string    `json:"generator" db:"generator"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
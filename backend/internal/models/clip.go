@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Clip is a highlight cut from a file's timeline
+type Clip struct {
+	ID        string    `json:"id" db:"id"`
+	FileID    string    `json:"file_id" db:"file_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Title     string    `json:"title" db:"title"`
+	Note      *string   `json:"note" db:"note"`
+	StartMs   int64     `json:"start_ms" db:"start_ms"`
+	EndMs     int64     `json:"end_ms" db:"end_ms"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Comment is a note on a transcript. Root comments are anchored to a time range (and the segment it
+// starts in, so the anchor follows retiming); replies belong to a root comment and share its anchor.
+type Comment struct {
+	ID           string           `json:"id" db:"id"`
+	TranscriptID string           `json:"transcript_id" db:"transcript_id"`
+	UserID       string           `json:"user_id" db:"user_id"`
+	ParentID     *string          `json:"parent_id" db:"parent_id"`
+	SegmentID    *string          `json:"segment_id,omitempty" db:"segment_id"`
+	StartMs      *int64           `json:"start_ms,omitempty" db:"start_ms"`
+	EndMs        *int64           `json:"end_ms,omitempty" db:"end_ms"`
+	Body         string           `json:"body" db:"body"`
+	Mentions     []CommentMention `json:"mentions"`
+	ResolvedAt   *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy   *string          `json:"resolved_by,omitempty" db:"resolved_by"`
+	EditedAt     *time.Time       `json:"edited_at,omitempty" db:"edited_at"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at" db:"updated_at"`
+	Replies      []Comment        `json:"replies,omitempty"`
+}
+
+// CommentMention is a user @mentioned in a comment
+type CommentMention struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// MentionNotice is a comment that mentions the current user, with the file it was left on
+type MentionNotice struct {
+	FileID  string  `json:"file_id"`
+	Comment Comment `json:"comment"`
+}
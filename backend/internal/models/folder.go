@@ -3,13 +3,18 @@ package models
 import "time"
 
 type Folder struct {
-	ID        string     `json:"id" db:"id"`
-	Name      string     `json:"name" db:"name"`
-	ParentID  *string    `json:"parent_id" db:"parent_id"`
-	UserID    string     `json:"user_id" db:"user_id"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID          string     `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	ParentID    *string    `json:"parent_id" db:"parent_id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	Color       *string    `json:"color" db:"color"`
+	Icon        *string    `json:"icon" db:"icon"`
+	Description *string    `json:"description" db:"description"`
+	Pinned      bool       `json:"pinned" db:"pinned"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	Version     int64      `json:"version" db:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type File struct {
@@ -18,13 +23,18 @@ type File struct {
 	Type       string     `json:"type" db:"type"`
 	Size       *int64     `json:"size,omitempty" db:"size"`
 	Length     *string    `json:"length,omitempty" db:"length"`
+	DurationMs *int64     `json:"duration_ms,omitempty" db:"duration_ms"`
 	Language   *string    `json:"language,omitempty" db:"language"`
 	Service    *string    `json:"service,omitempty" db:"service"`
 	Tags       []string   `json:"tags,omitempty" db:"tags"`
 	FolderID   *string    `json:"folder_id" db:"folder_id"`
 	UserID     string     `json:"user_id" db:"user_id"`
+	StorageKey *string    `json:"-" db:"storage_key"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewedBy *string    `json:"reviewed_by,omitempty" db:"reviewed_by"`
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	Version    int64      `json:"version" db:"version"`
 	DeletedAt  *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
@@ -47,4 +57,86 @@ type FolderDataResponse struct {
 		TotalFiles   int `json:"total_files"`
 		TotalFolders int `json:"total_folders"`
 	} `json:"stats"`
-}
\ No newline at end of file
+	// NextCursor fetches the next page of contents; nil on the last page
+	NextCursor *string `json:"next_cursor"`
+}
+
+// FolderSort is a field folder listings can be sorted by
+type FolderSort string
+
+const (
+	FolderSortName      FolderSort = "name"
+	FolderSortCreatedAt FolderSort = "created_at"
+	FolderSortUpdatedAt FolderSort = "updated_at"
+	FolderSortSize      FolderSort = "size"
+	FolderSortDuration  FolderSort = "duration"
+)
+
+// FolderContentsPage is one page of a folder listing with the totals matching its filters
+type FolderContentsPage struct {
+	Contents     FolderContents
+	TotalFolders int
+	TotalFiles   int
+	NextCursor   *string
+}
+
+// DeletionResult reports what a cascading delete soft-deleted. Everything deleted together shares
+// the batch ID.
+type DeletionResult struct {
+	BatchID        string `json:"deletion_batch_id"`
+	DeletedFolders int    `json:"deleted_folders"`
+	DeletedFiles   int    `json:"deleted_files"`
+}
+
+// FolderCopyResult is a copied folder with the IDs of everything copied, keyed by the original's ID
+type FolderCopyResult struct {
+	Folder        *Folder           `json:"folder"`
+	FolderIDs     map[string]string `json:"folder_ids"`
+	FileIDs       map[string]string `json:"file_ids"`
+	TranscriptIDs map[string]string `json:"transcript_ids"`
+}
+
+// FolderTreeNode is a folder in the folder tree with totals over its whole subtree. Children is
+// left out below the requested depth; HasChildren tells the client the node can be expanded.
+// SmartFolders are the smart folders shown under it, which the totals do not count.
+type FolderTreeNode struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	ParentID        *string           `json:"parent_id"`
+	Color           *string           `json:"color"`
+	Icon            *string           `json:"icon"`
+	Pinned          bool              `json:"pinned"`
+	Depth           int               `json:"depth"`
+	FolderCount     int               `json:"folder_count"`
+	FileCount       int               `json:"file_count"`
+	TotalFiles      int               `json:"total_files"`
+	TotalBytes      int64             `json:"total_bytes"`
+	TotalDurationMs int64             `json:"total_duration_ms"`
+	HasChildren     bool              `json:"has_children"`
+	Children        []*FolderTreeNode `json:"children,omitempty"`
+	SmartFolders    []SmartFolder     `json:"smart_folders,omitempty"`
+}
+
+// FolderTreeResponse is the tree below a folder (the root when RootID is nil). SmartFolders are the
+// smart folders directly below it.
+type FolderTreeResponse struct {
+	RootID       *string           `json:"root_id"`
+	MaxDepth     int               `json:"max_depth"`
+	Nodes        []*FolderTreeNode `json:"nodes"`
+	SmartFolders []SmartFolder     `json:"smart_folders"`
+}
+
+// FolderPathResult is the folder a path resolves to, with how many folders creating it made
+type FolderPathResult struct {
+	Folder         *Folder `json:"folder"`
+	Path           string  `json:"path"`
+	CreatedFolders int     `json:"created_folders"`
+}
+
+// QuickAccess is the sidebar's quick access list: pinned folders and the user's starred folders and
+// files, most recently starred first
+type QuickAccess struct {
+	PinnedFolders  []Folder `json:"pinned_folders"`
+	StarredFolders []Folder `json:"starred_folders"`
+	StarredFiles   []File   `json:"starred_files"`
+}
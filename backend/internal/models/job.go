@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobType string
+type JobStatus string
+
+const (
+	JobTypeSummarize JobType = "summarize"
+	JobTypeTranslate JobType = "translate"
+	JobTypeChapter   JobType = "chapter"
+	JobTypeEmbed     JobType = "embed"
+
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+type Job struct {
+	ID         string          `json:"id" db:"id"`
+	UserID     string          `json:"user_id" db:"user_id"`
+	FileID     *string         `json:"file_id,omitempty" db:"file_id"`
+	Type       JobType         `json:"type" db:"type"`
+	Status     JobStatus       `json:"status" db:"status"`
+	Payload    json.RawMessage `json:"payload,omitempty" db:"payload"`
+	Error      *string         `json:"error,omitempty" db:"error"`
+	Attempts   int             `json:"attempts" db:"attempts"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// TranslatePayload is the payload of a translate job
+type TranslatePayload struct {
+	TargetLanguage string `json:"target_language"`
+}
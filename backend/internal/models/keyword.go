@@ -0,0 +1,29 @@
+package models
+
+// Keyword is a key phrase from one or more transcripts. Score is the phrase's RAKE score weighted
+// by how rare it is across the user's library; confirmed keywords are the ones kept in a file's tags.
+type Keyword struct {
+	Keyword     string  `json:"keyword"`
+	Score       float64 `json:"score"`
+	Occurrences int     `json:"occurrences"`
+	FileCount   int     `json:"file_count"`
+	Confirmed   bool    `json:"confirmed"`
+}
+
+// KeywordFile is a file that mentions a keyword
+type KeywordFile struct {
+	FileID      string  `json:"file_id"`
+	FileName    string  `json:"file_name"`
+	FolderID    *string `json:"folder_id"`
+	Occurrences int     `json:"occurrences"`
+	Score       float64 `json:"score"`
+	Confirmed   bool    `json:"confirmed"`
+}
+
+type KeywordFilesResponse struct {
+	Keyword string        `json:"keyword"`
+	Files   []KeywordFile `json:"files"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// RedactionTerm is a user-defined word or phrase that is always redacted
+type RedactionTerm struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Term      string    `json:"term" db:"term"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// RedactedEntity locates one redaction placeholder within a segment's redacted text.
+// Original is only populated in previews and reveals, never stored in the clear.
+type RedactedEntity struct {
+	Type        string  `json:"type"`
+	Placeholder string  `json:"placeholder"`
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	Original    *string `json:"original,omitempty"`
+}
+
+// SegmentRedaction records the redactions applied to one segment; the original text is kept encrypted
+type SegmentRedaction struct {
+	ID           string           `json:"id" db:"id"`
+	TranscriptID string           `json:"transcript_id" db:"transcript_id"`
+	SegmentID    string           `json:"segment_id" db:"segment_id"`
+	RedactedText string           `json:"redacted_text" db:"text"`
+	Entities     []RedactedEntity `json:"entities" db:"entities"`
+	Ciphertext   []byte           `json:"-" db:"original_ciphertext"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+}
+
+// RevealedSegment pairs a redacted segment with its decrypted original
+type RevealedSegment struct {
+	SegmentID    string           `json:"segment_id"`
+	RedactedText string           `json:"redacted_text"`
+	OriginalText string           `json:"original_text"`
+	Entities     []RedactedEntity `json:"entities"`
+}
+
+type RedactionAction string
+
+const (
+	RedactionActionApply   RedactionAction = "apply"
+	RedactionActionReveal  RedactionAction = "reveal"
+	RedactionActionRestore RedactionAction = "restore"
+)
+
+// RedactionAuditEntry records who applied, revealed or reversed a transcript's redactions
+type RedactionAuditEntry struct {
+	ID           string          `json:"id" db:"id"`
+	TranscriptID string          `json:"transcript_id" db:"transcript_id"`
+	UserID       string          `json:"user_id" db:"user_id"`
+	Action       RedactionAction `json:"action" db:"action"`
+	SegmentCount int             `json:"segment_count" db:"segment_count"`
+	EntityCounts map[string]int  `json:"entity_counts" db:"entity_counts"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
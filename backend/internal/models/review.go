@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	ReviewItemSegment = "segment"
+	ReviewItemWord    = "word"
+)
+
+const (
+	ReviewActionAccept  = "accept"
+	ReviewActionCorrect = "correct"
+)
+
+// ReviewItem is a low-confidence segment or word awaiting a reviewer's decision
+type ReviewItem struct {
+	Kind         string  `json:"kind"`
+	SegmentID    string  `json:"segment_id"`
+	WordIndex    *int    `json:"word_index,omitempty"`
+	TranscriptID string  `json:"transcript_id"`
+	FileID       string  `json:"file_id"`
+	FileName     string  `json:"file_name"`
+	FolderID     *string `json:"folder_id"`
+	Text         string  `json:"text"`
+	// SegmentText gives a flagged word its surrounding context
+	SegmentText string  `json:"segment_text"`
+	StartMs     int64   `json:"start_ms"`
+	EndMs       int64   `json:"end_ms"`
+	Confidence  float64 `json:"confidence"`
+}
+
+type ReviewQueueResponse struct {
+	Items            []ReviewItem `json:"items"`
+	Total            int          `json:"total"`
+	Limit            int          `json:"limit"`
+	Offset           int          `json:"offset"`
+	SegmentThreshold float64      `json:"segment_threshold"`
+	WordThreshold    float64      `json:"word_threshold"`
+}
+
+// ReviewDecision records a reviewer accepting or correcting a queue item
+type ReviewDecision struct {
+	ID            string    `json:"id" db:"id"`
+	SegmentID     string    `json:"segment_id" db:"segment_id"`
+	WordIndex     *int      `json:"word_index,omitempty" db:"word_index"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	Action        string    `json:"action" db:"action"`
+	OriginalText  string    `json:"original_text" db:"original_text"`
+	CorrectedText *string   `json:"corrected_text,omitempty" db:"corrected_text"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+type SearchHit struct {
+	SegmentID    string       `json:"segment_id"`
+	TranscriptID string       `json:"transcript_id"`
+	FileID       string       `json:"file_id"`
+	FileName     string       `json:"file_name"`
+	FolderID     *string      `json:"folder_id"`
+	Breadcrumbs  []Breadcrumb `json:"breadcrumbs"`
+	Speaker      string       `json:"speaker,omitempty"`
+	Text         string       `json:"text"`
+	Snippet      string       `json:"snippet"`
+	StartMs      int64        `json:"start_ms"`
+	EndMs        int64        `json:"end_ms"`
+	Rank         float64      `json:"rank"`
+}
+
+type SearchResponse struct {
+	Query   string      `json:"query"`
+	Results []SearchHit `json:"results"`
+	Total   int         `json:"total"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+}
+
+// TranscriptChunk is a run of consecutive segments from one speaker, embedded as a unit
+type TranscriptChunk struct {
+	ID           string   `json:"id"`
+	TranscriptID string   `json:"transcript_id"`
+	ChunkIndex   int      `json:"chunk_index"`
+	SpeakerID    *string  `json:"speaker_id"`
+	SegmentIDs   []string `json:"segment_ids"`
+	Text         string   `json:"text"`
+	StartMs      int64    `json:"start_ms"`
+	EndMs        int64    `json:"end_ms"`
+	Confidence   *float64 `json:"confidence,omitempty"`
+}
+
+type SemanticHit struct {
+	ChunkID      string       `json:"chunk_id,omitempty"`
+	SegmentIDs   []string     `json:"segment_ids"`
+	TranscriptID string       `json:"transcript_id"`
+	FileID       string       `json:"file_id"`
+	FileName     string       `json:"file_name"`
+	FolderID     *string      `json:"folder_id"`
+	Breadcrumbs  []Breadcrumb `json:"breadcrumbs"`
+	Speaker      string       `json:"speaker,omitempty"`
+	Text         string       `json:"text"`
+	Snippet      string       `json:"snippet,omitempty"`
+	StartMs      int64        `json:"start_ms"`
+	EndMs        int64        `json:"end_ms"`
+	Similarity   float64      `json:"similarity"`
+	KeywordRank  float64      `json:"keyword_rank,omitempty"`
+	Score        float64      `json:"score"`
+}
+
+type SemanticSearchResponse struct {
+	Query   string        `json:"query"`
+	Mode    string        `json:"mode"`
+	Results []SemanticHit `json:"results"`
+	Total   int           `json:"total"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// SmartFolderPeriod is a creation date range relative to when a smart folder is opened
+type SmartFolderPeriod string
+
+const (
+	SmartFolderPeriodToday       SmartFolderPeriod = "today"
+	SmartFolderPeriodThisWeek    SmartFolderPeriod = "this_week"
+	SmartFolderPeriodThisMonth   SmartFolderPeriod = "this_month"
+	SmartFolderPeriodThisQuarter SmartFolderPeriod = "this_quarter"
+	SmartFolderPeriodThisYear    SmartFolderPeriod = "this_year"
+)
+
+// SmartFolderFilters is a saved search over the user's files. Every set filter must match; within
+// a list any value matches, except tags, which must all be present.
+type SmartFolderFilters struct {
+	// FolderID limits the search to files anywhere below a folder
+	FolderID      *string           `json:"folder_id,omitempty"`
+	Languages     []string          `json:"languages,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Services      []string          `json:"services,omitempty"`
+	MinDurationMs *int64            `json:"min_duration_ms,omitempty"`
+	MaxDurationMs *int64            `json:"max_duration_ms,omitempty"`
+	CreatedAfter  *time.Time        `json:"created_after,omitempty"`
+	CreatedBefore *time.Time        `json:"created_before,omitempty"`
+	CreatedWithin SmartFolderPeriod `json:"created_within,omitempty"`
+	// Text is a web-search style query over the file's transcripts
+	Text string `json:"text,omitempty"`
+}
+
+// SmartFolder is a virtual folder whose contents are the files matching its filters. ParentID is
+// the folder it appears under in the folder tree, nil for the root.
+type SmartFolder struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"user_id"`
+	Name      string             `json:"name"`
+	ParentID  *string            `json:"parent_id"`
+	Color     *string            `json:"color"`
+	Icon      *string            `json:"icon"`
+	Filters   SmartFolderFilters `json:"filters"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// SmartFolderDataResponse is one page of a smart folder's contents, shaped like FolderDataResponse
+type SmartFolderDataResponse struct {
+	SmartFolder *SmartFolder   `json:"smart_folder"`
+	Contents    FolderContents `json:"contents"`
+	Stats       struct {
+		TotalFiles   int `json:"total_files"`
+		TotalFolders int `json:"total_folders"`
+	} `json:"stats"`
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type TranscriptSummary struct {
+	ID           string       `json:"id" db:"id"`
+	TranscriptID string       `json:"transcript_id" db:"transcript_id"`
+	Summary      string       `json:"summary" db:"summary"`
+	KeyPoints    []KeyPoint   `json:"key_points" db:"key_points"`
+	ActionItems  []ActionItem `json:"action_items" db:"action_items"`
+	Generator    string       `json:"generator" db:"generator"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// KeyPoint is a notable statement linked to the segment it came from
+type KeyPoint struct {
+	Text      string `json:"text"`
+	SegmentID string `json:"segment_id"`
+	StartMs   int64  `json:"start_ms"`
+}
+
+// ActionItem is a task mentioned in the transcript, e.g. "Complete Q3 budget review (mentioned at 02:18)"
+type ActionItem struct {
+	Text      string  `json:"text"`
+	Owner     *string `json:"owner,omitempty"`
+	Due       *string `json:"due,omitempty"`
+	SegmentID string  `json:"segment_id"`
+	StartMs   int64   `json:"start_ms"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import "time"
+
+// SpeakerColors is the palette new roster entries are assigned from, in order
+var SpeakerColors = []string{
+	"#2563EB",
+	"#DC2626",
+	"#16A34A",
+	"#D97706",
+	"#7C3AED",
+	"#DB2777",
+	"#0891B2",
+	"#65A30D",
+}
+
+// SpeakerColor returns the palette color for the speaker at the given roster index
+func SpeakerColor(index int) string {
+	if index < 0 {
+		index = 0
+	}
+	return SpeakerColors[index%len(SpeakerColors)]
+}
+
+type Transcript struct {
+	ID       string  `json:"id" db:"id"`
+	FileID   string  `json:"file_id" db:"file_id"`
+	UserID   string  `json:"user_id" db:"user_id"`
+	Language *string `json:"language,omitempty" db:"language"`
+	// SourceTranscriptID links a translation to the transcript it was translated from
+	SourceTranscriptID *string `json:"source_transcript_id,omitempty" db:"source_transcript_id"`
+	// RedactedAt is set once PII has been redacted from the stored text
+	RedactedAt *time.Time `json:"redacted_at,omitempty" db:"redacted_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	Speakers   []Speaker  `json:"speakers"`
+	Segments   []Segment  `json:"segments"`
+	Chapters   []Chapter  `json:"chapters,omitempty"`
+}
+
+type Speaker struct {
+	ID           string    `json:"id" db:"id"`
+	TranscriptID string    `json:"transcript_id" db:"transcript_id"`
+	Label        string    `json:"label" db:"label"`
+	Name         *string   `json:"name" db:"name"`
+	Color        string    `json:"color" db:"color"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DisplayName returns the reviewer-assigned name, falling back to the diarization label
+func (s Speaker) DisplayName() string {
+	if s.Name != nil && *s.Name != "" {
+		return *s.Name
+	}
+	return s.Label
+}
+
+type Segment struct {
+	ID           string  `json:"id" db:"id"`
+	TranscriptID string  `json:"transcript_id" db:"transcript_id"`
+	SpeakerID    *string `json:"speaker_id" db:"speaker_id"`
+	SpeakerName  string  `json:"speaker_name,omitempty" db:"-"`
+	// SourceSegmentID aligns a translated segment with its original
+	SourceSegmentID *string   `json:"source_segment_id,omitempty" db:"source_segment_id"`
+	Text            string    `json:"text" db:"text"`
+	StartMs         int64     `json:"start_ms" db:"start_ms"`
+	EndMs           int64     `json:"end_ms" db:"end_ms"`
+	Confidence      *float64  `json:"confidence,omitempty" db:"confidence"`
+	Words           []Word    `json:"words,omitempty" db:"words"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type Word struct {
+	Text       string  `json:"text"`
+	StartMs    int64   `json:"start_ms"`
+	EndMs      int64   `json:"end_ms"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ResolveSpeakerNames fills each segment's SpeakerName from the roster
+func (t *Transcript) ResolveSpeakerNames() {
+	names := make(map[string]string, len(t.Speakers))
+	for _, speaker := range t.Speakers {
+		names[speaker.ID] = speaker.DisplayName()
+	}
+
+	for i := range t.Segments {
+		if t.Segments[i].SpeakerID == nil {
+			t.Segments[i].SpeakerName = ""
+			continue
+		}
+		t.Segments[i].SpeakerName = names[*t.Segments[i].SpeakerID]
+	}
+}
+
+type SpeakerSplitResult struct {
+	Speaker       *Speaker `json:"speaker"`
+	SegmentsMoved int      `json:"segments_moved"`
+}
+
+// SegmentRevision is a segment's text and timing as they were before an edit
+type SegmentRevision struct {
+	ID        string    `json:"id" db:"id"`
+	SegmentID string    `json:"segment_id" db:"segment_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Text      string    `json:"text" db:"text"`
+	StartMs   int64     `json:"start_ms" db:"start_ms"`
+	EndMs     int64     `json:"end_ms" db:"end_ms"`
+	Words     []Word    `json:"words,omitempty" db:"words"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	TrashItemFolder = "folder"
+	TrashItemFile   = "file"
+)
+
+// TrashItem is a deleted folder or file as it appears in the trash. Items deleted along with a
+// trashed folder are listed under that folder rather than on their own.
+type TrashItem struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// OriginalParentID and OriginalPath locate the folder the item was deleted from
+	OriginalParentID *string `json:"original_parent_id"`
+	OriginalPath     string  `json:"original_path"`
+	// ParentInTrash is set when the original folder is itself deleted, so restoring recreates it
+	ParentInTrash   bool      `json:"parent_in_trash"`
+	DeletionBatchID *string   `json:"deletion_batch_id,omitempty"`
+	ContainedItems  int       `json:"contained_items"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+type TrashListResponse struct {
+	Items         []TrashItem `json:"items"`
+	Total         int         `json:"total"`
+	Limit         int         `json:"limit"`
+	Offset        int         `json:"offset"`
+	RetentionDays int         `json:"retention_days"`
+}
+
+// RestoreResult reports what a restore brought back, including deleted parent folders recreated
+// to hold it
+type RestoreResult struct {
+	RestoredFolders int `json:"restored_folders"`
+	RestoredFiles   int `json:"restored_files"`
+	RestoredParents int `json:"restored_parents"`
+}
+
+// PurgeResult reports what was permanently deleted. StorageKeys lists the media no longer
+// referenced by any file, for the caller to remove from storage.
+type PurgeResult struct {
+	DeletedFolders int      `json:"deleted_folders"`
+	DeletedFiles   int      `json:"deleted_files"`
+	StorageKeys    []string `json:"-"`
+}
@@ -30,4 +30,20 @@ type User struct {
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
-}
\ No newline at end of file
+}
+// HasCustomVocabulary reports whether the plan includes custom vocabulary lists
+func (p UserPlan) HasCustomVocabulary() bool {
+	return p == UserPlanProfessional || p == UserPlanBusiness
+}
+
+// TrashRetention is how long deleted folders and files stay in the trash before they are purged
+func (p UserPlan) TrashRetention() time.Duration {
+	switch p {
+	case UserPlanBusiness:
+		return 90 * 24 * time.Hour
+	case UserPlanProfessional:
+		return 30 * 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
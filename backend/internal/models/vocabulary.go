@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// VocabularyTerm is a word or name the transcriber should expect. Account-level terms have no
+// FolderID; folder terms apply to the folder and everything below it.
+type VocabularyTerm struct {
+	ID       string  `json:"id" db:"id"`
+	UserID   string  `json:"user_id" db:"user_id"`
+	FolderID *string `json:"folder_id" db:"folder_id"`
+	Term     string  `json:"term" db:"term"`
+	// SoundsLike lists how the term tends to be misheard; those spellings are corrected to Term
+	SoundsLike []string  `json:"sounds_like" db:"sounds_like"`
+	Boost      float64   `json:"boost" db:"boost"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
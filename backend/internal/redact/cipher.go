@@ -0,0 +1,57 @@
+package redact
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// Cipher encrypts redacted originals with AES-256-GCM. The nonce is stored as a prefix of the ciphertext.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher builds a cipher from a base64-encoded 32-byte key
+func NewCipher(encodedKey string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("redaction key must be base64 encoded: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("redaction key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts plaintext, binding it to the segment it belongs to so ciphertexts can't be swapped between segments
+func (c *Cipher) Seal(plaintext []byte, segmentID string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, []byte(segmentID)), nil
+}
+
+func (c *Cipher) Open(ciphertext []byte, segmentID string) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:size], ciphertext[size:], []byte(segmentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt redacted original: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,193 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type EntityType string
+
+const (
+	EntityEmail      EntityType = "email"
+	EntityPhone      EntityType = "phone"
+	EntitySSN        EntityType = "ssn"
+	EntityCreditCard EntityType = "credit_card"
+	EntityCustomTerm EntityType = "custom_term"
+	EntityPerson     EntityType = "person"
+)
+
+// Placeholder is the text substituted for a redacted entity
+func (t EntityType) Placeholder() string {
+	switch t {
+	case EntityEmail:
+		return "[EMAIL]"
+	case EntityPhone:
+		return "[PHONE]"
+	case EntitySSN:
+		return "[SSN]"
+	case EntityCreditCard:
+		return "[CARD]"
+	case EntityPerson:
+		return "[NAME]"
+	default:
+		return "[REDACTED]"
+	}
+}
+
+// Match is a detected entity, located by byte offsets into the scanned text
+type Match struct {
+	Type  EntityType
+	Start int
+	End   int
+}
+
+// Detector finds entities in a single piece of text
+type Detector interface {
+	Detect(text string) []Match
+}
+
+// BuiltinDetectors returns the email, card, SSN and phone number detectors
+func BuiltinDetectors() []Detector {
+	return []Detector{
+		&patternDetector{entity: EntityEmail, pattern: emailPattern},
+		&patternDetector{entity: EntityCreditCard, pattern: cardPattern, valid: validCardNumber},
+		&patternDetector{entity: EntitySSN, pattern: ssnPattern, valid: validSSN},
+		&patternDetector{entity: EntityPhone, pattern: phonePattern},
+	}
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\d(?:[ -]?\d){12,18}`)
+	ssnPattern   = regexp.MustCompile(`\d{3}[- ]\d{2}[- ]\d{4}`)
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\d{3}[\s.-]?)\d{3}[\s.-]?\d{4}`)
+)
+
+// patternDetector reports regular expression matches that stand alone as numbers or words
+// and pass the optional validity check
+type patternDetector struct {
+	entity  EntityType
+	pattern *regexp.Regexp
+	valid   func(match string) bool
+}
+
+func (d *patternDetector) Detect(text string) []Match {
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		if !standsAlone(text, loc[0], loc[1]) {
+			continue
+		}
+		if d.valid != nil && !d.valid(text[loc[0]:loc[1]]) {
+			continue
+		}
+		matches = append(matches, Match{Type: d.entity, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+// standsAlone rejects matches that are part of a longer run of letters or digits
+func standsAlone(text string, start, end int) bool {
+	if start > 0 && isWordByte(text[start-1]) {
+		return false
+	}
+	if end < len(text) && isWordByte(text[end]) {
+		return false
+	}
+	return true
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
+
+// validCardNumber applies the Luhn checksum to 13-19 digit numbers
+func validCardNumber(match string) bool {
+	digits := onlyDigits(match)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validSSN rejects area, group and serial numbers the SSA never issues
+func validSSN(match string) bool {
+	digits := onlyDigits(match)
+	area, group, serial := digits[:3], digits[3:5], digits[5:]
+	if area == "000" || area == "666" || area[0] == '9' {
+		return false
+	}
+	return group != "00" && serial != "0000"
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// TermDetector redacts user-defined words and phrases, matched case-insensitively on word boundaries
+type TermDetector struct {
+	pattern *regexp.Regexp
+}
+
+func NewTermDetector(terms []string) *TermDetector {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return &TermDetector{}
+	}
+	return &TermDetector{pattern: regexp.MustCompile(`(?i)(` + strings.Join(quoted, "|") + `)`)}
+}
+
+func (d *TermDetector) Detect(text string) []Match {
+	if d.pattern == nil {
+		return nil
+	}
+
+	var matches []Match
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		if !termBoundary(text, loc[0], loc[1]) {
+			continue
+		}
+		matches = append(matches, Match{Type: EntityCustomTerm, Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+// termBoundary is standsAlone for any script, so terms like "Zoë" are matched as whole words
+func termBoundary(text string, start, end int) bool {
+	before := []rune(text[:start])
+	if len(before) > 0 && isWordRune(before[len(before)-1]) {
+		return false
+	}
+	for _, r := range text[end:] {
+		return !isWordRune(r)
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,130 @@
+package redact
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+func TestRedactTranscriptText(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		text  string
+		want  string
+	}{
+		{name: "email", text: "email jane.doe@example.com now", want: "email [EMAIL] now"},
+		{name: "card", text: "card 4111 1111 1111 1111 thanks", want: "card [CARD] thanks"},
+		{name: "card failing checksum", text: "ref 4111 1111 1111 1112", want: "ref 4111 1111 1111 1112"},
+		{name: "ssn", text: "ssn 123-45-6789", want: "ssn [SSN]"},
+		{name: "ssn never issued", text: "ssn 000-12-3456", want: "ssn 000-12-3456"},
+		{name: "phones", text: "call (555) 123-4567 or +1 555.123.4567", want: "call [PHONE] or [PHONE]"},
+		{name: "digits inside a longer number", text: "order 12345678901", want: "order 12345678901"},
+		{
+			name:  "terms on word boundaries",
+			terms: []string{"Project Falcon", "zoë"},
+			text:  "project falcon meets Zoë and Zoëy",
+			want:  "[REDACTED] meets [REDACTED] and Zoëy",
+		},
+		{name: "longest overlapping match wins", terms: []string{"jane"}, text: "mail jane@example.com", want: "mail [EMAIL]"},
+		{name: "nothing to redact", text: "nothing to see here", want: "nothing to see here"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectors := append(BuiltinDetectors(), NewTermDetector(tt.terms))
+			transcript := &models.Transcript{Segments: []models.Segment{{ID: "s1", Text: tt.text}}}
+
+			results, err := NewEngine(detectors, nil).RedactTranscript(context.Background(), transcript)
+			if err != nil {
+				t.Fatalf("RedactTranscript() error = %v", err)
+			}
+			Apply(transcript, results)
+			if got := transcript.Segments[0].Text; got != tt.want {
+				t.Errorf("redacted text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOverlaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []Match
+		length  int
+		want    []Match
+	}{
+		{
+			name:    "disjoint matches sorted by position",
+			matches: []Match{{EntityPhone, 10, 20}, {EntityEmail, 0, 5}},
+			length:  20,
+			want:    []Match{{EntityEmail, 0, 5}, {EntityPhone, 10, 20}},
+		},
+		{
+			name:    "longer match replaces the one it overlaps",
+			matches: []Match{{EntityCustomTerm, 2, 6}, {EntityEmail, 0, 12}},
+			length:  12,
+			want:    []Match{{EntityEmail, 0, 12}},
+		},
+		{
+			name:    "equal lengths keep the first",
+			matches: []Match{{EntityPerson, 0, 4}, {EntityCustomTerm, 2, 6}},
+			length:  6,
+			want:    []Match{{EntityPerson, 0, 4}},
+		},
+		{
+			name:    "touching matches do not overlap",
+			matches: []Match{{EntityPerson, 0, 3}, {EntityPerson, 3, 6}},
+			length:  6,
+			want:    []Match{{EntityPerson, 0, 3}, {EntityPerson, 3, 6}},
+		},
+		{
+			name:    "out of range and empty matches are dropped",
+			matches: []Match{{EntityPerson, -1, 2}, {EntityPerson, 4, 9}, {EntityPerson, 3, 3}},
+			length:  8,
+			want:    []Match{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveOverlaps(tt.matches, tt.length); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveOverlaps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// replyCompleter answers every completion with a fixed reply
+type replyCompleter struct {
+	reply string
+}
+
+func (c replyCompleter) Complete(ctx context.Context, messages []llm.Message) (string, error) {
+	return c.reply, nil
+}
+
+func (c replyCompleter) Stream(ctx context.Context, messages []llm.Message, onDelta func(delta string) error) (string, error) {
+	return c.reply, onDelta(c.reply)
+}
+
+func TestLLMRecognizer(t *testing.T) {
+	recognizer := NewLLMRecognizer(replyCompleter{reply: "```json\n" +
+		`{"entities": [{"index": 0, "text": "Maria"}, {"index": 1, "text": "Ann Lee"}, {"index": 7, "text": "Bob"}, {"index": 1, "text": "Nobody"}]}` +
+		"\n```"})
+
+	got, err := recognizer.Recognize(context.Background(), []string{"Maria met Maria", "thanks Ann Lee"})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+	want := [][]Match{
+		{{EntityPerson, 0, 5}, {EntityPerson, 10, 15}},
+		{{EntityPerson, 7, 14}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Recognize() = %+v, want %+v", got, want)
+	}
+}
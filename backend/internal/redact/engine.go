@@ -0,0 +1,197 @@
+package redact
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// Engine runs a set of detectors, plus an optional entity recognizer, over transcript text
+type Engine struct {
+	detectors  []Detector
+	recognizer EntityRecognizer
+}
+
+func NewEngine(detectors []Detector, recognizer EntityRecognizer) *Engine {
+	return &Engine{detectors: detectors, recognizer: recognizer}
+}
+
+// SegmentResult is the redacted form of one segment, with its original kept for encryption
+type SegmentResult struct {
+	SegmentID     string
+	OriginalText  string
+	OriginalWords []models.Word
+	Text          string
+	Words         []models.Word
+	Entities      []models.RedactedEntity
+}
+
+// RedactTranscript returns a result for every segment that contains at least one entity
+func (e *Engine) RedactTranscript(ctx context.Context, transcript *models.Transcript) ([]SegmentResult, error) {
+	texts := make([]string, len(transcript.Segments))
+	for i, segment := range transcript.Segments {
+		texts[i] = segment.Text
+	}
+
+	var recognized [][]Match
+	if e.recognizer != nil && len(texts) > 0 {
+		var err error
+		recognized, err = e.recognizer.Recognize(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := []SegmentResult{}
+	for i, segment := range transcript.Segments {
+		var matches []Match
+		for _, detector := range e.detectors {
+			matches = append(matches, detector.Detect(segment.Text)...)
+		}
+		if i < len(recognized) {
+			matches = append(matches, recognized[i]...)
+		}
+
+		matches = resolveOverlaps(matches, len(segment.Text))
+		if len(matches) == 0 {
+			continue
+		}
+
+		text, entities := substitute(segment.Text, matches)
+		results = append(results, SegmentResult{
+			SegmentID:     segment.ID,
+			OriginalText:  segment.Text,
+			OriginalWords: segment.Words,
+			Text:          text,
+			Words:         redactWords(segment.Text, segment.Words, matches),
+			Entities:      entities,
+		})
+	}
+	return results, nil
+}
+
+// Apply substitutes redacted text into the transcript in place, for views and exports
+func Apply(transcript *models.Transcript, results []SegmentResult) {
+	byID := make(map[string]SegmentResult, len(results))
+	for _, result := range results {
+		byID[result.SegmentID] = result
+	}
+
+	for i := range transcript.Segments {
+		result, ok := byID[transcript.Segments[i].ID]
+		if !ok {
+			continue
+		}
+		transcript.Segments[i].Text = result.Text
+		transcript.Segments[i].Words = result.Words
+	}
+}
+
+// CountEntities tallies results by entity type, as recorded in the audit log
+func CountEntities(results []SegmentResult) map[string]int {
+	counts := make(map[string]int)
+	for _, result := range results {
+		for _, entity := range result.Entities {
+			counts[entity.Type]++
+		}
+	}
+	return counts
+}
+
+// resolveOverlaps keeps the longest of any overlapping matches and returns them in text order
+func resolveOverlaps(matches []Match, textLength int) []Match {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].End-matches[i].Start > matches[j].End-matches[j].Start
+	})
+
+	kept := []Match{}
+	for _, match := range matches {
+		if match.Start < 0 || match.End > textLength || match.Start >= match.End {
+			continue
+		}
+		overlaps := false
+		for _, other := range kept {
+			if match.Start < other.End && other.Start < match.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, match)
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start < kept[j].Start })
+	return kept
+}
+
+// substitute replaces each match with its placeholder, recording where the placeholders landed
+func substitute(text string, matches []Match) (string, []models.RedactedEntity) {
+	var b strings.Builder
+	entities := make([]models.RedactedEntity, 0, len(matches))
+
+	last := 0
+	for _, match := range matches {
+		b.WriteString(text[last:match.Start])
+		placeholder := match.Type.Placeholder()
+		original := text[match.Start:match.End]
+		entities = append(entities, models.RedactedEntity{
+			Type:        string(match.Type),
+			Placeholder: placeholder,
+			Start:       b.Len(),
+			End:         b.Len() + len(placeholder),
+			Original:    &original,
+		})
+		b.WriteString(placeholder)
+		last = match.End
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), entities
+}
+
+// redactWords replaces the words covered by a match with a single placeholder word spanning
+// their timings, locating each word in the segment text in order. When the words no longer line up
+// with the text it returns nil, since a word it cannot place might hold the entity.
+func redactWords(text string, words []models.Word, matches []Match) []models.Word {
+	if len(words) == 0 {
+		return words
+	}
+
+	redacted := make([]models.Word, 0, len(words))
+	cursor := 0
+	lastMatch := -1
+	for _, word := range words {
+		start := strings.Index(text[cursor:], word.Text)
+		if start < 0 {
+			return nil
+		}
+		start += cursor
+		end := start + len(word.Text)
+		cursor = end
+
+		matchIndex := -1
+		for i, match := range matches {
+			if start < match.End && match.Start < end {
+				matchIndex = i
+				break
+			}
+		}
+		if matchIndex < 0 {
+			redacted = append(redacted, word)
+			lastMatch = -1
+			continue
+		}
+
+		if matchIndex == lastMatch {
+			redacted[len(redacted)-1].EndMs = word.EndMs
+			continue
+		}
+		word.Text = matches[matchIndex].Type.Placeholder()
+		redacted = append(redacted, word)
+		lastMatch = matchIndex
+	}
+	return redacted
+}
@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+func TestRedactWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		words   []models.Word
+		matches []Match
+		want    []models.Word
+	}{
+		{
+			name: "single word match",
+			text: "call me at 555-123-4567 today",
+			words: []models.Word{
+				{Text: "call", StartMs: 0, EndMs: 100},
+				{Text: "me", StartMs: 100, EndMs: 200},
+				{Text: "at", StartMs: 200, EndMs: 300},
+				{Text: "555-123-4567", StartMs: 300, EndMs: 900},
+				{Text: "today", StartMs: 900, EndMs: 1000},
+			},
+			matches: []Match{{Type: EntityPhone, Start: 11, End: 23}},
+			want: []models.Word{
+				{Text: "call", StartMs: 0, EndMs: 100},
+				{Text: "me", StartMs: 100, EndMs: 200},
+				{Text: "at", StartMs: 200, EndMs: 300},
+				{Text: "[PHONE]", StartMs: 300, EndMs: 900},
+				{Text: "today", StartMs: 900, EndMs: 1000},
+			},
+		},
+		{
+			name: "match spanning words becomes one placeholder",
+			text: "hello John Smith bye",
+			words: []models.Word{
+				{Text: "hello", StartMs: 0, EndMs: 100},
+				{Text: "John", StartMs: 100, EndMs: 200},
+				{Text: "Smith", StartMs: 200, EndMs: 300},
+				{Text: "bye", StartMs: 300, EndMs: 400},
+			},
+			matches: []Match{{Type: EntityPerson, Start: 6, End: 16}},
+			want: []models.Word{
+				{Text: "hello", StartMs: 0, EndMs: 100},
+				{Text: "[NAME]", StartMs: 100, EndMs: 300},
+				{Text: "bye", StartMs: 300, EndMs: 400},
+			},
+		},
+		{
+			name: "adjacent matches stay separate",
+			text: "Ann Bob",
+			words: []models.Word{
+				{Text: "Ann", StartMs: 0, EndMs: 100},
+				{Text: "Bob", StartMs: 100, EndMs: 200},
+			},
+			matches: []Match{{Type: EntityPerson, Start: 0, End: 3}, {Type: EntityPerson, Start: 4, End: 7}},
+			want: []models.Word{
+				{Text: "[NAME]", StartMs: 0, EndMs: 100},
+				{Text: "[NAME]", StartMs: 100, EndMs: 200},
+			},
+		},
+		{
+			name: "words not matching the text are dropped",
+			text: "my number is 555-123-4567",
+			words: []models.Word{
+				{Text: "my", StartMs: 0, EndMs: 100},
+				{Text: "phone", StartMs: 100, EndMs: 200},
+				{Text: "is", StartMs: 200, EndMs: 300},
+				{Text: "555-123-4567", StartMs: 300, EndMs: 900},
+			},
+			matches: []Match{{Type: EntityPhone, Start: 13, End: 25}},
+			want:    nil,
+		},
+		{
+			name:    "no words",
+			text:    "jane@example.com",
+			matches: []Match{{Type: EntityEmail, Start: 0, End: 16}},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactWords(tt.text, tt.words, tt.matches)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactWords() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
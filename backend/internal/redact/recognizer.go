@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+)
+
+// EntityRecognizer is the named-entity recognition hook. It sees the whole transcript at once,
+// so model-backed recognizers can use context across segments, and returns matches per text.
+type EntityRecognizer interface {
+	Recognize(ctx context.Context, texts []string) ([][]Match, error)
+}
+
+// maxRecognizerBatch bounds how many segments are sent to the model in one request
+const maxRecognizerBatch = 100
+
+const recognizeInstructions = `You find personal names in transcript segments so they can be redacted.
+You receive a JSON array of strings. Reply with JSON only, in this shape:
+{"entities": [{"index": 0, "text": "exact name as written"}]}
+"index" is the position of the string containing the name. Include every person's name, first names and surnames, exactly as written. Do not include organizations, places or products.`
+
+// LLMRecognizer finds personal names with a chat model
+type LLMRecognizer struct {
+	completer llm.Completer
+}
+
+func NewLLMRecognizer(completer llm.Completer) *LLMRecognizer {
+	return &LLMRecognizer{completer: completer}
+}
+
+func (r *LLMRecognizer) Recognize(ctx context.Context, texts []string) ([][]Match, error) {
+	matches := make([][]Match, len(texts))
+	for start := 0; start < len(texts); start += maxRecognizerBatch {
+		end := start + maxRecognizerBatch
+		if end > len(texts) {
+			end = len(texts)
+		}
+		if err := r.recognizeBatch(ctx, texts[start:end], matches[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+func (r *LLMRecognizer) recognizeBatch(ctx context.Context, texts []string, matches [][]Match) error {
+	encoded, err := json.Marshal(texts)
+	if err != nil {
+		return err
+	}
+
+	reply, err := r.completer.Complete(ctx, []llm.Message{
+		{Role: llm.RoleSystem, Content: recognizeInstructions},
+		{Role: llm.RoleUser, Content: string(encoded)},
+	})
+	if err != nil {
+		return err
+	}
+
+	var parsed struct {
+		Entities []struct {
+			Index int    `json:"index"`
+			Text  string `json:"text"`
+		} `json:"entities"`
+	}
+	if err := json.Unmarshal([]byte(llm.ExtractJSON(reply)), &parsed); err != nil {
+		return fmt.Errorf("recognizer returned invalid JSON: %w", err)
+	}
+
+	for _, entity := range parsed.Entities {
+		name := strings.TrimSpace(entity.Text)
+		if entity.Index < 0 || entity.Index >= len(texts) || name == "" {
+			continue
+		}
+		// Locate every occurrence ourselves rather than trusting offsets from the model
+		text := texts[entity.Index]
+		for offset := 0; ; {
+			at := strings.Index(text[offset:], name)
+			if at < 0 {
+				break
+			}
+			start := offset + at
+			matches[entity.Index] = append(matches[entity.Index], Match{Type: EntityPerson, Start: start, End: start + len(name)})
+			offset = start + len(name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// Redactor runs redaction for a user: the built-in detectors, the user's custom terms and the
+// NER hook. Redactions can only be made permanent, and later reversed, when a cipher is configured.
+type Redactor struct {
+	redactionRepo *repository.RedactionRepository
+	recognizer    EntityRecognizer
+	cipher        *Cipher
+}
+
+func NewRedactor(redactionRepo *repository.RedactionRepository, recognizer EntityRecognizer, cipher *Cipher) *Redactor {
+	return &Redactor{
+		redactionRepo: redactionRepo,
+		recognizer:    recognizer,
+		cipher:        cipher,
+	}
+}
+
+// Reversible reports whether originals can be encrypted, which storing a redaction requires
+func (r *Redactor) Reversible() bool {
+	return r.cipher != nil
+}
+
+// Detect finds the entities to redact in a transcript
+func (r *Redactor) Detect(ctx context.Context, userID string, transcript *models.Transcript) ([]SegmentResult, error) {
+	terms, err := r.redactionRepo.ListTerms(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(terms))
+	for i, term := range terms {
+		values[i] = term.Term
+	}
+
+	detectors := append(BuiltinDetectors(), NewTermDetector(values))
+	return NewEngine(detectors, r.recognizer).RedactTranscript(ctx, transcript)
+}
+
+// Redacted returns the transcript with PII replaced by placeholders, without storing anything.
+// Chapters are dropped since their titles were generated from the raw text.
+func (r *Redactor) Redacted(ctx context.Context, userID string, transcript *models.Transcript) error {
+	results, err := r.Detect(ctx, userID, transcript)
+	if err != nil {
+		return err
+	}
+	Apply(transcript, results)
+	transcript.Chapters = nil
+	return nil
+}
+
+// sealedOriginal is the plaintext kept encrypted for each redacted segment
+type sealedOriginal struct {
+	Text  string        `json:"text"`
+	Words []models.Word `json:"words,omitempty"`
+}
+
+// Seal prepares detection results for storage, encrypting each segment's original content.
+// The stored entities keep only their placeholders' positions, never the original text.
+func (r *Redactor) Seal(results []SegmentResult) ([]repository.RedactedSegment, error) {
+	if r.cipher == nil {
+		return nil, fmt.Errorf("redaction key not configured")
+	}
+
+	sealed := make([]repository.RedactedSegment, 0, len(results))
+	for _, result := range results {
+		plaintext, err := json.Marshal(sealedOriginal{Text: result.OriginalText, Words: result.OriginalWords})
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, err := r.cipher.Seal(plaintext, result.SegmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		entities := make([]models.RedactedEntity, len(result.Entities))
+		for i, entity := range result.Entities {
+			entity.Original = nil
+			entities[i] = entity
+		}
+
+		sealed = append(sealed, repository.RedactedSegment{
+			SegmentID:  result.SegmentID,
+			Text:       result.Text,
+			Words:      result.Words,
+			Entities:   entities,
+			Ciphertext: ciphertext,
+		})
+	}
+	return sealed, nil
+}
+
+// Open decrypts a stored redaction's original text and word timings
+func (r *Redactor) Open(redaction models.SegmentRedaction) (string, []models.Word, error) {
+	if r.cipher == nil {
+		return "", nil, fmt.Errorf("redaction key not configured")
+	}
+
+	plaintext, err := r.cipher.Open(redaction.Ciphertext, redaction.SegmentID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var original sealedOriginal
+	if err := json.Unmarshal(plaintext, &original); err != nil {
+		return "", nil, fmt.Errorf("failed to read redacted original: %w", err)
+	}
+	return original.Text, original.Words, nil
+}
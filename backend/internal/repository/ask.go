@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type AskRepository struct {
+	db *database.DB
+}
+
+func NewAskRepository(db *database.DB) *AskRepository {
+	return &AskRepository{db: db}
+}
+
+// AddExchange appends a question and its answer to a file's conversation in one transaction, so a
+// question is never saved without the answer. It returns the saved answer.
+func (r *AskRepository) AddExchange(question, answer *models.AskMessage) (*models.AskMessage, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := addMessage(tx, question); err != nil {
+		return nil, err
+	}
+	saved, err := addMessage(tx, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit conversation")
+	}
+
+	return saved, nil
+}
+
+func addMessage(q queryRower, message *models.AskMessage) (*models.AskMessage, error) {
+	citations := message.Citations
+	if citations == nil {
+		citations = []models.Citation{}
+	}
+	encoded, err := json.Marshal(citations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid citations: %w", err)
+	}
+
+	// clock_timestamp() rather than NOW() so the answer sorts after the question saved in the
+	// same transaction
+	query := `
+		INSERT INTO ask_messages (file_id, user_id, role, content, citations, created_at)
+		VALUES ($1, $2, $3, $4, $5, clock_timestamp())
+		RETURNING id, file_id, user_id, role, content, citations, created_at
+	`
+
+	saved, err := scanAskMessage(q.QueryRow(query, message.FileID, message.UserID, message.Role, message.Content, encoded))
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to save message")
+	}
+
+	return saved, nil
+}
+
+// GetHistory returns the most recent turns of a file's conversation, oldest first
+func (r *AskRepository) GetHistory(fileID, userID string, limit int) ([]models.AskMessage, error) {
+	query := `
+		SELECT id, file_id, user_id, role, content, citations, created_at
+		FROM (
+			SELECT id, file_id, user_id, role, content, citations, created_at
+			FROM ask_messages
+			WHERE file_id = $1 AND user_id = $2
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3
+		) recent
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(query, fileID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve conversation")
+	}
+	defer rows.Close()
+
+	messages := []models.AskMessage{}
+	for rows.Next() {
+		message, err := scanAskMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read conversation message")
+		}
+		messages = append(messages, *message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating conversation: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ClearHistory deletes a file's conversation
+func (r *AskRepository) ClearHistory(fileID, userID string) error {
+	_, err := r.db.Exec(`DELETE FROM ask_messages WHERE file_id = $1 AND user_id = $2`, fileID, userID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to clear conversation")
+	}
+	return nil
+}
+
+func scanAskMessage(row interface{ Scan(...interface{}) error }) (*models.AskMessage, error) {
+	var message models.AskMessage
+	var citations []byte
+	err := row.Scan(
+		&message.ID,
+		&message.FileID,
+		&message.UserID,
+		&message.Role,
+		&message.Content,
+		&citations,
+		&message.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(citations) > 0 {
+		if err := json.Unmarshal(citations, &message.Citations); err != nil {
+			return nil, err
+		}
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+const chapterColumns = `id, transcript_id, chapter_index, title, start_ms, end_ms, start_segment_id, generator, created_at`
+
+func scanChapter(row interface{ Scan(...interface{}) error }) (*models.Chapter, error) {
+	var chapter models.Chapter
+	err := row.Scan(
+		&chapter.ID,
+		&chapter.TranscriptID,
+		&chapter.Index,
+		&chapter.Title,
+		&chapter.StartMs,
+		&chapter.EndMs,
+		&chapter.StartSegmentID,
+		&chapter.Generator,
+		&chapter.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// GetChapters retrieves a transcript's chapters in order
+func (r *TranscriptRepository) GetChapters(transcriptID string) ([]models.Chapter, error) {
+	query := `
+		SELECT ` + chapterColumns + `
+		FROM transcript_chapters
+		WHERE transcript_id = $1
+		ORDER BY chapter_index
+	`
+
+	rows, err := r.db.Query(query, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve chapters")
+	}
+	defer rows.Close()
+
+	chapters := []models.Chapter{}
+	for rows.Next() {
+		chapter, err := scanChapter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read chapter information")
+		}
+		chapters = append(chapters, *chapter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chapters: %w", err)
+	}
+
+	return chapters, nil
+}
+
+// SaveChapters replaces a transcript's chapters
+func (r *TranscriptRepository) SaveChapters(transcriptID string, chapters []models.Chapter) ([]models.Chapter, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM transcript_chapters WHERE transcript_id = $1`, transcriptID); err != nil {
+		return nil, fmt.Errorf("database error: failed to clear chapters")
+	}
+
+	saved := make([]models.Chapter, 0, len(chapters))
+	for i, chapter := range chapters {
+		stored, err := scanChapter(tx.QueryRow(`
+			INSERT INTO transcript_chapters (transcript_id, chapter_index, title, start_ms, end_ms, start_segment_id, generator, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			RETURNING `+chapterColumns,
+			transcriptID, i, chapter.Title, chapter.StartMs, chapter.EndMs, chapter.StartSegmentID, chapter.Generator,
+		))
+		if err != nil {
+			if strings.Contains(err.Error(), "connection") {
+				return nil, fmt.Errorf("database connection error: unable to connect to database")
+			}
+			return nil, fmt.Errorf("database error: failed to save chapter")
+		}
+		saved = append(saved, *stored)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit chapters")
+	}
+
+	return saved, nil
+}
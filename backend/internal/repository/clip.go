@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type ClipRepository struct {
+	db *database.DB
+}
+
+func NewClipRepository(db *database.DB) *ClipRepository {
+	return &ClipRepository{db: db}
+}
+
+const clipColumns = `c.id, c.file_id, c.user_id, c.title, c.note, c.start_ms, c.end_ms, c.created_at, c.updated_at`
+
+func scanClip(row interface{ Scan(...interface{}) error }) (*models.Clip, error) {
+	var clip models.Clip
+	err := row.Scan(
+		&clip.ID,
+		&clip.FileID,
+		&clip.UserID,
+		&clip.Title,
+		&clip.Note,
+		&clip.StartMs,
+		&clip.EndMs,
+		&clip.CreatedAt,
+		&clip.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &clip, nil
+}
+
+// CreateClip cuts a clip from one of the user's files
+func (r *ClipRepository) CreateClip(clip *models.Clip) (*models.Clip, error) {
+	query := `
+		INSERT INTO clips (file_id, user_id, title, note, start_ms, end_ms, created_at, updated_at)
+		SELECT f.id, f.user_id, $3, $4, $5, $6, NOW(), NOW()
+		FROM files f
+		WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+		RETURNING id, file_id, user_id, title, note, start_ms, end_ms, created_at, updated_at
+	`
+
+	created, err := scanClip(r.db.QueryRow(query, clip.FileID, clip.UserID, clip.Title, clip.Note, clip.StartMs, clip.EndMs))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("file not found")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to create clip")
+	}
+
+	return created, nil
+}
+
+// ListClips returns a file's clips in timeline order
+func (r *ClipRepository) ListClips(fileID, userID string) ([]models.Clip, error) {
+	query := `
+		SELECT ` + clipColumns + `
+		FROM clips c
+		INNER JOIN files f ON f.id = c.file_id
+		WHERE c.file_id = $1 AND c.user_id = $2 AND f.deleted_at IS NULL
+		ORDER BY c.start_ms, c.created_at
+	`
+
+	rows, err := r.db.Query(query, fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve clips")
+	}
+	defer rows.Close()
+
+	clips := []models.Clip{}
+	for rows.Next() {
+		clip, err := scanClip(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read clip information")
+		}
+		clips = append(clips, *clip)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating clips: %w", err)
+	}
+
+	return clips, nil
+}
+
+// GetClipByID retrieves a clip by ID for a specific user
+func (r *ClipRepository) GetClipByID(clipID, userID string) (*models.Clip, error) {
+	query := `
+		SELECT ` + clipColumns + `
+		FROM clips c
+		INNER JOIN files f ON f.id = c.file_id
+		WHERE c.id = $1 AND c.user_id = $2 AND f.deleted_at IS NULL
+	`
+
+	clip, err := scanClip(r.db.QueryRow(query, clipID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve clip")
+	}
+
+	return clip, nil
+}
+
+// UpdateClip changes a clip's title, note or range. An empty note clears it.
+func (r *ClipRepository) UpdateClip(clipID, userID string, title, note *string, startMs, endMs *int64) (*models.Clip, error) {
+	query := `
+		UPDATE clips c
+		SET title = COALESCE($3, c.title),
+			note = CASE WHEN $4::text IS NULL THEN c.note ELSE NULLIF($4, '') END,
+			start_ms = COALESCE($5, c.start_ms),
+			end_ms = COALESCE($6, c.end_ms),
+			updated_at = NOW()
+		FROM files f
+		WHERE c.id = $1 AND c.user_id = $2 AND f.id = c.file_id AND f.deleted_at IS NULL
+		RETURNING ` + clipColumns
+
+	clip, err := scanClip(r.db.QueryRow(query, clipID, userID, title, note, startMs, endMs))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("clip not found")
+		}
+		if strings.Contains(err.Error(), "check constraint") {
+			return nil, fmt.Errorf("invalid clip range: end must be after start")
+		}
+		return nil, fmt.Errorf("database error: failed to update clip")
+	}
+
+	return clip, nil
+}
+
+// DeleteClip removes a clip
+func (r *ClipRepository) DeleteClip(clipID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM clips WHERE id = $1 AND user_id = $2`, clipID, userID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to delete clip")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: failed to check deletion result")
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("clip not found")
+	}
+
+	return nil
+}
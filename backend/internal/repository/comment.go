@@ -0,0 +1,488 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type CommentRepository struct {
+	db *database.DB
+}
+
+func NewCommentRepository(db *database.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+const commentColumns = `c.id, c.transcript_id, c.user_id, c.parent_id, c.segment_id, c.start_ms, c.end_ms, c.body, c.resolved_at, c.resolved_by, c.edited_at, c.created_at, c.updated_at`
+
+func scanComment(row interface{ Scan(...interface{}) error }) (*models.Comment, error) {
+	var comment models.Comment
+	err := row.Scan(
+		&comment.ID,
+		&comment.TranscriptID,
+		&comment.UserID,
+		&comment.ParentID,
+		&comment.SegmentID,
+		&comment.StartMs,
+		&comment.EndMs,
+		&comment.Body,
+		&comment.ResolvedAt,
+		&comment.ResolvedBy,
+		&comment.EditedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	comment.Mentions = []models.CommentMention{}
+	return &comment, nil
+}
+
+// CreateComment adds a comment and its mentions. A root comment anchored only to a segment takes
+// the segment's time range; one anchored only to a time range is attached to the segment it starts in.
+// Replies must target a root comment and carry no anchor of their own.
+func (r *CommentRepository) CreateComment(comment *models.Comment, mentionUserIDs []string) (*models.Comment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if comment.ParentID != nil {
+		var parentOfParent *string
+		err := tx.QueryRow(
+			`SELECT parent_id FROM transcript_comments WHERE id = $1 AND transcript_id = $2`,
+			*comment.ParentID, comment.TranscriptID,
+		).Scan(&parentOfParent)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("parent comment not found")
+			}
+			return nil, fmt.Errorf("database query error: failed to retrieve parent comment")
+		}
+		if parentOfParent != nil {
+			return nil, fmt.Errorf("cannot reply to a reply")
+		}
+		comment.SegmentID, comment.StartMs, comment.EndMs = nil, nil, nil
+	} else if err := resolveAnchor(tx, comment); err != nil {
+		return nil, err
+	}
+
+	var commentID string
+	err = tx.QueryRow(`
+		INSERT INTO transcript_comments (transcript_id, user_id, parent_id, segment_id, start_ms, end_ms, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id
+	`, comment.TranscriptID, comment.UserID, comment.ParentID, comment.SegmentID, comment.StartMs, comment.EndMs, comment.Body).Scan(&commentID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to create comment")
+	}
+
+	if err := replaceMentions(tx, commentID, mentionUserIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit comment")
+	}
+
+	return r.GetComment(commentID, comment.TranscriptID)
+}
+
+// resolveAnchor fills in whichever half of a root comment's anchor (segment or time range) is missing
+func resolveAnchor(tx *sql.Tx, comment *models.Comment) error {
+	if comment.SegmentID != nil {
+		var startMs, endMs int64
+		err := tx.QueryRow(
+			`SELECT start_ms, end_ms FROM transcript_segments WHERE id = $1 AND transcript_id = $2`,
+			*comment.SegmentID, comment.TranscriptID,
+		).Scan(&startMs, &endMs)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("segment not found")
+			}
+			return fmt.Errorf("database query error: failed to retrieve segment")
+		}
+		if comment.StartMs == nil {
+			comment.StartMs = &startMs
+		}
+		if comment.EndMs == nil {
+			comment.EndMs = &endMs
+		}
+		return nil
+	}
+
+	if comment.StartMs == nil || comment.EndMs == nil {
+		return fmt.Errorf("comment anchor requires a segment or a time range")
+	}
+
+	var segmentID string
+	err := tx.QueryRow(`
+		SELECT id FROM transcript_segments
+		WHERE transcript_id = $1 AND start_ms <= $2
+		ORDER BY start_ms DESC, id
+		LIMIT 1
+	`, comment.TranscriptID, *comment.StartMs).Scan(&segmentID)
+	if err == nil {
+		comment.SegmentID = &segmentID
+	} else if err != sql.ErrNoRows {
+		return fmt.Errorf("database query error: failed to retrieve segment")
+	}
+	return nil
+}
+
+// replaceMentions sets the users mentioned in a comment
+func replaceMentions(tx *sql.Tx, commentID string, userIDs []string) error {
+	if _, err := tx.Exec(`DELETE FROM comment_mentions WHERE comment_id = $1`, commentID); err != nil {
+		return fmt.Errorf("database error: failed to update mentions")
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO comment_mentions (comment_id, user_id, created_at)
+		SELECT $1, unnest($2::text[]), NOW()
+		ON CONFLICT DO NOTHING
+	`, commentID, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("database error: failed to save mentions")
+	}
+	return nil
+}
+
+// GetComment retrieves a single comment with its mentions
+func (r *CommentRepository) GetComment(commentID, transcriptID string) (*models.Comment, error) {
+	comment, err := scanComment(r.db.QueryRow(`
+		SELECT `+commentColumns+`
+		FROM transcript_comments c
+		WHERE c.id = $1 AND c.transcript_id = $2
+	`, commentID, transcriptID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve comment")
+	}
+
+	comments := []*models.Comment{comment}
+	if err := r.attachMentions(comments); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListThreads returns a transcript's root comments in playback order with their replies.
+// status is "open", "resolved" or "" for all threads.
+func (r *CommentRepository) ListThreads(transcriptID, status string) ([]models.Comment, error) {
+	filter := ""
+	switch status {
+	case "open":
+		filter = "AND root.resolved_at IS NULL"
+	case "resolved":
+		filter = "AND root.resolved_at IS NOT NULL"
+	}
+
+	query := `
+		SELECT ` + commentColumns + `
+		FROM transcript_comments c
+		INNER JOIN transcript_comments root ON root.id = COALESCE(c.parent_id, c.id)
+		WHERE c.transcript_id = $1 ` + filter + `
+		ORDER BY root.start_ms NULLS LAST, root.created_at, root.id, c.parent_id NULLS FIRST, c.created_at, c.id
+	`
+
+	rows, err := r.db.Query(query, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve comments")
+	}
+	defer rows.Close()
+
+	var all []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read comment")
+		}
+		all = append(all, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	if err := r.attachMentions(all); err != nil {
+		return nil, err
+	}
+
+	// Rows arrive with each root directly followed by its replies
+	threads := []models.Comment{}
+	for _, comment := range all {
+		if comment.ParentID == nil {
+			threads = append(threads, *comment)
+			continue
+		}
+		if len(threads) > 0 && threads[len(threads)-1].ID == *comment.ParentID {
+			last := &threads[len(threads)-1]
+			last.Replies = append(last.Replies, *comment)
+		}
+	}
+	return threads, nil
+}
+
+// MentionableUsers resolves emails, matched case-insensitively, to the IDs of the active users who
+// can already see the transcript. Anyone else is left out, so a mention reveals nothing about them.
+func (r *CommentRepository) MentionableUsers(transcriptID string, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return []string{}, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT u.id
+		FROM users u
+		WHERE lower(u.email) = ANY($2) AND u.deleted_at IS NULL
+			AND `+transcriptParticipant("$1")+`
+	`, transcriptID, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to resolve mentions")
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read mention")
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// transcriptParticipant matches users u who can see the transcript given by transcriptID: its
+// owner and anyone who has commented on it
+func transcriptParticipant(transcriptID string) string {
+	return fmt.Sprintf(`(
+		u.id = (SELECT user_id FROM transcripts WHERE id = %[1]s)
+		OR EXISTS (SELECT 1 FROM transcript_comments p WHERE p.transcript_id = %[1]s AND p.user_id = u.id)
+	)`, transcriptID)
+}
+
+// attachMentions loads the mentioned users for a set of comments, leaving out anyone who cannot
+// see the comment's transcript
+func (r *CommentRepository) attachMentions(comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Comment, len(comments))
+	ids := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+		ids = append(ids, comment.ID)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT cm.comment_id, u.id, u.name, u.email
+		FROM comment_mentions cm
+		INNER JOIN transcript_comments c ON c.id = cm.comment_id
+		INNER JOIN users u ON u.id = cm.user_id
+		WHERE cm.comment_id = ANY($1::uuid[])
+			AND `+transcriptParticipant("c.transcript_id")+`
+		ORDER BY cm.created_at, u.name
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("database query error: failed to retrieve mentions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID string
+		var mention models.CommentMention
+		if err := rows.Scan(&commentID, &mention.UserID, &mention.Name, &mention.Email); err != nil {
+			return fmt.Errorf("data parsing error: failed to read mention")
+		}
+		if comment, ok := byID[commentID]; ok {
+			comment.Mentions = append(comment.Mentions, mention)
+		}
+	}
+
+	return rows.Err()
+}
+
+// authorOf checks a comment exists on the transcript and returns its author
+func authorOf(tx *sql.Tx, commentID, transcriptID string) (string, *string, error) {
+	var authorID string
+	var parentID *string
+	err := tx.QueryRow(
+		`SELECT user_id, parent_id FROM transcript_comments WHERE id = $1 AND transcript_id = $2 FOR UPDATE`,
+		commentID, transcriptID,
+	).Scan(&authorID, &parentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil, fmt.Errorf("comment not found")
+		}
+		return "", nil, fmt.Errorf("database query error: failed to retrieve comment")
+	}
+	return authorID, parentID, nil
+}
+
+// UpdateComment edits a comment's body and mentions; only its author may edit it
+func (r *CommentRepository) UpdateComment(commentID, transcriptID, userID, body string, mentionUserIDs []string) (*models.Comment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	authorID, _, err := authorOf(tx, commentID, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID != userID {
+		return nil, fmt.Errorf("only the author can edit this comment")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE transcript_comments
+		SET body = $1, edited_at = NOW(), updated_at = NOW()
+		WHERE id = $2
+	`, body, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to update comment")
+	}
+
+	if err := replaceMentions(tx, commentID, mentionUserIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit comment")
+	}
+
+	return r.GetComment(commentID, transcriptID)
+}
+
+// DeleteComment removes a comment; deleting a root comment removes its whole thread
+func (r *CommentRepository) DeleteComment(commentID, transcriptID, userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	authorID, _, err := authorOf(tx, commentID, transcriptID)
+	if err != nil {
+		return err
+	}
+	if authorID != userID {
+		return fmt.Errorf("only the author can delete this comment")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM transcript_comments WHERE id = $1`, commentID); err != nil {
+		return fmt.Errorf("database error: failed to delete comment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit deletion")
+	}
+	return nil
+}
+
+// SetResolved resolves or reopens a thread
+func (r *CommentRepository) SetResolved(commentID, transcriptID, userID string, resolved bool) (*models.Comment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, parentID, err := authorOf(tx, commentID, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		return nil, fmt.Errorf("only threads can be resolved, not replies")
+	}
+
+	query := `UPDATE transcript_comments SET resolved_at = NOW(), resolved_by = $2, updated_at = NOW() WHERE id = $1`
+	args := []interface{}{commentID, userID}
+	if !resolved {
+		query = `UPDATE transcript_comments SET resolved_at = NULL, resolved_by = NULL, updated_at = NOW() WHERE id = $1`
+		args = args[:1]
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("database error: failed to update comment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit comment")
+	}
+
+	return r.GetComment(commentID, transcriptID)
+}
+
+// ListMentions returns the comments that mention a user, newest first
+func (r *CommentRepository) ListMentions(userID string, limit, offset int) ([]models.MentionNotice, error) {
+	query := `
+		SELECT t.file_id, ` + commentColumns + `
+		FROM comment_mentions cm
+		INNER JOIN transcript_comments c ON c.id = cm.comment_id
+		INNER JOIN transcripts t ON t.id = c.transcript_id
+		INNER JOIN files f ON f.id = t.file_id
+		WHERE cm.user_id = $1 AND f.deleted_at IS NULL
+		ORDER BY cm.created_at DESC, c.id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve mentions")
+	}
+	defer rows.Close()
+
+	var fileIDs []string
+	var comments []*models.Comment
+	for rows.Next() {
+		var fileID string
+		comment, err := scanComment(prefixedScanner{row: rows, prefix: []interface{}{&fileID}})
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read comment")
+		}
+		fileIDs = append(fileIDs, fileID)
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating mentions: %w", err)
+	}
+
+	if err := r.attachMentions(comments); err != nil {
+		return nil, err
+	}
+
+	notices := make([]models.MentionNotice, len(comments))
+	for i, comment := range comments {
+		notices[i] = models.MentionNotice{FileID: fileIDs[i], Comment: *comment}
+	}
+	return notices, nil
+}
+
+// prefixedScanner scans extra leading columns before handing the rest to a row scanner
+type prefixedScanner struct {
+	row    interface{ Scan(...interface{}) error }
+	prefix []interface{}
+}
+
+func (s prefixedScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(append([]interface{}{}, s.prefix...), dest...)...)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// openTestDB connects to the database in DATABASE_URL, skipping the test when it is not set
+func openTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	db, err := database.New()
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// createTestUser creates a user that is deleted, with its folders, files and jobs, when the test ends
+func createTestUser(t *testing.T, db *database.DB, prefix string) *models.User {
+	t.Helper()
+
+	now := time.Now().UTC()
+	user := &models.User{
+		ID:            fmt.Sprintf("%s-%d", prefix, now.UnixNano()),
+		Email:         fmt.Sprintf("%s-%d@example.com", prefix, now.UnixNano()),
+		Name:          "Test User",
+		Plan:          models.UserPlanFree,
+		Status:        models.UserStatusActive,
+		APIQuotaLimit: 100,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := NewUserRepository(db).CreateUser(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM jobs WHERE user_id = $1`, user.ID)
+		db.Exec(`DELETE FROM files WHERE user_id = $1`, user.ID)
+		db.Exec(`DELETE FROM folders WHERE user_id = $1`, user.ID)
+		db.Exec(`DELETE FROM users WHERE id = $1`, user.ID)
+	})
+	return user
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type EmbeddingRepository struct {
+	db *database.DB
+}
+
+func NewEmbeddingRepository(db *database.DB) *EmbeddingRepository {
+	return &EmbeddingRepository{db: db}
+}
+
+// ReplaceChunks swaps a transcript's stored chunks and embeddings for a freshly computed set
+func (r *EmbeddingRepository) ReplaceChunks(transcriptID string, chunks []models.TranscriptChunk, vectors [][]float32) error {
+	if len(chunks) != len(vectors) {
+		return fmt.Errorf("chunk and embedding counts differ: %d chunks, %d embeddings", len(chunks), len(vectors))
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM transcript_chunks WHERE transcript_id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to clear existing chunks")
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO transcript_chunks (transcript_id, chunk_index, speaker_id, segment_ids, text, start_ms, end_ms, confidence, embedding, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::vector, NOW())
+	`)
+	if err != nil {
+		return fmt.Errorf("database error: failed to prepare chunk insert")
+	}
+	defer stmt.Close()
+
+	for i, chunk := range chunks {
+		_, err := stmt.Exec(
+			transcriptID,
+			chunk.ChunkIndex,
+			chunk.SpeakerID,
+			pq.Array(chunk.SegmentIDs),
+			chunk.Text,
+			chunk.StartMs,
+			chunk.EndMs,
+			chunk.Confidence,
+			vectorLiteral(vectors[i]),
+		)
+		if err != nil {
+			return fmt.Errorf("database error: failed to store chunk %d: %w", chunk.ChunkIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit chunks")
+	}
+
+	return nil
+}
+
+// SearchSimilar returns the user's chunks closest to the query vector by cosine similarity
+func (r *EmbeddingRepository) SearchSimilar(userID string, vector []float32, scope SearchScope, limit int, minSimilarity float64) ([]models.SemanticHit, error) {
+	sqlQuery := `
+		SELECT c.id, c.segment_ids, c.transcript_id, t.file_id, f.name, f.folder_id,
+			COALESCE(sp.name, sp.label, ''),
+			c.text, c.start_ms, c.end_ms,
+			1 - (c.embedding <=> $2::vector) AS similarity
+		FROM transcript_chunks c
+		INNER JOIN transcripts t ON t.id = c.transcript_id
+		INNER JOIN files f ON f.id = t.file_id
+		LEFT JOIN transcript_speakers sp ON sp.id = c.speaker_id
+		WHERE t.user_id = $1 AND f.deleted_at IS NULL
+	`
+	args := []interface{}{userID, vectorLiteral(vector)}
+
+	if scope.FileID != "" {
+		args = append(args, scope.FileID)
+		sqlQuery += fmt.Sprintf(" AND f.id = $%d", len(args))
+	}
+
+	if scope.FolderID != "" {
+		args = append(args, scope.FolderID)
+		sqlQuery += " AND " + folderSubtreeCondition("f.folder_id", len(args))
+	}
+
+	args = append(args, minSimilarity, limit)
+	sqlQuery += fmt.Sprintf(`
+		AND 1 - (c.embedding <=> $2::vector) >= $%d
+		ORDER BY c.embedding <=> $2::vector
+		LIMIT $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to run similarity search")
+	}
+	defer rows.Close()
+
+	hits := []models.SemanticHit{}
+	for rows.Next() {
+		var hit models.SemanticHit
+		var segmentIDs pq.StringArray
+		err := rows.Scan(
+			&hit.ChunkID,
+			&segmentIDs,
+			&hit.TranscriptID,
+			&hit.FileID,
+			&hit.FileName,
+			&hit.FolderID,
+			&hit.Speaker,
+			&hit.Text,
+			&hit.StartMs,
+			&hit.EndMs,
+			&hit.Similarity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read similarity result")
+		}
+		hit.SegmentIDs = segmentIDs
+		hit.Score = hit.Similarity
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating similarity results: %w", err)
+	}
+
+	return hits, nil
+}
+
+// vectorLiteral formats a vector in pgvector's text representation, e.g. [0.1,0.2]
+func vectorLiteral(vector []float32) string {
+	var b strings.Builder
+	b.Grow(len(vector) * 10)
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
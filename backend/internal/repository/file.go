@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type FileRepository struct {
+	db *database.DB
+}
+
+func NewFileRepository(db *database.DB) *FileRepository {
+	return &FileRepository{db: db}
+}
+
+const fileColumns = `f.id, f.name, f.type, f.size, f.length, f.duration_ms, f.language, f.service, f.tags, f.folder_id, f.user_id, f.storage_key, f.reviewed_at, f.reviewed_by, f.created_at, f.updated_at, f.version, f.deleted_at`
+
+func scanFile(row interface{ Scan(...interface{}) error }) (*models.File, error) {
+	var file models.File
+	var tags pq.StringArray
+	err := row.Scan(
+		&file.ID,
+		&file.Name,
+		&file.Type,
+		&file.Size,
+		&file.Length,
+		&file.DurationMs,
+		&file.Language,
+		&file.Service,
+		&tags,
+		&file.FolderID,
+		&file.UserID,
+		&file.StorageKey,
+		&file.ReviewedAt,
+		&file.ReviewedBy,
+		&file.CreatedAt,
+		&file.UpdatedAt,
+		&file.Version,
+		&file.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	file.Tags = []string(tags)
+	return &file, nil
+}
+
+// lockFileTx locks a live file's row until the transaction ends and enforces an If-Match
+// precondition on it. On a failed precondition it returns the file as it is now with the error, so
+// the caller can hand it back in the 412.
+func lockFileTx(tx *sql.Tx, fileID, userID string, expectedVersions []int64) (*models.File, error) {
+	file, err := scanFile(tx.QueryRow(`
+		SELECT `+fileColumns+`
+		FROM files f
+		WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+		FOR UPDATE
+	`, fileID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("file not found")
+		}
+		return nil, fmt.Errorf("database error: failed to lock file")
+	}
+	if err := checkVersion(file.Version, expectedVersions); err != nil {
+		return file, err
+	}
+	return file, nil
+}
+
+// GetFileByID retrieves a file by ID for a specific user
+func (r *FileRepository) GetFileByID(fileID, userID string) (*models.File, error) {
+	query := `
+		SELECT ` + fileColumns + `
+		FROM files f
+		WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+	`
+
+	file, err := scanFile(r.db.QueryRow(query, fileID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve file")
+	}
+
+	return file, nil
+}
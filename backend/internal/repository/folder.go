@@ -17,24 +17,39 @@ func NewFolderRepository(db *database.DB) *FolderRepository {
 	return &FolderRepository{db: db}
 }
 
-// GetFolderByID retrieves a folder by its ID and user ID
-func (r *FolderRepository) GetFolderByID(folderID, userID string) (*models.Folder, error) {
-	query := `
-		SELECT id, name, parent_id, user_id, created_at, updated_at, deleted_at
-		FROM folders
-		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
-	`
+const folderColumns = `id, name, parent_id, user_id, color, icon, description, pinned, created_at, updated_at, version, deleted_at`
 
+func scanFolder(row interface{ Scan(...interface{}) error }) (*models.Folder, error) {
 	var folder models.Folder
-	err := r.db.QueryRow(query, folderID, userID).Scan(
+	err := row.Scan(
 		&folder.ID,
 		&folder.Name,
 		&folder.ParentID,
 		&folder.UserID,
+		&folder.Color,
+		&folder.Icon,
+		&folder.Description,
+		&folder.Pinned,
 		&folder.CreatedAt,
 		&folder.UpdatedAt,
+		&folder.Version,
 		&folder.DeletedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetFolderByID retrieves a folder by its ID and user ID
+func (r *FolderRepository) GetFolderByID(folderID, userID string) (*models.Folder, error) {
+	query := `
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	folder, err := scanFolder(r.db.QueryRow(query, folderID, userID))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -46,7 +61,7 @@ func (r *FolderRepository) GetFolderByID(folderID, userID string) (*models.Folde
 		return nil, fmt.Errorf("database query error: failed to retrieve folder")
 	}
 
-	return &folder, nil
+	return folder, nil
 }
 
 // GetBreadcrumbs builds the breadcrumb path for a folder
@@ -108,61 +123,8 @@ func (r *FolderRepository) GetBreadcrumbs(folderID, userID string) ([]models.Bre
 	return breadcrumbs, nil
 }
 
-// GetFolderContents retrieves folders and files in a given folder
-func (r *FolderRepository) GetFolderContents(folderID, userID string) (*models.FolderContents, error) {
-
-	contents := &models.FolderContents{
-		Folders: []models.Folder{},
-		Files:   []models.File{},
-	}
-
-	// Get folders only for now
-	var folderQuery string
-	var folderArgs []interface{}
-
-	if folderID == "" {
-		// Root/dashboard level - get folders with no parent (parent_id IS NULL)
-		folderQuery = `SELECT id, name, parent_id, user_id, created_at, updated_at FROM folders WHERE user_id = $1 AND deleted_at IS NULL AND parent_id IS NULL ORDER BY name`
-		folderArgs = []interface{}{userID}
-	} else {
-		// Specific folder - get child folders
-		folderQuery = `SELECT id, name, parent_id, user_id, created_at, updated_at FROM folders WHERE user_id = $1 AND deleted_at IS NULL AND parent_id = $2 ORDER BY name`
-		folderArgs = []interface{}{userID, folderID}
-	}
-
-	rows, err := r.db.Query(folderQuery, folderArgs...)
-	if err != nil {
-		if strings.Contains(err.Error(), "connection") {
-			return nil, fmt.Errorf("database connection error: unable to connect to database")
-		}
-		return nil, fmt.Errorf("database query error: failed to retrieve folders")
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var folder models.Folder
-		err := rows.Scan(
-			&folder.ID,
-			&folder.Name,
-			&folder.ParentID,
-			&folder.UserID,
-			&folder.CreatedAt,
-			&folder.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("data parsing error: failed to read folder information")
-		}
-		contents.Folders = append(contents.Folders, folder)
-	}
-
-	// TODO: Add files later - for now only handling folders
-	// Files array will remain empty but that's fine for dashboard
-
-	return contents, nil
-}
-
 // GetFolderData returns complete folder data including breadcrumbs and contents
-func (r *FolderRepository) GetFolderData(folderID, userID string) (*models.FolderDataResponse, error) {
+func (r *FolderRepository) GetFolderData(folderID, userID string, query FolderListQuery) (*models.FolderDataResponse, error) {
 	var folder *models.Folder
 	var err error
 
@@ -184,8 +146,8 @@ func (r *FolderRepository) GetFolderData(folderID, userID string) (*models.Folde
 		return nil, err
 	}
 
-	// Get folder contents
-	contents, err := r.GetFolderContents(folderID, userID)
+	// Get one page of folder contents
+	page, err := r.GetFolderContents(folderID, userID, query)
 	if err != nil {
 		return nil, err
 	}
@@ -194,14 +156,15 @@ func (r *FolderRepository) GetFolderData(folderID, userID string) (*models.Folde
 	response := &models.FolderDataResponse{
 		Folder:      folder, // nil for root/dashboard, actual folder object for subfolders
 		Breadcrumbs: breadcrumbs,
-		Contents:    *contents,
+		Contents:    page.Contents,
 		Stats: struct {
 			TotalFiles   int `json:"total_files"`
 			TotalFolders int `json:"total_folders"`
 		}{
-			TotalFiles:   len(contents.Files),
-			TotalFolders: len(contents.Folders),
+			TotalFiles:   page.TotalFiles,
+			TotalFolders: page.TotalFolders,
 		},
+		NextCursor: page.NextCursor,
 	}
 
 	return response, nil
@@ -210,7 +173,7 @@ func (r *FolderRepository) GetFolderData(folderID, userID string) (*models.Folde
 // GetAllUserFolders retrieves all folders for a user (for tree view)
 func (r *FolderRepository) GetAllUserFolders(userID string) ([]models.Folder, error) {
 	query := `
-		SELECT id, name, parent_id, user_id, created_at, updated_at
+		SELECT `+folderColumns+`
 		FROM folders
 		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY name
@@ -227,52 +190,59 @@ func (r *FolderRepository) GetAllUserFolders(userID string) ([]models.Folder, er
 
 	var folders []models.Folder
 	for rows.Next() {
-		var folder models.Folder
-		err := rows.Scan(
-			&folder.ID,
-			&folder.Name,
-			&folder.ParentID,
-			&folder.UserID,
-			&folder.CreatedAt,
-			&folder.UpdatedAt,
-		)
+		folder, err := scanFolder(rows)
 		if err != nil {
 			return nil, fmt.Errorf("data parsing error: failed to read folder information")
 		}
-		folders = append(folders, folder)
+		folders = append(folders, *folder)
 	}
 
 	return folders, nil
 }
 
-// UpdateFolder updates a folder's name
-func (r *FolderRepository) UpdateFolder(folderID, name, userID string) (*models.Folder, error) {
+// UpdateFolder renames a folder, applying policy when a sibling already uses the name. With
+// ConflictMerge the folder is merged into that sibling, which is returned instead. With
+// expectedVersions set the folder must still be at one of those versions.
+func (r *FolderRepository) UpdateFolder(folderID, name, userID string, policy ConflictPolicy, expectedVersions []int64) (*models.Folder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := lockUserFolders(tx, userID); err != nil {
+		return nil, err
+	}
+
 	// First check if the folder exists and belongs to the user
-	existingFolder, err := r.GetFolderByID(folderID, userID)
+	existingFolder, err := lockFolderTx(tx, folderID, userID)
 	if err != nil {
 		return nil, err
 	}
 	if existingFolder == nil {
 		return nil, fmt.Errorf("folder not found")
 	}
+	if err := checkVersion(existingFolder.Version, expectedVersions); err != nil {
+		return nil, err
+	}
+
+	name, mergeInto, err := resolveFolderConflict(tx, userID, existingFolder.ParentID, name, folderID, policy)
+	if err != nil {
+		return nil, err
+	}
+	if mergeInto != "" {
+		return r.commitMerge(tx, userID, folderID, mergeInto)
+	}
 
 	query := `
-		UPDATE folders 
+		UPDATE folders
 		SET name = $1, updated_at = NOW()
 		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
-		RETURNING id, name, parent_id, user_id, created_at, updated_at
+		RETURNING `+folderColumns+`
 	`
-	
-	var folder models.Folder
-	err = r.db.QueryRow(query, name, folderID, userID).Scan(
-		&folder.ID,
-		&folder.Name,
-		&folder.ParentID,
-		&folder.UserID,
-		&folder.CreatedAt,
-		&folder.UpdatedAt,
-	)
-	
+
+	folder, err := scanFolder(tx.QueryRow(query, name, folderID, userID))
+
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
 			return nil, fmt.Errorf("folder already exists: a folder with this name already exists in this location")
@@ -282,95 +252,206 @@ func (r *FolderRepository) UpdateFolder(folderID, name, userID string) (*models.
 		}
 		return nil, fmt.Errorf("database error: failed to update folder")
 	}
-	
-	return &folder, nil
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder update")
+	}
+
+	return folder, nil
+}
+
+// commitMerge merges sourceID into targetID, commits and returns the target folder
+func (r *FolderRepository) commitMerge(tx *sql.Tx, userID, sourceID, targetID string) (*models.Folder, error) {
+	if _, err := mergeFolderInto(tx, userID, sourceID, targetID, false); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE folders SET updated_at = NOW() WHERE id = $1`, targetID); err != nil {
+		return nil, mergeError(err)
+	}
+
+	folder, err := getFolderTx(tx, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("folder not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder merge")
+	}
+
+	return folder, nil
 }
 
-// CreateFolder creates a new folder
-func (r *FolderRepository) CreateFolder(name string, parentID *string, userID string) (*models.Folder, error) {
+// CreateFolder creates a new folder, applying policy when a sibling already uses the name. With
+// ConflictMerge the existing folder is returned and created is false.
+func (r *FolderRepository) CreateFolder(name string, parentID *string, userID string, policy ConflictPolicy) (folder *models.Folder, created bool, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := lockUserFolders(tx, userID); err != nil {
+		return nil, false, err
+	}
+
+	if parentID != nil {
+		parent, err := getFolderTx(tx, *parentID, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		if parent == nil {
+			return nil, false, fmt.Errorf("invalid parent folder: the specified parent folder does not exist")
+		}
+	}
+
+	name, existingID, err := resolveFolderConflict(tx, userID, parentID, name, "", policy)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingID != "" {
+		folder, err := getFolderTx(tx, existingID, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		return folder, false, tx.Commit()
+	}
+
 	query := `
 		INSERT INTO folders (name, parent_id, user_id, created_at, updated_at)
 		VALUES ($1, $2, $3, NOW(), NOW())
-		RETURNING id, name, parent_id, user_id, created_at, updated_at
+		RETURNING `+folderColumns+`
 	`
-	
-	var folder models.Folder
-	err := r.db.QueryRow(query, name, parentID, userID).Scan(
-		&folder.ID,
-		&folder.Name,
-		&folder.ParentID,
-		&folder.UserID,
-		&folder.CreatedAt,
-		&folder.UpdatedAt,
-	)
-	
+
+	folder, err = scanFolder(tx.QueryRow(query, name, parentID, userID))
+
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-			return nil, fmt.Errorf("folder already exists: a folder with this name already exists in this location")
+			return nil, false, fmt.Errorf("folder already exists: a folder with this name already exists in this location")
 		}
 		if strings.Contains(err.Error(), "foreign key") {
-			return nil, fmt.Errorf("invalid parent folder: the specified parent folder does not exist")
+			return nil, false, fmt.Errorf("invalid parent folder: the specified parent folder does not exist")
 		}
 		if strings.Contains(err.Error(), "connection") {
-			return nil, fmt.Errorf("database connection error: unable to connect to database")
+			return nil, false, fmt.Errorf("database connection error: unable to connect to database")
 		}
-		return nil, fmt.Errorf("database error: failed to create folder")
+		return nil, false, fmt.Errorf("database error: failed to create folder")
 	}
-	
-	return &folder, nil
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("database error: failed to commit folder creation")
+	}
+
+	return folder, true, nil
 }
 
-// DeleteFolder soft deletes a folder by setting deleted_at timestamp
-func (r *FolderRepository) DeleteFolder(folderID, userID string) error {
-	// First check if the folder exists and belongs to the user
-	existingFolder, err := r.GetFolderByID(folderID, userID)
+// DeleteFolder soft deletes a folder together with every folder and file below it, in one
+// transaction. Everything deleted shares a deletion batch ID; items that were already deleted keep
+// their own batch. With expectedVersions set the folder must still be at one of those versions.
+func (r *FolderRepository) DeleteFolder(folderID, userID string, expectedVersions []int64) (*models.DeletionResult, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("database error: failed to start transaction")
 	}
-	if existingFolder == nil {
-		return fmt.Errorf("folder not found")
+	defer tx.Rollback()
+
+	result := models.DeletionResult{}
+	var version int64
+	err = tx.QueryRow(`
+		SELECT gen_random_uuid(), version
+		FROM folders
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, folderID, userID).Scan(&result.BatchID, &version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("folder not found")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to delete folder")
+	}
+	if err := checkVersion(version, expectedVersions); err != nil {
+		return nil, err
 	}
 
 	query := `
-		UPDATE folders 
-		SET deleted_at = NOW(), updated_at = NOW()
-		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM folders c INNER JOIN subtree st ON c.parent_id = st.id
+			WHERE c.user_id = $2 AND c.deleted_at IS NULL
+		),
+		deleted_folders AS (
+			UPDATE folders
+			SET deleted_at = NOW(), updated_at = NOW(), deletion_batch_id = $3
+			WHERE id IN (SELECT id FROM subtree)
+			RETURNING id
+		),
+		deleted_files AS (
+			UPDATE files
+			SET deleted_at = NOW(), updated_at = NOW(), deletion_batch_id = $3
+			WHERE folder_id IN (SELECT id FROM subtree) AND user_id = $2 AND deleted_at IS NULL
+			RETURNING id
+		)
+		SELECT (SELECT COUNT(*) FROM deleted_folders), (SELECT COUNT(*) FROM deleted_files)
 	`
-	
-	result, err := r.db.Exec(query, folderID, userID)
+
+	err = tx.QueryRow(query, folderID, userID, result.BatchID).Scan(&result.DeletedFolders, &result.DeletedFiles)
 	if err != nil {
 		if strings.Contains(err.Error(), "connection") {
-			return fmt.Errorf("database connection error: unable to connect to database")
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
 		}
-		return fmt.Errorf("database error: failed to delete folder")
+		return nil, fmt.Errorf("database error: failed to delete folder")
 	}
-	
-	rowsAffected, err := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit deletion")
+	}
+
+	return &result, nil
+}
+
+// MoveFolder moves a folder to a new parent location, applying policy when a folder there already
+// uses its name. With ConflictMerge the folder is merged into that one, which is returned instead.
+// With expectedVersions set the folder must still be at one of those versions.
+func (r *FolderRepository) MoveFolder(folderID, newParentID, userID string, policy ConflictPolicy, expectedVersions []int64) (*models.Folder, error) {
+	// Convert empty string to nil for database
+	var parentID *string
+	if newParentID != "" {
+		parentID = &newParentID
+	}
+
+	// Every check and the update run in one transaction holding the user's folder lock, so two
+	// moves (A into B while B into A) cannot both pass the circular reference check
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("database error: failed to verify deletion")
+		return nil, fmt.Errorf("database error: failed to start transaction")
 	}
-	
-	if rowsAffected == 0 {
-		return fmt.Errorf("folder not found or already deleted")
+	defer tx.Rollback()
+
+	if err := lockUserFolders(tx, userID); err != nil {
+		return nil, err
 	}
-	
-	return nil
-}
 
-// MoveFolder moves a folder to a new parent location
-func (r *FolderRepository) MoveFolder(folderID, newParentID, userID string) (*models.Folder, error) {
 	// First check if the folder exists and belongs to the user
-	existingFolder, err := r.GetFolderByID(folderID, userID)
+	existingFolder, err := lockFolderTx(tx, folderID, userID)
 	if err != nil {
 		return nil, err
 	}
 	if existingFolder == nil {
 		return nil, fmt.Errorf("folder not found")
 	}
+	if err := checkVersion(existingFolder.Version, expectedVersions); err != nil {
+		return nil, err
+	}
 
 	// If newParentID is provided, validate it exists and belongs to the user
 	if newParentID != "" {
-		parentFolder, err := r.GetFolderByID(newParentID, userID)
+		parentFolder, err := getFolderTx(tx, newParentID, userID)
 		if err != nil {
 			return nil, err
 		}
@@ -379,49 +460,56 @@ func (r *FolderRepository) MoveFolder(folderID, newParentID, userID string) (*mo
 		}
 
 		// Prevent circular reference - check if newParentID is a descendant of folderID
-		if err := r.validateNoCircularReference(folderID, newParentID, userID); err != nil {
+		if err := validateNoCircularReference(tx, folderID, newParentID, userID); err != nil {
 			return nil, err
 		}
 	}
 
-	// Convert empty string to nil for database
-	var parentID *string
-	if newParentID != "" {
-		parentID = &newParentID
+	name, mergeInto, err := resolveFolderConflict(tx, userID, parentID, existingFolder.Name, folderID, policy)
+	if err != nil {
+		return nil, err
+	}
+	if mergeInto != "" {
+		return r.commitMerge(tx, userID, folderID, mergeInto)
 	}
 
 	query := `
-		UPDATE folders 
-		SET parent_id = $1, updated_at = NOW()
-		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
-		RETURNING id, name, parent_id, user_id, created_at, updated_at
+		UPDATE folders
+		SET parent_id = $1, name = $2, updated_at = NOW()
+		WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
+		RETURNING `+folderColumns+`
 	`
-	
-	var folder models.Folder
-	err = r.db.QueryRow(query, parentID, folderID, userID).Scan(
-		&folder.ID,
-		&folder.Name,
-		&folder.ParentID,
-		&folder.UserID,
-		&folder.CreatedAt,
-		&folder.UpdatedAt,
-	)
-	
+
+	folder, err := scanFolder(tx.QueryRow(query, parentID, name, folderID, userID))
+
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("folder not found")
+		}
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+			return nil, fmt.Errorf("folder already exists: a folder with this name already exists in the destination")
+		}
 		if strings.Contains(err.Error(), "foreign key") {
 			return nil, fmt.Errorf("invalid destination folder: the specified parent folder does not exist")
 		}
+		if strings.Contains(err.Error(), "folder cycle") {
+			return nil, fmt.Errorf("cannot move folder into itself or its descendants")
+		}
 		if strings.Contains(err.Error(), "connection") {
 			return nil, fmt.Errorf("database connection error: unable to connect to database")
 		}
 		return nil, fmt.Errorf("database error: failed to move folder")
 	}
-	
-	return &folder, nil
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder move")
+	}
+
+	return folder, nil
 }
 
 // validateNoCircularReference ensures a folder is not moved into itself or its descendants
-func (r *FolderRepository) validateNoCircularReference(folderID, newParentID, userID string) error {
+func validateNoCircularReference(q queryRower, folderID, newParentID, userID string) error {
 	// Use recursive CTE to find all descendants of the folder being moved
 	query := `
 		WITH RECURSIVE descendants AS (
@@ -444,7 +532,7 @@ func (r *FolderRepository) validateNoCircularReference(folderID, newParentID, us
 	`
 	
 	var count int
-	err := r.db.QueryRow(query, folderID, userID, newParentID).Scan(&count)
+	err := q.QueryRow(query, folderID, userID, newParentID).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("failed to validate move operation: %w", err)
 	}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// ConflictPolicy decides what happens when a folder is created, renamed, moved or copied onto a
+// name a live sibling already uses. Names are compared case-insensitively, like the listing order
+// and the uniqueness check.
+type ConflictPolicy string
+
+const (
+	// ConflictFail rejects the operation with an "already exists" error
+	ConflictFail ConflictPolicy = "fail"
+	// ConflictRename picks the first free "Name (2)"-style name, or for copies "Name (copy)"
+	ConflictRename ConflictPolicy = "rename"
+	// ConflictMerge merges the folder's contents into the existing one, recursively. Files whose
+	// names are taken in the merged folder are renamed.
+	ConflictMerge ConflictPolicy = "merge"
+)
+
+// ParseConflictPolicy reads a conflict policy, returning fallback when raw is empty
+func ParseConflictPolicy(raw string, fallback ConflictPolicy) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(strings.ToLower(strings.TrimSpace(raw))); policy {
+	case "":
+		return fallback, nil
+	case ConflictFail, ConflictRename, ConflictMerge:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy: must be one of fail, rename or merge")
+	}
+}
+
+// lockUserFolders serializes changes to a user's folder names and parents for the rest of the
+// transaction, so two requests cannot both decide the same name is free or that a move is safe. The
+// folders_prevent_cycle trigger takes the same lock.
+func lockUserFolders(tx *sql.Tx, userID string) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('folders:' || $1))`, userID); err != nil {
+		return fmt.Errorf("database error: failed to lock folders")
+	}
+	return nil
+}
+
+// getFolderTx returns a live folder, or nil when it does not exist
+func getFolderTx(q queryRower, folderID, userID string) (*models.Folder, error) {
+	return queryFolder(q, "", folderID, userID)
+}
+
+// lockFolderTx is getFolderTx that also locks the folder's row until the transaction ends
+func lockFolderTx(tx *sql.Tx, folderID, userID string) (*models.Folder, error) {
+	return queryFolder(tx, "FOR UPDATE", folderID, userID)
+}
+
+func queryFolder(q queryRower, lock, folderID, userID string) (*models.Folder, error) {
+	folder, err := scanFolder(q.QueryRow(`
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		`+lock, folderID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve folder")
+	}
+	return folder, nil
+}
+
+// resolveFolderConflict applies policy to putting a folder named name in parentID (the root when
+// nil). It returns the name to use, or with ConflictMerge the ID of the existing folder to merge
+// into. selfID is the folder being renamed or moved, which never conflicts with itself.
+func resolveFolderConflict(tx *sql.Tx, userID string, parentID *string, name, selfID string, policy ConflictPolicy) (string, string, error) {
+	return resolveNameConflict(tx, userID, parentID, name, selfID, policy, func(taken map[string]string) string {
+		return nextFreeName(taken, name, false)
+	})
+}
+
+// resolveCopyConflict is resolveFolderConflict for a copy, which ConflictRename names with
+// " (copy)" and " (copy n)" suffixes
+func resolveCopyConflict(tx *sql.Tx, userID string, parentID *string, name string, policy ConflictPolicy) (string, string, error) {
+	return resolveNameConflict(tx, userID, parentID, name, "", policy, func(taken map[string]string) string {
+		return nextCopyName(taken, name)
+	})
+}
+
+func resolveNameConflict(tx *sql.Tx, userID string, parentID *string, name, selfID string, policy ConflictPolicy, rename func(map[string]string) string) (string, string, error) {
+	names, err := queryNames(tx, `
+		SELECT id, name FROM folders
+		WHERE user_id = $1 AND parent_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL
+			AND id IS DISTINCT FROM NULLIF($3, '')::uuid
+		ORDER BY created_at, id
+	`, userID, parentID, selfID)
+	if err != nil {
+		return "", "", err
+	}
+
+	existingID, taken := names.byName[strings.ToLower(name)]
+	if !taken {
+		return name, "", nil
+	}
+
+	switch policy {
+	case ConflictRename:
+		return rename(names.byName), "", nil
+	case ConflictMerge:
+		return name, existingID, nil
+	default:
+		return "", "", fmt.Errorf("folder already exists: a folder with this name already exists in this location")
+	}
+}
+
+// mergeFolderInto moves the live contents of sourceID into targetID, merging same-named subfolders
+// recursively and renaming files whose names are taken. The emptied source folders are moved to
+// the trash, or deleted outright with discard (for folders created by the same transaction). It
+// returns which folder each merged folder went into.
+func mergeFolderInto(tx *sql.Tx, userID, sourceID, targetID string, discard bool) (map[string]string, error) {
+	merged := map[string]string{sourceID: targetID}
+
+	type child struct {
+		id, name, matchID string
+	}
+	rows, err := tx.Query(`
+		SELECT c.id, c.name, COALESCE(match.id::text, '')
+		FROM folders c
+		LEFT JOIN LATERAL (
+			SELECT t.id FROM folders t
+			WHERE t.user_id = $1 AND t.parent_id = $3 AND t.deleted_at IS NULL AND lower(t.name) = lower(c.name)
+			ORDER BY t.created_at, t.id
+			LIMIT 1
+		) match ON true
+		WHERE c.user_id = $1 AND c.parent_id = $2 AND c.deleted_at IS NULL
+	`, userID, sourceID, targetID)
+	if err != nil {
+		return nil, mergeError(err)
+	}
+	var children []child
+	for rows.Next() {
+		var c child
+		if err := rows.Scan(&c.id, &c.name, &c.matchID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("data parsing error: failed to read folder information")
+		}
+		children = append(children, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, mergeError(err)
+	}
+
+	for _, c := range children {
+		if c.matchID == "" {
+			if _, err := tx.Exec(`UPDATE folders SET parent_id = $1, updated_at = NOW() WHERE id = $2`, targetID, c.id); err != nil {
+				return nil, mergeError(err)
+			}
+			continue
+		}
+		nested, err := mergeFolderInto(tx, userID, c.id, c.matchID, discard)
+		if err != nil {
+			return nil, err
+		}
+		for from, to := range nested {
+			merged[from] = to
+		}
+	}
+
+	taken, err := queryNames(tx, `
+		SELECT id, name FROM files WHERE user_id = $1 AND folder_id = $2 AND deleted_at IS NULL
+	`, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := queryNames(tx, `
+		SELECT id, name FROM files WHERE user_id = $1 AND folder_id = $2 AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`, userID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range incoming.ordered {
+		name := nextFreeName(taken.byName, file.name, true)
+		_, err := tx.Exec(`UPDATE files SET folder_id = $1, name = $2, updated_at = NOW() WHERE id = $3`, targetID, name, file.id)
+		if err != nil {
+			return nil, mergeError(err)
+		}
+	}
+
+	if discard {
+		_, err = tx.Exec(`DELETE FROM folders WHERE id = $1`, sourceID)
+	} else {
+		_, err = tx.Exec(`
+			UPDATE folders
+			SET deleted_at = NOW(), deletion_batch_id = gen_random_uuid(), updated_at = NOW()
+			WHERE id = $1
+		`, sourceID)
+	}
+	if err != nil {
+		return nil, mergeError(err)
+	}
+
+	return merged, nil
+}
+
+type namedRow struct {
+	id, name string
+}
+
+// siblingNames are the rows of a names query, in order and by lowercased name (the first ID per
+// name)
+type siblingNames struct {
+	ordered []namedRow
+	byName  map[string]string
+}
+
+func queryNames(tx *sql.Tx, query string, args ...interface{}) (*siblingNames, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, mergeError(err)
+	}
+	defer rows.Close()
+
+	names := &siblingNames{byName: make(map[string]string)}
+	for rows.Next() {
+		var row namedRow
+		if err := rows.Scan(&row.id, &row.name); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read name")
+		}
+		names.ordered = append(names.ordered, row)
+		if _, ok := names.byName[strings.ToLower(row.name)]; !ok {
+			names.byName[strings.ToLower(row.name)] = row.id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mergeError(err)
+	}
+	return names, nil
+}
+
+// nextFreeName returns name, or the first of "Name (2)", "Name (3)", ... not in taken (which is
+// keyed by lowercased name), and marks it taken. File names keep their extension last
+// ("Interview (2).mp3").
+func nextFreeName(taken map[string]string, name string, isFile bool) string {
+	base, ext := name, ""
+	if isFile {
+		if e := filepath.Ext(name); e != "" && e != name {
+			base, ext = strings.TrimSuffix(name, e), e
+		}
+	}
+
+	return claimFreeName(taken, name, func(n int) string {
+		return fmt.Sprintf("%s (%d)%s", base, n+1, ext)
+	})
+}
+
+// nextCopyName is nextFreeName with " (copy)", " (copy 2)", ... suffixes
+func nextCopyName(taken map[string]string, name string) string {
+	return claimFreeName(taken, name, func(n int) string {
+		if n == 1 {
+			return name + " (copy)"
+		}
+		return fmt.Sprintf("%s (copy %d)", name, n)
+	})
+}
+
+// claimFreeName returns name, or the first candidate(n) for n = 1, 2, ... whose lowercased form is
+// not in taken, and marks it taken
+func claimFreeName(taken map[string]string, name string, candidate func(n int) string) string {
+	free := name
+	for n := 1; ; n++ {
+		if _, ok := taken[strings.ToLower(free)]; !ok {
+			break
+		}
+		free = candidate(n)
+	}
+	taken[strings.ToLower(free)] = ""
+	return free
+}
+
+func mergeError(err error) error {
+	if strings.Contains(err.Error(), "folder cycle") {
+		return fmt.Errorf("cannot move folder into itself or its descendants")
+	}
+	if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+		return fmt.Errorf("folder already exists: a folder with this name already exists in this location")
+	}
+	if strings.Contains(err.Error(), "connection") {
+		return fmt.Errorf("database connection error: unable to connect to database")
+	}
+	return fmt.Errorf("database error: failed to merge folders")
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// CopyFolderOptions chooses what a folder copy includes besides the folder tree
+type CopyFolderOptions struct {
+	IncludeFiles bool
+	// IncludeTranscripts copies each file's transcripts, translations, speakers and chapters. It
+	// requires IncludeFiles.
+	IncludeTranscripts bool
+	// Conflict applies when the destination already has a folder with the same name
+	Conflict ConflictPolicy
+}
+
+// CopyFolder deep-copies a folder and its subfolders under newParentID (the root when nil) in one
+// transaction, optionally with their files and transcripts. options.Conflict decides what happens
+// when the destination already has a folder with the name: ConflictRename gives the copy a
+// " (copy)" suffix, and with ConflictMerge the copy is merged into it and the ID maps point at the
+// folders the copies were merged into. Copies keep the folders' color, icon and description but
+// are not pinned or starred. Copied files share the originals' stored media; derived data such as
+// embeddings, summaries and comments is not copied.
+func (r *FolderRepository) CopyFolder(folderID string, newParentID *string, userID string, options CopyFolderOptions) (*models.FolderCopyResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if err := lockUserFolders(tx, userID); err != nil {
+		return nil, err
+	}
+
+	var name string
+	err = tx.QueryRow(`
+		SELECT name FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`, folderID, userID).Scan(&name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("folder not found")
+		}
+		return nil, copyError(err)
+	}
+
+	if newParentID != nil {
+		var inSubtree sql.NullBool
+		err := tx.QueryRow(`
+			SELECT `+folderSubtreeCondition("id", 2)+`
+			FROM folders
+			WHERE id = $3 AND user_id = $1 AND deleted_at IS NULL
+		`, userID, folderID, *newParentID).Scan(&inSubtree)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("destination folder not found")
+		}
+		if err != nil {
+			return nil, copyError(err)
+		}
+		if inSubtree.Bool {
+			return nil, fmt.Errorf("cannot copy folder into itself or its descendants")
+		}
+	}
+
+	copyName, mergeInto, err := resolveCopyConflict(tx, userID, newParentID, name, options.Conflict)
+	if err != nil {
+		return nil, err
+	}
+	if mergeInto != "" {
+		// Copy under a free name first, then merge the copy into the existing folder
+		if copyName, _, err = resolveCopyConflict(tx, userID, newParentID, name, ConflictRename); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.FolderCopyResult{
+		FolderIDs:     map[string]string{},
+		FileIDs:       map[string]string{},
+		TranscriptIDs: map[string]string{},
+	}
+
+	// Each statement generates the new IDs in a mapping CTE, inserts the copies from it and
+	// returns the old-to-new pairs the next level needs
+	result.FolderIDs, err = copyRows(tx, `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM folders c INNER JOIN subtree st ON c.parent_id = st.id
+			WHERE c.user_id = $2 AND c.deleted_at IS NULL
+		),
+		mapping AS (
+			SELECT id AS old_id, gen_random_uuid() AS new_id FROM subtree
+		),
+		copied AS (
+			INSERT INTO folders (id, name, parent_id, user_id, color, icon, description, created_at, updated_at)
+			SELECT m.new_id,
+				CASE WHEN f.id = $1 THEN $3 ELSE f.name END,
+				CASE WHEN f.id = $1 THEN $4::uuid ELSE parent.new_id END,
+				f.user_id, f.color, f.icon, f.description, NOW(), NOW()
+			FROM mapping m
+			INNER JOIN folders f ON f.id = m.old_id
+			LEFT JOIN mapping parent ON parent.old_id = f.parent_id
+		)
+		SELECT old_id, new_id FROM mapping
+	`, folderID, userID, copyName, newParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.IncludeFiles {
+		oldFolders, newFolders := mappingArrays(result.FolderIDs)
+		result.FileIDs, err = copyRows(tx, `
+			WITH mapping AS (
+				SELECT f.id AS old_id, gen_random_uuid() AS new_id, folder.new_id AS folder_id
+				FROM files f
+				INNER JOIN unnest($2::uuid[], $3::uuid[]) AS folder(old_id, new_id) ON f.folder_id = folder.old_id
+				WHERE f.user_id = $1 AND f.deleted_at IS NULL
+			),
+			copied AS (
+				INSERT INTO files (id, name, type, size, length, duration_ms, language, service, tags, folder_id, user_id, storage_key, created_at, updated_at)
+				SELECT m.new_id, f.name, f.type, f.size, f.length, f.duration_ms, f.language, f.service, f.tags, m.folder_id, f.user_id, f.storage_key, NOW(), NOW()
+				FROM mapping m
+				INNER JOIN files f ON f.id = m.old_id
+			)
+			SELECT old_id, new_id FROM mapping
+		`, userID, oldFolders, newFolders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.IncludeFiles && options.IncludeTranscripts && len(result.FileIDs) > 0 {
+		if result.TranscriptIDs, err = copyTranscripts(tx, result.FileIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if mergeInto != "" {
+		merged, err := mergeFolderInto(tx, userID, result.FolderIDs[folderID], mergeInto, true)
+		if err != nil {
+			return nil, err
+		}
+		for oldID, newID := range result.FolderIDs {
+			if target, ok := merged[newID]; ok {
+				result.FolderIDs[oldID] = target
+			}
+		}
+	}
+
+	folder, err := scanCopiedFolder(tx, result.FolderIDs[folderID])
+	if err != nil {
+		return nil, err
+	}
+	result.Folder = folder
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder copy")
+	}
+
+	return result, nil
+}
+
+// copyTranscripts copies the transcripts of copied files, translations included, with their
+// speakers, segments and chapters
+func copyTranscripts(tx *sql.Tx, fileIDs map[string]string) (map[string]string, error) {
+	oldFiles, newFiles := mappingArrays(fileIDs)
+	transcriptIDs, err := copyRows(tx, `
+		WITH mapping AS (
+			SELECT t.id AS old_id, gen_random_uuid() AS new_id, file.new_id AS file_id
+			FROM transcripts t
+			INNER JOIN unnest($1::uuid[], $2::uuid[]) AS file(old_id, new_id) ON t.file_id = file.old_id
+		),
+		copied AS (
+			INSERT INTO transcripts (id, file_id, user_id, language, source_transcript_id, redacted_at, created_at, updated_at)
+			SELECT m.new_id, m.file_id, t.user_id, t.language, source.new_id, t.redacted_at, NOW(), NOW()
+			FROM mapping m
+			INNER JOIN transcripts t ON t.id = m.old_id
+			LEFT JOIN mapping source ON source.old_id = t.source_transcript_id
+		)
+		SELECT old_id, new_id FROM mapping
+	`, oldFiles, newFiles)
+	if err != nil || len(transcriptIDs) == 0 {
+		return transcriptIDs, err
+	}
+
+	oldTranscripts, newTranscripts := mappingArrays(transcriptIDs)
+	speakerIDs, err := copyRows(tx, `
+		WITH mapping AS (
+			SELECT s.id AS old_id, gen_random_uuid() AS new_id, transcript.new_id AS transcript_id
+			FROM transcript_speakers s
+			INNER JOIN unnest($1::uuid[], $2::uuid[]) AS transcript(old_id, new_id) ON s.transcript_id = transcript.old_id
+		),
+		copied AS (
+			INSERT INTO transcript_speakers (id, transcript_id, label, name, color, created_at, updated_at)
+			SELECT m.new_id, m.transcript_id, s.label, s.name, s.color, NOW(), NOW()
+			FROM mapping m
+			INNER JOIN transcript_speakers s ON s.id = m.old_id
+		)
+		SELECT old_id, new_id FROM mapping
+	`, oldTranscripts, newTranscripts)
+	if err != nil {
+		return nil, err
+	}
+
+	oldSpeakers, newSpeakers := mappingArrays(speakerIDs)
+	_, err = tx.Exec(`
+		WITH mapping AS (
+			SELECT s.id AS old_id, gen_random_uuid() AS new_id, transcript.new_id AS transcript_id
+			FROM transcript_segments s
+			INNER JOIN unnest($1::uuid[], $2::uuid[]) AS transcript(old_id, new_id) ON s.transcript_id = transcript.old_id
+		),
+		speaker AS (
+			SELECT * FROM unnest($3::uuid[], $4::uuid[]) AS speaker(old_id, new_id)
+		),
+		copied_segments AS (
+			INSERT INTO transcript_segments (id, transcript_id, speaker_id, source_segment_id, text, start_ms, end_ms, confidence, words, created_at, updated_at)
+			SELECT m.new_id, m.transcript_id, speaker.new_id, source.new_id, s.text, s.start_ms, s.end_ms, s.confidence, s.words, NOW(), NOW()
+			FROM mapping m
+			INNER JOIN transcript_segments s ON s.id = m.old_id
+			LEFT JOIN speaker ON speaker.old_id = s.speaker_id
+			LEFT JOIN mapping source ON source.old_id = s.source_segment_id
+		)
+		INSERT INTO transcript_chapters (transcript_id, chapter_index, title, start_ms, end_ms, start_segment_id, generator, created_at)
+		SELECT transcript.new_id, c.chapter_index, c.title, c.start_ms, c.end_ms, segment.new_id, c.generator, NOW()
+		FROM transcript_chapters c
+		INNER JOIN unnest($1::uuid[], $2::uuid[]) AS transcript(old_id, new_id) ON c.transcript_id = transcript.old_id
+		LEFT JOIN mapping segment ON segment.old_id = c.start_segment_id
+	`, oldTranscripts, newTranscripts, oldSpeakers, newSpeakers)
+	if err != nil {
+		return nil, copyError(err)
+	}
+
+	return transcriptIDs, nil
+}
+
+// copyRows runs a copy statement returning (old_id, new_id) pairs
+func copyRows(tx *sql.Tx, query string, args ...interface{}) (map[string]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, copyError(err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var oldID, newID string
+		if err := rows.Scan(&oldID, &newID); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read copied ID")
+		}
+		ids[oldID] = newID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, copyError(err)
+	}
+	return ids, nil
+}
+
+// mappingArrays splits an old-to-new ID map into parallel arrays for unnest
+func mappingArrays(ids map[string]string) (interface{}, interface{}) {
+	oldIDs := make([]string, 0, len(ids))
+	newIDs := make([]string, 0, len(ids))
+	for oldID, newID := range ids {
+		oldIDs = append(oldIDs, oldID)
+		newIDs = append(newIDs, newID)
+	}
+	return pq.Array(oldIDs), pq.Array(newIDs)
+}
+
+func scanCopiedFolder(tx *sql.Tx, folderID string) (*models.Folder, error) {
+	folder, err := scanFolder(tx.QueryRow(`
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE id = $1
+	`, folderID))
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve copied folder")
+	}
+	return folder, nil
+}
+
+func copyError(err error) error {
+	if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+		return fmt.Errorf("folder already exists: a folder with this name already exists in the destination")
+	}
+	if strings.Contains(err.Error(), "connection") {
+		return fmt.Errorf("database connection error: unable to connect to database")
+	}
+	return fmt.Errorf("database error: failed to copy folder")
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// FolderListQuery pages, sorts and filters a folder listing. Folders are listed before files; the
+// file-only filters (types, language, tag) leave folders out entirely. With Smart set, the listing
+// is a smart folder's: the files anywhere in the library that match its saved filters.
+type FolderListQuery struct {
+	Sort       models.FolderSort
+	Descending bool
+	Limit      int
+	// Cursor is the next_cursor of the previous page, empty for the first page
+	Cursor        string
+	Types         []string
+	Language      string
+	Tag           string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Smart         *models.SmartFolderFilters
+}
+
+// filtersFiles reports whether the query uses filters only files can match
+func (q FolderListQuery) filtersFiles() bool {
+	return len(q.Types) > 0 || q.Language != "" || q.Tag != "" || q.Smart != nil
+}
+
+// folderCursor is the position after the last item of a page: the item's kind (0 folders,
+// 1 files), its sort value as text, its lowercased name and its ID
+type folderCursor struct {
+	Sort       models.FolderSort `json:"s"`
+	Descending bool              `json:"d"`
+	Kind       int               `json:"k"`
+	Value      string            `json:"v"`
+	Name       string            `json:"n"`
+	ID         string            `json:"i"`
+}
+
+func encodeFolderCursor(cursor folderCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeFolderCursor(encoded string) (*folderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var cursor folderCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// folderSortColumns returns the sort expression for folders and for files and the SQL type a
+// cursor value is cast back to. Folders have no size or duration and sort as -1, like files
+// missing one.
+func folderSortColumns(sort models.FolderSort) (string, string, string) {
+	switch sort {
+	case models.FolderSortCreatedAt:
+		return "created_at", "created_at", "timestamptz"
+	case models.FolderSortUpdatedAt:
+		return "updated_at", "updated_at", "timestamptz"
+	case models.FolderSortSize:
+		return "-1::bigint", "COALESCE(size, -1)", "bigint"
+	case models.FolderSortDuration:
+		return "-1::bigint", "COALESCE(duration_ms, -1)", "bigint"
+	default:
+		return "lower(name)", "lower(name)", "text"
+	}
+}
+
+// folderListConditions builds the WHERE clauses shared by the page and count queries. The user is
+// bound to $1 and the parent folder, if any, to $2. Smart folder listings are not limited to a
+// parent.
+func folderListConditions(folderID string, query FolderListQuery) (string, string, []interface{}) {
+	folderCondition := "user_id = $1 AND deleted_at IS NULL"
+	fileCondition := "user_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{nil}
+	if query.Smart != nil {
+		args = args[:0]
+	} else if folderID == "" {
+		folderCondition += " AND parent_id IS NULL"
+		fileCondition += " AND folder_id IS NULL"
+		args = args[:0]
+	} else {
+		folderCondition += " AND parent_id = $2"
+		fileCondition += " AND folder_id = $2"
+		args[0] = folderID
+	}
+	bind := func(value interface{}) int {
+		args = append(args, value)
+		return len(args) + 1
+	}
+	next := func(value interface{}) string {
+		return fmt.Sprintf("$%d", bind(value))
+	}
+
+	if query.CreatedAfter != nil {
+		condition := " AND created_at >= " + next(*query.CreatedAfter)
+		folderCondition += condition
+		fileCondition += condition
+	}
+	if query.CreatedBefore != nil {
+		condition := " AND created_at < " + next(*query.CreatedBefore)
+		folderCondition += condition
+		fileCondition += condition
+	}
+	if len(query.Types) > 0 {
+		fileCondition += " AND lower(type) = ANY(" + next(pq.Array(query.Types)) + ")"
+	}
+	if query.Language != "" {
+		fileCondition += " AND lower(language) = lower(" + next(query.Language) + ")"
+	}
+	if query.Tag != "" {
+		fileCondition += " AND EXISTS (SELECT 1 FROM unnest(tags) AS tag WHERE lower(tag) = lower(" + next(query.Tag) + "))"
+	}
+	if query.Smart != nil {
+		fileCondition += smartFolderConditions(*query.Smart, time.Now().UTC(), bind)
+	}
+
+	return folderCondition, fileCondition, args
+}
+
+// GetFolderContents returns one page of a folder's children (the root's when folderID is empty),
+// folders first, with the cursor for the next page and the total counts matching the filters
+func (r *FolderRepository) GetFolderContents(folderID, userID string, query FolderListQuery) (*models.FolderContentsPage, error) {
+	var cursor *folderCursor
+	if query.Cursor != "" {
+		var err error
+		if cursor, err = decodeFolderCursor(query.Cursor); err != nil {
+			return nil, err
+		}
+		if cursor.Sort != query.Sort || cursor.Descending != query.Descending {
+			return nil, fmt.Errorf("invalid cursor: it was issued for a different sort order")
+		}
+	}
+
+	folderCondition, fileCondition, filterArgs := folderListConditions(folderID, query)
+	args := append([]interface{}{userID}, filterArgs...)
+
+	contents := &models.FolderContentsPage{}
+
+	err := r.db.QueryRow(`
+		SELECT
+			CASE WHEN $`+fmt.Sprint(len(args)+1)+` THEN 0 ELSE (SELECT COUNT(*) FROM folders WHERE `+folderCondition+`) END,
+			(SELECT COUNT(*) FROM files WHERE `+fileCondition+`)
+	`, append(args, query.filtersFiles())...).Scan(&contents.TotalFolders, &contents.TotalFiles)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to count folder contents")
+	}
+
+	folderSort, fileSort, sortType := folderSortColumns(query.Sort)
+	items := `SELECT 1 AS kind, id, lower(name) AS name_key, ` + fileSort + ` AS sort_value FROM files WHERE ` + fileCondition
+	if !query.filtersFiles() {
+		items = `SELECT 0 AS kind, id, lower(name) AS name_key, ` + folderSort + ` AS sort_value FROM folders WHERE ` + folderCondition + `
+			UNION ALL
+			` + items
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	pageArgs := append([]interface{}{}, args...)
+	position := ""
+	if cursor != nil {
+		pageArgs = append(pageArgs, cursor.Kind, cursor.Value, cursor.Name, cursor.ID)
+		n := len(pageArgs)
+		position = fmt.Sprintf(`WHERE kind > $%[1]d OR (kind = $%[1]d AND (sort_value, name_key, id) %[5]s ($%[2]d::%[6]s, $%[3]d, $%[4]d::uuid))`,
+			n-3, n-2, n-1, n, comparison, sortType)
+	}
+	pageArgs = append(pageArgs, query.Limit+1)
+
+	rows, err := r.db.Query(`
+		SELECT kind, id, sort_value::text, name_key
+		FROM (`+items+`) items
+		`+position+`
+		ORDER BY kind, sort_value `+direction+`, name_key `+direction+`, id `+direction+`
+		LIMIT $`+fmt.Sprint(len(pageArgs)), pageArgs...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve folder contents")
+	}
+	defer rows.Close()
+
+	var page []folderCursor
+	for rows.Next() {
+		item := folderCursor{Sort: query.Sort, Descending: query.Descending}
+		if err := rows.Scan(&item.Kind, &item.ID, &item.Value, &item.Name); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read folder contents")
+		}
+		page = append(page, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating folder contents: %w", err)
+	}
+
+	if len(page) > query.Limit {
+		page = page[:query.Limit]
+		next := encodeFolderCursor(page[len(page)-1])
+		contents.NextCursor = &next
+	}
+
+	var folderIDs, fileIDs []string
+	for _, item := range page {
+		if item.Kind == 0 {
+			folderIDs = append(folderIDs, item.ID)
+		} else {
+			fileIDs = append(fileIDs, item.ID)
+		}
+	}
+	if contents.Contents.Folders, err = r.foldersByID(folderIDs); err != nil {
+		return nil, err
+	}
+	if contents.Contents.Files, err = r.filesByID(fileIDs); err != nil {
+		return nil, err
+	}
+
+	return contents, nil
+}
+
+// foldersByID loads folders, keeping the order of ids
+func (r *FolderRepository) foldersByID(ids []string) ([]models.Folder, error) {
+	folders := make([]models.Folder, 0, len(ids))
+	if len(ids) == 0 {
+		return folders, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], id)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve folders")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read folder information")
+		}
+		folders = append(folders, *folder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+// filesByID loads files, keeping the order of ids
+func (r *FolderRepository) filesByID(ids []string) ([]models.File, error) {
+	files := make([]models.File, 0, len(ids))
+	if len(ids) == 0 {
+		return files, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+fileColumns+`
+		FROM files f
+		WHERE f.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], f.id)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve files")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read file information")
+		}
+		files = append(files, *file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating files: %w", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// FolderMetadataUpdate changes a folder's appearance. Nil fields are left alone and empty strings
+// clear the field.
+type FolderMetadataUpdate struct {
+	Color       *string
+	Icon        *string
+	Description *string
+}
+
+// UpdateFolderMetadata sets a folder's color, icon and description. With expectedVersions set the
+// folder must still be at one of those versions.
+func (r *FolderRepository) UpdateFolderMetadata(folderID, userID string, update FolderMetadataUpdate, expectedVersions []int64) (*models.Folder, error) {
+	return r.updateFolder(folderID, userID, expectedVersions, func(folder *models.Folder) {
+		if update.Color != nil {
+			folder.Color = emptyToNil(*update.Color)
+		}
+		if update.Icon != nil {
+			folder.Icon = emptyToNil(*update.Icon)
+		}
+		if update.Description != nil {
+			folder.Description = emptyToNil(*update.Description)
+		}
+	})
+}
+
+// SetFolderPinned pins or unpins a folder. With expectedVersions set the folder must still be at
+// one of those versions.
+func (r *FolderRepository) SetFolderPinned(folderID, userID string, pinned bool, expectedVersions []int64) (*models.Folder, error) {
+	return r.updateFolder(folderID, userID, expectedVersions, func(folder *models.Folder) {
+		folder.Pinned = pinned
+	})
+}
+
+// updateFolder applies change to the folder's metadata with its row locked
+func (r *FolderRepository) updateFolder(folderID, userID string, expectedVersions []int64, change func(*models.Folder)) (*models.Folder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	folder, err := lockFolderTx(tx, folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("folder not found")
+	}
+	if err := checkVersion(folder.Version, expectedVersions); err != nil {
+		return nil, err
+	}
+
+	change(folder)
+	updated, err := scanFolder(tx.QueryRow(`
+		UPDATE folders
+		SET color = $1, icon = $2, description = $3, pinned = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING `+folderColumns+`
+	`, folder.Color, folder.Icon, folder.Description, folder.Pinned, folder.ID))
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to update folder")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder update")
+	}
+
+	return updated, nil
+}
+
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
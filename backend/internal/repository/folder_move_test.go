@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestMoveFolderConcurrentSwap moves A into B while moving B into A. The user's folder lock must
+// let exactly one of the moves through and leave no cycle behind.
+func TestMoveFolderConcurrentSwap(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "test-move")
+
+	repo := NewFolderRepository(db)
+	for round := 0; round < 20; round++ {
+		a, _, err := repo.CreateFolder(fmt.Sprintf("A %d", round), nil, user.ID, ConflictFail)
+		if err != nil {
+			t.Fatalf("create folder A: %v", err)
+		}
+		b, _, err := repo.CreateFolder(fmt.Sprintf("B %d", round), nil, user.ID, ConflictFail)
+		if err != nil {
+			t.Fatalf("create folder B: %v", err)
+		}
+
+		start := make(chan struct{})
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i, move := range [][2]string{{a.ID, b.ID}, {b.ID, a.ID}} {
+			wg.Add(1)
+			go func(i int, folderID, parentID string) {
+				defer wg.Done()
+				<-start
+				_, errs[i] = repo.MoveFolder(folderID, parentID, user.ID, ConflictFail, nil)
+			}(i, move[0], move[1])
+		}
+		close(start)
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("round %d: %d moves succeeded, want exactly 1 (errors: %v, %v)", round, succeeded, errs[0], errs[1])
+		}
+
+		var cycles int
+		err = db.QueryRow(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id AS start_id, parent_id AS id, 1 AS depth
+				FROM folders
+				WHERE user_id = $1 AND parent_id IS NOT NULL
+				UNION ALL
+				SELECT a.start_id, f.parent_id, a.depth + 1
+				FROM ancestors a
+				INNER JOIN folders f ON f.id = a.id
+				WHERE f.parent_id IS NOT NULL AND a.id <> a.start_id AND a.depth < 100
+			)
+			SELECT COUNT(*) FROM ancestors WHERE id = start_id
+		`, user.ID).Scan(&cycles)
+		if err != nil {
+			t.Fatalf("check cycles: %v", err)
+		}
+		if cycles != 0 {
+			t.Fatalf("round %d: %d folders are their own ancestor", round, cycles)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+const maxFolderPathDepth = 64
+
+// SplitFolderPath splits a path like /Clients/Acme/2025 into folder names. Leading, trailing and
+// repeated slashes are ignored, so "" and "/" are the root.
+func SplitFolderPath(path string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(path, "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "." || name == ".." {
+			return nil, fmt.Errorf("invalid path: '.' and '..' are not supported")
+		}
+		if len(name) > 255 {
+			return nil, fmt.Errorf("invalid path: folder names must be less than 255 characters")
+		}
+		names = append(names, name)
+	}
+	if len(names) > maxFolderPathDepth {
+		return nil, fmt.Errorf("invalid path: at most %d levels are supported", maxFolderPathDepth)
+	}
+	return names, nil
+}
+
+// JoinFolderPath is the inverse of SplitFolderPath
+func JoinFolderPath(names []string) string {
+	return "/" + strings.Join(names, "/")
+}
+
+// resolveFolderPath walks names down from the root, the way GetBreadcrumbs walks up, and returns
+// the deepest folder matched and how many names it matched (0 and nil when not even the first
+// exists). Names match case-insensitively, like sibling names everywhere else; when siblings differ
+// only in case, the deepest match wins, then the oldest folder.
+func resolveFolderPath(q queryRower, userID string, names []string) (*models.Folder, int, error) {
+	if len(names) == 0 {
+		return nil, 0, nil
+	}
+
+	query := `
+		WITH RECURSIVE folder_path AS (
+			-- Base case: the first name among the root folders
+			SELECT id, 1 as level
+			FROM folders
+			WHERE user_id = $1 AND parent_id IS NULL AND deleted_at IS NULL AND lower(name) = lower(($2::text[])[1])
+
+			UNION ALL
+
+			-- Recursive case: the next name among the children
+			SELECT f.id, fp.level + 1
+			FROM folders f
+			INNER JOIN folder_path fp ON f.parent_id = fp.id
+			WHERE f.user_id = $1 AND f.deleted_at IS NULL AND lower(f.name) = lower(($2::text[])[fp.level + 1])
+		)
+		SELECT fp.id, fp.level
+		FROM folder_path fp
+		INNER JOIN folders f ON f.id = fp.id
+		ORDER BY fp.level DESC, f.created_at, f.id
+		LIMIT 1
+	`
+
+	var folderID string
+	var level int
+	err := q.QueryRow(query, userID, pq.Array(names)).Scan(&folderID, &level)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, 0, nil
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, 0, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, 0, fmt.Errorf("database query error: failed to resolve folder path")
+	}
+
+	folder, err := getFolderTx(q, folderID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return folder, level, nil
+}
+
+// GetFolderByPath returns the folder at a path, or nil when any folder along it does not exist
+func (r *FolderRepository) GetFolderByPath(userID string, names []string) (*models.Folder, error) {
+	folder, level, err := resolveFolderPath(r.db, userID, names)
+	if err != nil {
+		return nil, err
+	}
+	if level < len(names) {
+		return nil, nil
+	}
+	return folder, nil
+}
+
+// CreateFolderPath creates the folder at a path together with any missing folders above it, like
+// mkdir -p, in one transaction. Existing folders along the path are reused; creating a path that
+// already exists returns its folder with nothing created.
+func (r *FolderRepository) CreateFolderPath(userID string, names []string) (*models.FolderPathResult, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("invalid path: the root folder cannot be created")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	// Serialize path creation per user so two requests cannot both create the same missing folder
+	if err := lockUserFolders(tx, userID); err != nil {
+		return nil, err
+	}
+
+	folder, level, err := resolveFolderPath(tx, userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names[level:] {
+		var parentID *string
+		if folder != nil {
+			parentID = &folder.ID
+		}
+		if _, _, err := resolveFolderConflict(tx, userID, parentID, name, "", ConflictFail); err != nil {
+			return nil, err
+		}
+
+		created, err := scanFolder(tx.QueryRow(`
+			INSERT INTO folders (name, parent_id, user_id, created_at, updated_at)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			RETURNING `+folderColumns+`
+		`, name, parentID, userID))
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+				return nil, fmt.Errorf("folder already exists: a folder named %q already exists in this location", name)
+			}
+			if strings.Contains(err.Error(), "connection") {
+				return nil, fmt.Errorf("database connection error: unable to connect to database")
+			}
+			return nil, fmt.Errorf("database error: failed to create folder")
+		}
+		folder = created
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit folder creation")
+	}
+
+	return &models.FolderPathResult{
+		Folder:         folder,
+		Path:           JoinFolderPath(names),
+		CreatedFolders: len(names) - level,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// GetFolderTree returns the folders below rootID (the root when nil) down to maxDepth levels,
+// nested, with file counts, bytes and media duration totalled over each folder's whole subtree
+// (including levels below maxDepth). Smart folders are attached to the nodes they appear under; the
+// ones directly below rootID are returned separately.
+func (r *FolderRepository) GetFolderTree(userID string, rootID *string, maxDepth int) ([]*models.FolderTreeNode, []models.SmartFolder, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, parent_id, name, color, icon, pinned, 1 AS depth, ARRAY[id] AS path
+			FROM folders
+			WHERE user_id = $1 AND deleted_at IS NULL AND parent_id IS NOT DISTINCT FROM $2::uuid
+			UNION ALL
+			SELECT c.id, c.parent_id, c.name, c.color, c.icon, c.pinned, t.depth + 1, t.path || c.id
+			FROM folders c
+			INNER JOIN tree t ON c.parent_id = t.id
+			WHERE c.user_id = $1 AND c.deleted_at IS NULL AND NOT c.id = ANY(t.path)
+		),
+		direct AS (
+			SELECT folder_id, COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes, COALESCE(SUM(duration_ms), 0) AS duration_ms
+			FROM files
+			WHERE user_id = $1 AND deleted_at IS NULL AND folder_id IN (SELECT id FROM tree)
+			GROUP BY folder_id
+		),
+		-- Each folder adds its files to every listed ancestor on its path, in one pass over the tree
+		totals AS (
+			SELECT a.id, SUM(d.files) AS files, SUM(d.bytes) AS bytes, SUM(d.duration_ms) AS duration_ms
+			FROM tree x
+			INNER JOIN direct d ON d.folder_id = x.id
+			CROSS JOIN LATERAL unnest(x.path[1:$3]) AS a(id)
+			GROUP BY a.id
+		),
+		children AS (
+			SELECT parent_id AS id, COUNT(*) AS folders
+			FROM tree
+			GROUP BY parent_id
+		)
+		SELECT t.id, t.parent_id, t.name, t.color, t.icon, t.pinned, t.depth,
+			COALESCE(c.folders, 0),
+			COALESCE(d.files, 0),
+			COALESCE(s.files, 0),
+			COALESCE(s.bytes, 0),
+			COALESCE(s.duration_ms, 0)
+		FROM tree t
+		LEFT JOIN children c ON c.id = t.id
+		LEFT JOIN direct d ON d.folder_id = t.id
+		LEFT JOIN totals s ON s.id = t.id
+		WHERE t.depth <= $3
+		ORDER BY t.depth, lower(t.name), t.id
+	`
+
+	rows, err := r.db.Query(query, userID, rootID, maxDepth)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, nil, fmt.Errorf("database query error: failed to retrieve folder tree")
+	}
+	defer rows.Close()
+
+	// Rows come parents first, so each node's parent is already in the map
+	nodes := []*models.FolderTreeNode{}
+	byID := make(map[string]*models.FolderTreeNode)
+	for rows.Next() {
+		node := &models.FolderTreeNode{}
+		err := rows.Scan(
+			&node.ID,
+			&node.ParentID,
+			&node.Name,
+			&node.Color,
+			&node.Icon,
+			&node.Pinned,
+			&node.Depth,
+			&node.FolderCount,
+			&node.FileCount,
+			&node.TotalFiles,
+			&node.TotalBytes,
+			&node.TotalDurationMs,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("data parsing error: failed to read folder tree")
+		}
+		node.HasChildren = node.FolderCount > 0
+		byID[node.ID] = node
+
+		if parent, ok := byID[stringValue(node.ParentID)]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			nodes = append(nodes, node)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating folder tree: %w", err)
+	}
+
+	smartFolders, err := listSmartFolders(r.db, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	topLevel := []models.SmartFolder{}
+	for _, smartFolder := range smartFolders {
+		parentID := stringValue(smartFolder.ParentID)
+		if parentID == stringValue(rootID) {
+			topLevel = append(topLevel, smartFolder)
+		} else if node, ok := byID[parentID]; ok {
+			node.SmartFolders = append(node.SmartFolders, smartFolder)
+			node.HasChildren = true
+		}
+	}
+
+	return nodes, topLevel, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "fmt"
+
+// checkVersion enforces an If-Match precondition on a folder or file: with expectedVersions set,
+// version must be one of them. Callers read version with the row locked so it cannot change before
+// their update.
+func checkVersion(version int64, expectedVersions []int64) error {
+	if expectedVersions == nil {
+		return nil
+	}
+	for _, expected := range expectedVersions {
+		if expected == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("precondition failed: the item has changed (version %d)", version)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type JobRepository struct {
+	db *database.DB
+}
+
+func NewJobRepository(db *database.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, user_id, file_id, type, status, payload, error, attempts, created_at, started_at, finished_at`
+
+func scanJob(row interface{ Scan(...interface{}) error }) (*models.Job, error) {
+	var job models.Job
+	var payload []byte
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&job.FileID,
+		&job.Type,
+		&job.Status,
+		&payload,
+		&job.Error,
+		&job.Attempts,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > 0 {
+		job.Payload = json.RawMessage(payload)
+	}
+	return &job, nil
+}
+
+// CreateJob queues a job of the given type for a user and optional file
+func (r *JobRepository) CreateJob(userID string, fileID *string, jobType models.JobType, payload interface{}) (*models.Job, error) {
+	var encoded []byte
+	if payload != nil {
+		var err error
+		encoded, err = json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job payload: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO jobs (user_id, file_id, type, status, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(query, userID, fileID, jobType, models.JobStatusQueued, encoded))
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			return nil, fmt.Errorf("file not found")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to create job")
+	}
+
+	return job, nil
+}
+
+// GetJobByID retrieves a job owned by the user
+func (r *JobRepository) GetJobByID(jobID, userID string) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1 AND user_id = $2`
+
+	job, err := scanJob(r.db.QueryRow(query, jobID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve job")
+	}
+
+	return job, nil
+}
+
+// GetActiveJob returns a queued or running job of the given type for a file, if any
+func (r *JobRepository) GetActiveJob(fileID, userID string, jobType models.JobType) (*models.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE file_id = $1 AND user_id = $2 AND type = $3 AND status IN ($4, $5)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job, err := scanJob(r.db.QueryRow(query, fileID, userID, jobType, models.JobStatusQueued, models.JobStatusRunning))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve job")
+	}
+
+	return job, nil
+}
+
+// GetActiveJobWithPayload is GetActiveJob restricted to jobs whose payload contains the given fields,
+// e.g. a translation into one particular language
+func (r *JobRepository) GetActiveJobWithPayload(fileID, userID string, jobType models.JobType, payload interface{}) (*models.Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE file_id = $1 AND user_id = $2 AND type = $3 AND status IN ($4, $5) AND payload @> $6::jsonb
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job, err := scanJob(r.db.QueryRow(query, fileID, userID, jobType, models.JobStatusQueued, models.JobStatusRunning, encoded))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve job")
+	}
+
+	return job, nil
+}
+
+// ClaimNextJob marks the oldest claimable job of one of the given types as running and returns it.
+// Claimable jobs are queued ones and running ones started more than staleAfter ago, whose worker
+// crashed or was restarted before finishing them, with attempts left. SKIP LOCKED lets several workers poll the same
+// table without handing out a job twice.
+func (r *JobRepository) ClaimNextJob(types []models.JobType, staleAfter time.Duration, maxAttempts int) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, started_at = NOW(), finished_at = NULL, attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type = ANY($3)
+				AND (status = $2 OR (status = $1 AND started_at < NOW() - $4 * INTERVAL '1 millisecond' AND attempts < $5))
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(query, models.JobStatusRunning, models.JobStatusQueued, pq.Array(jobTypeNames(types)), staleAfter.Milliseconds(), maxAttempts))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database error: failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// FailStaleJobs fails running jobs of the given types that were started more than staleAfter ago
+// and have already been attempted maxAttempts times, so a job that keeps taking its worker down is
+// not retried forever. It returns how many jobs were failed.
+func (r *JobRepository) FailStaleJobs(types []models.JobType, staleAfter time.Duration, maxAttempts int) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE jobs
+		SET status = $1, error = $2, finished_at = NOW()
+		WHERE status = $3 AND type = ANY($4)
+			AND started_at < NOW() - $5 * INTERVAL '1 millisecond'
+			AND attempts >= $6
+	`, models.JobStatusFailed, "job did not finish: its worker stopped or timed out", models.JobStatusRunning,
+		pq.Array(jobTypeNames(types)), staleAfter.Milliseconds(), maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("database error: failed to fail stale jobs")
+	}
+	return result.RowsAffected()
+}
+
+func jobTypeNames(types []models.JobType) []string {
+	names := make([]string, len(types))
+	for i, jobType := range types {
+		names[i] = string(jobType)
+	}
+	return names
+}
+
+// CompleteJob marks a running job as succeeded. attempt is the job's Attempts when it was claimed;
+// if the job has since been reclaimed as stale, the newer claim owns it and nothing changes.
+func (r *JobRepository) CompleteJob(jobID string, attempt int) error {
+	_, err := r.db.Exec(
+		`UPDATE jobs SET status = $1, error = NULL, finished_at = NOW() WHERE id = $2 AND status = $3 AND attempts = $4`,
+		models.JobStatusSucceeded, jobID, models.JobStatusRunning, attempt,
+	)
+	if err != nil {
+		return fmt.Errorf("database error: failed to complete job")
+	}
+	return nil
+}
+
+// FailJob marks a running job as failed with the given reason, unless it has since been reclaimed
+// (see CompleteJob)
+func (r *JobRepository) FailJob(jobID string, attempt int, reason string) error {
+	_, err := r.db.Exec(
+		`UPDATE jobs SET status = $1, error = $2, finished_at = NOW() WHERE id = $3 AND status = $4 AND attempts = $5`,
+		models.JobStatusFailed, reason, jobID, models.JobStatusRunning, attempt,
+	)
+	if err != nil {
+		return fmt.Errorf("database error: failed to record job failure")
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type KeywordRepository struct {
+	db *database.DB
+}
+
+func NewKeywordRepository(db *database.DB) *KeywordRepository {
+	return &KeywordRepository{db: db}
+}
+
+// weightedKeywords combines extracted phrases with the keywords users confirmed in file tags
+// (which count as a top-scoring phrase) and weights each file's phrases by inverse document
+// frequency across the user's library, bound to $1. Callers select from "weighted".
+const weightedKeywords = `
+	WITH mentions AS (
+		SELECT m.file_id, m.keyword, MAX(m.score) AS score, MAX(m.occurrences) AS occurrences, bool_or(m.confirmed) AS confirmed
+		FROM (
+			SELECT k.file_id, k.keyword, k.score, k.occurrences, false AS confirmed
+			FROM file_keywords k
+			WHERE k.user_id = $1
+			UNION ALL
+			SELECT f.id, lower(tag), 0, 0, true
+			FROM files f
+			CROSS JOIN LATERAL unnest(f.tags) AS tag
+			WHERE f.user_id = $1
+		) m
+		INNER JOIN files f ON f.id = m.file_id AND f.deleted_at IS NULL
+		GROUP BY m.file_id, m.keyword
+	),
+	library AS (
+		SELECT COUNT(DISTINCT file_id) AS files FROM mentions
+	),
+	frequency AS (
+		SELECT keyword, COUNT(*) AS files FROM mentions GROUP BY keyword
+	),
+	weighted AS (
+		SELECT m.file_id, m.keyword, m.occurrences, m.confirmed,
+			GREATEST(m.score, CASE WHEN m.confirmed THEN 1 ELSE 0 END) * (ln((l.files + 1)::float8 / (df.files + 1)) + 1) AS weight
+		FROM mentions m
+		CROSS JOIN library l
+		INNER JOIN frequency df ON df.keyword = m.keyword
+	)`
+
+// keywordScope appends the file/folder conditions for scope to a query over "files f"
+func keywordScope(args []interface{}, scope SearchScope) (string, []interface{}) {
+	condition := ""
+	if scope.FileID != "" {
+		args = append(args, scope.FileID)
+		condition += fmt.Sprintf(" AND f.id = $%d", len(args))
+	}
+	if scope.FolderID != "" {
+		args = append(args, scope.FolderID)
+		condition += " AND " + folderSubtreeCondition("f.folder_id", len(args))
+	}
+	return condition, args
+}
+
+// HasKeywords reports whether keywords have been extracted for a user's file
+func (r *KeywordRepository) HasKeywords(fileID, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM file_keywords WHERE file_id = $1 AND user_id = $2)`, fileID, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("database query error: failed to check keywords")
+	}
+	return exists, nil
+}
+
+// SaveFileKeywords replaces the phrases extracted for a file
+func (r *KeywordRepository) SaveFileKeywords(fileID, userID string, keywords []models.Keyword) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM file_keywords WHERE file_id = $1`, fileID); err != nil {
+		return fmt.Errorf("database error: failed to clear keywords")
+	}
+
+	for _, keyword := range keywords {
+		_, err := tx.Exec(`
+			INSERT INTO file_keywords (file_id, user_id, keyword, score, occurrences, extracted_at)
+			VALUES ($1, $2, $3, $4, $5, NOW())
+		`, fileID, userID, keyword.Keyword, keyword.Score, keyword.Occurrences)
+		if err != nil {
+			if strings.Contains(err.Error(), "connection") {
+				return fmt.Errorf("database connection error: unable to connect to database")
+			}
+			return fmt.Errorf("database error: failed to save keyword")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit keywords")
+	}
+
+	return nil
+}
+
+// TopKeywords ranks keywords across a file, a folder and its subfolders, or the whole library
+func (r *KeywordRepository) TopKeywords(userID string, scope SearchScope, limit int) ([]models.Keyword, error) {
+	condition, args := keywordScope([]interface{}{userID}, scope)
+	args = append(args, limit)
+	query := weightedKeywords + `
+		SELECT w.keyword, SUM(w.weight), SUM(w.occurrences), COUNT(*), bool_or(w.confirmed)
+		FROM weighted w
+		INNER JOIN files f ON f.id = w.file_id
+		WHERE f.user_id = $1` + condition + `
+		GROUP BY w.keyword
+		ORDER BY 2 DESC, w.keyword
+		` + fmt.Sprintf("LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve keywords")
+	}
+	defer rows.Close()
+
+	keywords := []models.Keyword{}
+	for rows.Next() {
+		var keyword models.Keyword
+		err := rows.Scan(
+			&keyword.Keyword,
+			&keyword.Score,
+			&keyword.Occurrences,
+			&keyword.FileCount,
+			&keyword.Confirmed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read keyword")
+		}
+		keywords = append(keywords, keyword)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating keywords: %w", err)
+	}
+
+	return keywords, nil
+}
+
+// FilesForKeyword lists the files mentioning a keyword, confirmed ones first, with the total count
+func (r *KeywordRepository) FilesForKeyword(userID, keyword string, scope SearchScope, limit, offset int) ([]models.KeywordFile, int, error) {
+	condition, args := keywordScope([]interface{}{userID, keyword}, scope)
+	args = append(args, limit, offset)
+	query := weightedKeywords + `
+		SELECT f.id, f.name, f.folder_id, w.occurrences, w.weight, w.confirmed, COUNT(*) OVER()
+		FROM weighted w
+		INNER JOIN files f ON f.id = w.file_id
+		WHERE f.user_id = $1 AND w.keyword = $2` + condition + `
+		ORDER BY w.confirmed DESC, w.weight DESC, f.name
+		` + fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, 0, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, 0, fmt.Errorf("database query error: failed to retrieve files for keyword")
+	}
+	defer rows.Close()
+
+	files := []models.KeywordFile{}
+	total := 0
+	for rows.Next() {
+		var file models.KeywordFile
+		err := rows.Scan(
+			&file.FileID,
+			&file.FileName,
+			&file.FolderID,
+			&file.Occurrences,
+			&file.Score,
+			&file.Confirmed,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("data parsing error: failed to read file")
+		}
+		files = append(files, file)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating files: %w", err)
+	}
+
+	return files, total, nil
+}
+
+// ConfirmKeyword adds a keyword to a file's tags unless it is already there. With expectedVersions
+// set the file must still be at one of those versions; on a failed precondition the current file
+// is returned with the error.
+func (r *KeywordRepository) ConfirmKeyword(fileID, userID, keyword string, expectedVersions []int64) (*models.File, error) {
+	return r.updateTags(`
+		UPDATE files f
+		SET tags = array_append(COALESCE(f.tags, '{}'), $3::text), updated_at = NOW()
+		WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM unnest(f.tags) AS tag WHERE lower(tag) = lower($3))
+		RETURNING `+fileColumns,
+		fileID, userID, keyword, expectedVersions,
+	)
+}
+
+// UnconfirmKeyword removes a keyword from a file's tags, with expectedVersions as in ConfirmKeyword
+func (r *KeywordRepository) UnconfirmKeyword(fileID, userID, keyword string, expectedVersions []int64) (*models.File, error) {
+	return r.updateTags(`
+		UPDATE files f
+		SET tags = ARRAY(SELECT tag FROM unnest(f.tags) AS tag WHERE lower(tag) <> lower($3)), updated_at = NOW()
+		WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM unnest(f.tags) AS tag WHERE lower(tag) = lower($3))
+		RETURNING `+fileColumns,
+		fileID, userID, keyword, expectedVersions,
+	)
+}
+
+// updateTags runs a conditional tag update; when nothing needed changing the file is returned as is
+func (r *KeywordRepository) updateTags(query, fileID, userID, keyword string, expectedVersions []int64) (*models.File, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	current, err := lockFileTx(tx, fileID, userID, expectedVersions)
+	if err != nil {
+		return current, err
+	}
+
+	file, err := scanFile(tx.QueryRow(query, fileID, userID, keyword))
+	if err == sql.ErrNoRows {
+		return current, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to update tags")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to update tags")
+	}
+	return file, nil
+}
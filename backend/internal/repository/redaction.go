@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type RedactionRepository struct {
+	db *database.DB
+}
+
+func NewRedactionRepository(db *database.DB) *RedactionRepository {
+	return &RedactionRepository{db: db}
+}
+
+// RedactedSegment is the new content of a segment being redacted, with its encrypted original
+type RedactedSegment struct {
+	SegmentID  string
+	Text       string
+	Words      []models.Word
+	Entities   []models.RedactedEntity
+	Ciphertext []byte
+}
+
+// RestoredSegment is the decrypted original content written back to a segment
+type RestoredSegment struct {
+	SegmentID string
+	Text      string
+	Words     []models.Word
+}
+
+// ListTerms returns a user's custom redaction terms
+func (r *RedactionRepository) ListTerms(userID string) ([]models.RedactionTerm, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, term, created_at
+		FROM redaction_terms
+		WHERE user_id = $1
+		ORDER BY lower(term)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve redaction terms")
+	}
+	defer rows.Close()
+
+	terms := []models.RedactionTerm{}
+	for rows.Next() {
+		var term models.RedactionTerm
+		if err := rows.Scan(&term.ID, &term.UserID, &term.Term, &term.CreatedAt); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read redaction term")
+		}
+		terms = append(terms, term)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating redaction terms: %w", err)
+	}
+
+	return terms, nil
+}
+
+// CreateTerm adds a custom redaction term for a user
+func (r *RedactionRepository) CreateTerm(userID, term string) (*models.RedactionTerm, error) {
+	var created models.RedactionTerm
+	err := r.db.QueryRow(`
+		INSERT INTO redaction_terms (user_id, term, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, user_id, term, created_at
+	`, userID, term).Scan(&created.ID, &created.UserID, &created.Term, &created.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("redaction term already exists")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to create redaction term")
+	}
+
+	return &created, nil
+}
+
+// DeleteTerm removes one of a user's custom redaction terms
+func (r *RedactionRepository) DeleteTerm(termID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM redaction_terms WHERE id = $1 AND user_id = $2`, termID, userID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to delete redaction term")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: failed to check deletion result")
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("redaction term not found")
+	}
+
+	return nil
+}
+
+// ApplyRedactions overwrites segments of a transcript (or its translations) with their redacted
+// text and stores the encrypted originals. Segment revisions and data derived from the raw text
+// (embeddings, the summary, chapters, keywords and Q&A history) are deleted in the same transaction
+// so no copy of the raw text survives.
+func (r *RedactionRepository) ApplyRedactions(transcriptID, userID string, segments []RedactedSegment, entityCounts map[string]int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	for _, segment := range segments {
+		words, err := encodeWords(segment.Words)
+		if err != nil {
+			return err
+		}
+		entities, err := json.Marshal(segment.Entities)
+		if err != nil {
+			return fmt.Errorf("invalid redacted entities: %w", err)
+		}
+
+		result, err := tx.Exec(`
+			UPDATE transcript_segments
+			SET text = $1, words = $2, updated_at = NOW()
+			WHERE id = $3 AND transcript_id IN (
+				SELECT id FROM transcripts WHERE id = $4 OR source_transcript_id = $4
+			)
+		`, segment.Text, words, segment.SegmentID, transcriptID)
+		if err != nil {
+			return fmt.Errorf("database error: failed to redact segment")
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			return fmt.Errorf("segment not found")
+		}
+
+		// A segment redacted twice keeps its first, truly original ciphertext
+		_, err = tx.Exec(`
+			INSERT INTO segment_redactions (transcript_id, segment_id, entities, original_ciphertext, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (segment_id) DO UPDATE
+			SET entities = EXCLUDED.entities
+		`, transcriptID, segment.SegmentID, entities, segment.Ciphertext)
+		if err != nil {
+			return fmt.Errorf("database error: failed to store redaction")
+		}
+	}
+
+	// Any earlier version of any segment may hold text that is now redacted
+	_, err = tx.Exec(`
+		DELETE FROM segment_revisions
+		WHERE segment_id IN (
+			SELECT s.id
+			FROM transcript_segments s
+			INNER JOIN transcripts t ON t.id = s.transcript_id
+			WHERE t.id = $1 OR t.source_transcript_id = $1
+		)
+	`, transcriptID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to clear segment revisions")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM transcript_chunks WHERE transcript_id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to clear embeddings")
+	}
+	if _, err := tx.Exec(`DELETE FROM transcript_summaries WHERE transcript_id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to clear summary")
+	}
+	if _, err := tx.Exec(`DELETE FROM transcript_chapters WHERE transcript_id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to clear chapters")
+	}
+	_, err = tx.Exec(`
+		DELETE FROM file_keywords
+		WHERE file_id = (SELECT file_id FROM transcripts WHERE id = $1)
+	`, transcriptID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to clear keywords")
+	}
+	_, err = tx.Exec(`
+		DELETE FROM ask_messages
+		WHERE user_id = $2 AND file_id = (SELECT file_id FROM transcripts WHERE id = $1)
+	`, transcriptID, userID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to clear conversation")
+	}
+
+	if _, err := tx.Exec(`UPDATE transcripts SET redacted_at = NOW(), updated_at = NOW() WHERE id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to mark transcript redacted")
+	}
+
+	if err := logAudit(tx, transcriptID, userID, models.RedactionActionApply, len(segments), entityCounts); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit redactions")
+	}
+
+	return nil
+}
+
+// GetRedactions returns the stored redactions for a transcript and its translations
+func (r *RedactionRepository) GetRedactions(transcriptID string) ([]models.SegmentRedaction, error) {
+	rows, err := r.db.Query(`
+		SELECT sr.id, sr.transcript_id, sr.segment_id, s.text, sr.entities, sr.original_ciphertext, sr.created_at
+		FROM segment_redactions sr
+		INNER JOIN transcript_segments s ON s.id = sr.segment_id
+		WHERE sr.transcript_id = $1
+		ORDER BY s.transcript_id, s.start_ms, s.id
+	`, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve redactions")
+	}
+	defer rows.Close()
+
+	redactions := []models.SegmentRedaction{}
+	for rows.Next() {
+		var redaction models.SegmentRedaction
+		var entities []byte
+		err := rows.Scan(
+			&redaction.ID,
+			&redaction.TranscriptID,
+			&redaction.SegmentID,
+			&redaction.RedactedText,
+			&entities,
+			&redaction.Ciphertext,
+			&redaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read redaction")
+		}
+		if err := json.Unmarshal(entities, &redaction.Entities); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read redacted entities")
+		}
+		redactions = append(redactions, redaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating redactions: %w", err)
+	}
+
+	return redactions, nil
+}
+
+// RestoreSegments writes decrypted originals back and drops their redaction records
+func (r *RedactionRepository) RestoreSegments(transcriptID, userID string, segments []RestoredSegment, entityCounts map[string]int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	for _, segment := range segments {
+		words, err := encodeWords(segment.Words)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE transcript_segments s
+			SET text = $1, words = $2, updated_at = NOW()
+			FROM segment_redactions sr
+			WHERE s.id = $3 AND sr.segment_id = s.id AND sr.transcript_id = $4
+		`, segment.Text, words, segment.SegmentID, transcriptID)
+		if err != nil {
+			return fmt.Errorf("database error: failed to restore segment")
+		}
+
+		_, err = tx.Exec(`DELETE FROM segment_redactions WHERE segment_id = $1 AND transcript_id = $2`, segment.SegmentID, transcriptID)
+		if err != nil {
+			return fmt.Errorf("database error: failed to remove redaction")
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE transcripts SET redacted_at = NULL, updated_at = NOW() WHERE id = $1`, transcriptID); err != nil {
+		return fmt.Errorf("database error: failed to update transcript")
+	}
+
+	if err := logAudit(tx, transcriptID, userID, models.RedactionActionRestore, len(segments), entityCounts); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit restore")
+	}
+
+	return nil
+}
+
+// LogAudit records an action taken on a transcript's redactions
+func (r *RedactionRepository) LogAudit(transcriptID, userID string, action models.RedactionAction, segmentCount int, entityCounts map[string]int) error {
+	return logAudit(r.db, transcriptID, userID, action, segmentCount, entityCounts)
+}
+
+// execer is satisfied by both the database and a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// logAudit writes an audit entry, inside the transaction of the change it records when there is one
+func logAudit(exec execer, transcriptID, userID string, action models.RedactionAction, segmentCount int, entityCounts map[string]int) error {
+	if entityCounts == nil {
+		entityCounts = map[string]int{}
+	}
+	counts, err := json.Marshal(entityCounts)
+	if err != nil {
+		return fmt.Errorf("invalid entity counts: %w", err)
+	}
+
+	_, err = exec.Exec(`
+		INSERT INTO redaction_audit_log (transcript_id, user_id, action, segment_count, entity_counts, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`, transcriptID, userID, action, segmentCount, counts)
+	if err != nil {
+		return fmt.Errorf("database error: failed to write audit log")
+	}
+	return nil
+}
+
+// GetAuditLog returns a transcript's redaction audit trail, newest first
+func (r *RedactionRepository) GetAuditLog(transcriptID string, limit, offset int) ([]models.RedactionAuditEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, transcript_id, user_id, action, segment_count, entity_counts, created_at
+		FROM redaction_audit_log
+		WHERE transcript_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, transcriptID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve audit log")
+	}
+	defer rows.Close()
+
+	entries := []models.RedactionAuditEntry{}
+	for rows.Next() {
+		var entry models.RedactionAuditEntry
+		var counts []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TranscriptID,
+			&entry.UserID,
+			&entry.Action,
+			&entry.SegmentCount,
+			&counts,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read audit entry")
+		}
+		if err := json.Unmarshal(counts, &entry.EntityCounts); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read audit entity counts")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// encodeWords serializes word timings for the words column, storing NULL when there are none
+func encodeWords(words []models.Word) (interface{}, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(words)
+	if err != nil {
+		return nil, fmt.Errorf("invalid segment words: %w", err)
+	}
+	return encoded, nil
+}
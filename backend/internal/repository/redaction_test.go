@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// TestApplyRedactionsClearsRevisions edits a segment, redacts it and checks that the revision
+// holding the raw text is gone.
+func TestApplyRedactionsClearsRevisions(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "test-redact")
+
+	var fileID, transcriptID, segmentID string
+	if err := db.QueryRow(`INSERT INTO files (name, type, user_id) VALUES ('call.mp3', 'audio', $1) RETURNING id`, user.ID).Scan(&fileID); err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	if err := db.QueryRow(`INSERT INTO transcripts (file_id, user_id, language) VALUES ($1, $2, 'en') RETURNING id`, fileID, user.ID).Scan(&transcriptID); err != nil {
+		t.Fatalf("create transcript: %v", err)
+	}
+	err := db.QueryRow(`
+		INSERT INTO transcript_segments (transcript_id, text, start_ms, end_ms)
+		VALUES ($1, 'my email is jane@example.com', 0, 1000)
+		RETURNING id
+	`, transcriptID).Scan(&segmentID)
+	if err != nil {
+		t.Fatalf("create segment: %v", err)
+	}
+
+	transcripts := NewTranscriptRepository(db)
+	edited := "my email is jane.doe@example.com"
+	if _, err := transcripts.UpdateSegment(transcriptID, segmentID, user.ID, &edited, nil, nil); err != nil {
+		t.Fatalf("edit segment: %v", err)
+	}
+	revisions, err := transcripts.GetSegmentRevisions(transcriptID, segmentID)
+	if err != nil {
+		t.Fatalf("list revisions: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("got %d revisions before redacting, want 1", len(revisions))
+	}
+
+	err = NewRedactionRepository(db).ApplyRedactions(transcriptID, user.ID, []RedactedSegment{{
+		SegmentID:  segmentID,
+		Text:       "my email is [EMAIL]",
+		Entities:   []models.RedactedEntity{{Type: "email", Placeholder: "[EMAIL]", Start: 12, End: 19}},
+		Ciphertext: []byte("ciphertext"),
+	}}, map[string]int{"email": 1})
+	if err != nil {
+		t.Fatalf("apply redactions: %v", err)
+	}
+
+	revisions, err = transcripts.GetSegmentRevisions(transcriptID, segmentID)
+	if err != nil {
+		t.Fatalf("list revisions: %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Errorf("got %d revisions after redacting, want none: %+v", len(revisions), revisions)
+	}
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type ReviewRepository struct {
+	db *database.DB
+}
+
+func NewReviewRepository(db *database.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// ReviewThresholds sets the confidence below which segments and words are queued for review
+type ReviewThresholds struct {
+	Segment float64
+	Word    float64
+}
+
+// ReviewQuery narrows the review queue by scope and item kind (empty kind includes both)
+type ReviewQuery struct {
+	Scope      SearchScope
+	Thresholds ReviewThresholds
+	Kind       string
+}
+
+const reviewDecisionColumns = `id, segment_id, word_index, user_id, action, original_text, corrected_text, created_at`
+
+func scanReviewDecision(row interface{ Scan(...interface{}) error }) (*models.ReviewDecision, error) {
+	var decision models.ReviewDecision
+	err := row.Scan(
+		&decision.ID,
+		&decision.SegmentID,
+		&decision.WordIndex,
+		&decision.UserID,
+		&decision.Action,
+		&decision.OriginalText,
+		&decision.CorrectedText,
+		&decision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// reviewQueueQuery builds the union of undecided low-confidence segments and words in a user's
+// original transcripts. Translations are never queued; their confidence is the source's.
+func reviewQueueQuery(userID string, q ReviewQuery) (string, []interface{}) {
+	args := []interface{}{userID, q.Thresholds.Segment, q.Thresholds.Word}
+
+	scope := ""
+	if q.Scope.FileID != "" {
+		args = append(args, q.Scope.FileID)
+		scope += fmt.Sprintf(" AND f.id = $%d", len(args))
+	}
+	if q.Scope.FolderID != "" {
+		args = append(args, q.Scope.FolderID)
+		scope += " AND " + folderSubtreeCondition("f.folder_id", len(args))
+	}
+
+	from := `
+		FROM transcript_segments s
+		INNER JOIN transcripts t ON t.id = s.transcript_id
+		INNER JOIN files f ON f.id = t.file_id`
+	where := `
+		WHERE t.user_id = $1
+			AND t.source_transcript_id IS NULL
+			AND f.deleted_at IS NULL` + scope
+
+	var branches []string
+	if q.Kind == "" || q.Kind == models.ReviewItemSegment {
+		branches = append(branches, `
+		SELECT 'segment' AS kind, s.id AS segment_id, NULL::int AS word_index, s.transcript_id, f.id AS file_id, f.name, f.folder_id,
+			s.text, s.text AS segment_text, s.start_ms, s.end_ms, s.confidence`+from+where+`
+			AND s.confidence < $2
+			AND NOT EXISTS (
+				SELECT 1 FROM review_decisions d WHERE d.segment_id = s.id AND d.word_index IS NULL
+			)`)
+	}
+	if q.Kind == "" || q.Kind == models.ReviewItemWord {
+		branches = append(branches, `
+		SELECT 'word' AS kind, s.id AS segment_id, (w.ordinality - 1)::int AS word_index, s.transcript_id, f.id AS file_id, f.name, f.folder_id,
+			w.value->>'text' AS text, s.text AS segment_text, (w.value->>'start_ms')::bigint AS start_ms, (w.value->>'end_ms')::bigint AS end_ms,
+			(w.value->>'confidence')::float8 AS confidence`+from+`
+		CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(s.words) = 'array' THEN s.words ELSE '[]'::jsonb END) WITH ORDINALITY AS w(value, ordinality)`+where+`
+			AND (w.value->>'confidence')::float8 < $3
+			AND NOT EXISTS (
+				SELECT 1 FROM review_decisions d WHERE d.segment_id = s.id AND d.word_index = w.ordinality - 1
+			)`)
+	}
+
+	return strings.Join(branches, "\n\t\tUNION ALL"), args
+}
+
+// ListQueue returns the lowest-confidence items first, with the total number queued
+func (r *ReviewRepository) ListQueue(userID string, q ReviewQuery, limit, offset int) ([]models.ReviewItem, int, error) {
+	queue, args := reviewQueueQuery(userID, q)
+	args = append(args, limit, offset)
+	query := `
+		SELECT kind, segment_id, word_index, transcript_id, file_id, name, folder_id,
+			text, segment_text, start_ms, end_ms, confidence, COUNT(*) OVER()
+		FROM (` + queue + `
+		) queue
+		ORDER BY confidence, file_id, start_ms, word_index NULLS FIRST
+		` + fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, 0, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, 0, fmt.Errorf("database query error: failed to retrieve review queue")
+	}
+	defer rows.Close()
+
+	items := []models.ReviewItem{}
+	total := 0
+	for rows.Next() {
+		var item models.ReviewItem
+		err := rows.Scan(
+			&item.Kind,
+			&item.SegmentID,
+			&item.WordIndex,
+			&item.TranscriptID,
+			&item.FileID,
+			&item.FileName,
+			&item.FolderID,
+			&item.Text,
+			&item.SegmentText,
+			&item.StartMs,
+			&item.EndMs,
+			&item.Confidence,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("data parsing error: failed to read review item")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating review queue: %w", err)
+	}
+
+	return items, total, nil
+}
+
+// CountQueue returns how many items are still queued for a file
+func (r *ReviewRepository) CountQueue(fileID, userID string, thresholds ReviewThresholds) (int, error) {
+	return countQueue(r.db, fileID, userID, thresholds)
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func countQueue(q queryRower, fileID, userID string, thresholds ReviewThresholds) (int, error) {
+	queue, args := reviewQueueQuery(userID, ReviewQuery{
+		Scope:      SearchScope{FileID: fileID},
+		Thresholds: thresholds,
+	})
+
+	var remaining int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM (`+queue+`) queue`, args...).Scan(&remaining); err != nil {
+		return 0, fmt.Errorf("database query error: failed to count review queue")
+	}
+	return remaining, nil
+}
+
+// Accept marks a segment (nil word index) or one of its words as correct as transcribed
+func (r *ReviewRepository) Accept(transcriptID, segmentID, userID string, wordIndex *int) (*models.ReviewDecision, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	segment, err := lockSegment(tx, transcriptID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	original := segment.Text
+	if wordIndex != nil {
+		if *wordIndex < 0 || *wordIndex >= len(segment.Words) {
+			return nil, fmt.Errorf("word not found")
+		}
+		original = segment.Words[*wordIndex].Text
+	}
+
+	decision, err := saveReviewDecision(tx, segmentID, userID, wordIndex, models.ReviewActionAccept, original, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit review decision")
+	}
+
+	return decision, nil
+}
+
+// Correct replaces a segment's text (nil word index) or a single word, recording the edit as a
+// segment revision alongside the decision. A corrected word keeps its timing and is given full confidence.
+func (r *ReviewRepository) Correct(transcriptID, segmentID, userID string, wordIndex *int, text string) (*models.ReviewDecision, *models.Segment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	current, err := lockSegment(tx, transcriptID, segmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var original string
+	var saved *models.Segment
+	if wordIndex == nil {
+		original = current.Text
+		saved, err = updateSegment(tx, transcriptID, segmentID, userID, &text, nil, nil)
+	} else {
+		if *wordIndex < 0 || *wordIndex >= len(current.Words) {
+			return nil, nil, fmt.Errorf("word not found")
+		}
+		original = current.Words[*wordIndex].Text
+
+		updated := *current
+		updated.Words = make([]models.Word, len(current.Words))
+		copy(updated.Words, current.Words)
+		updated.Words[*wordIndex].Text = text
+		updated.Words[*wordIndex].Confidence = 1
+		updated.Text = replaceWord(current.Text, current.Words, *wordIndex, text)
+
+		saved, err = saveSegmentRevision(tx, current, &updated, userID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decision, err := saveReviewDecision(tx, segmentID, userID, wordIndex, models.ReviewActionCorrect, original, &text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("database error: failed to commit review decision")
+	}
+
+	return decision, saved, nil
+}
+
+func saveReviewDecision(tx *sql.Tx, segmentID, userID string, wordIndex *int, action, original string, corrected *string) (*models.ReviewDecision, error) {
+	decision, err := scanReviewDecision(tx.QueryRow(`
+		INSERT INTO review_decisions (segment_id, word_index, user_id, action, original_text, corrected_text, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (segment_id, (COALESCE(word_index, -1))) DO UPDATE
+		SET user_id = EXCLUDED.user_id, action = EXCLUDED.action, original_text = EXCLUDED.original_text,
+			corrected_text = EXCLUDED.corrected_text, created_at = EXCLUDED.created_at
+		RETURNING `+reviewDecisionColumns,
+		segmentID, wordIndex, userID, action, original, corrected,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to save review decision")
+	}
+	return decision, nil
+}
+
+// replaceWord swaps the word at index in the segment text, locating it by walking the
+// words in order. If the text has drifted from the words it is rebuilt from them instead.
+func replaceWord(text string, words []models.Word, index int, replacement string) string {
+	pos := 0
+	for i, word := range words {
+		offset := strings.Index(text[pos:], word.Text)
+		if offset < 0 || word.Text == "" {
+			break
+		}
+		start := pos + offset
+		if i == index {
+			return text[:start] + replacement + text[start+len(word.Text):]
+		}
+		pos = start + len(word.Text)
+	}
+
+	parts := make([]string, len(words))
+	for i, word := range words {
+		parts[i] = word.Text
+		if i == index {
+			parts[i] = replacement
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// MarkReviewed signs a file off once nothing is left in its queue. It returns the number of
+// queued items alongside a "review queue not empty" error otherwise. With expectedVersions set the
+// file must still be at one of those versions; on a failed precondition the current file is
+// returned with the error.
+func (r *ReviewRepository) MarkReviewed(fileID, userID string, thresholds ReviewThresholds, expectedVersions []int64) (*models.File, int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if current, err := lockFileTx(tx, fileID, userID, expectedVersions); err != nil {
+		return current, 0, err
+	}
+
+	remaining, err := countQueue(tx, fileID, userID, thresholds)
+	if err != nil {
+		return nil, 0, err
+	}
+	if remaining > 0 {
+		return nil, remaining, fmt.Errorf("review queue not empty")
+	}
+
+	file, err := scanFile(tx.QueryRow(`
+		UPDATE files f
+		SET reviewed_at = NOW(), reviewed_by = $2
+		WHERE f.id = $1
+		RETURNING `+fileColumns,
+		fileID, userID,
+	))
+	if err != nil {
+		return nil, 0, fmt.Errorf("database error: failed to mark file reviewed")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("database error: failed to commit review sign-off")
+	}
+
+	return file, 0, nil
+}
+
+// ReopenReview clears a file's sign-off, with expectedVersions as in MarkReviewed
+func (r *ReviewRepository) ReopenReview(fileID, userID string, expectedVersions []int64) (*models.File, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if current, err := lockFileTx(tx, fileID, userID, expectedVersions); err != nil {
+		return current, err
+	}
+
+	file, err := scanFile(tx.QueryRow(`
+		UPDATE files f
+		SET reviewed_at = NULL, reviewed_by = NULL
+		WHERE f.id = $1
+		RETURNING `+fileColumns,
+		fileID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to reopen review")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to reopen review")
+	}
+	return file, nil
+}
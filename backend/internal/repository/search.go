@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type SearchRepository struct {
+	db *database.DB
+}
+
+func NewSearchRepository(db *database.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SearchScope narrows a transcript search to a folder or a single file
+type SearchScope struct {
+	FolderID string
+	FileID   string
+}
+
+// SearchSegments runs a ranked full-text query over a user's transcript segments.
+// Each segment is matched with its own language configuration; snippets mark hits with <mark>.
+// Only original transcripts are searched, not their translations.
+func (r *SearchRepository) SearchSegments(userID, query string, scope SearchScope, limit, offset int) ([]models.SearchHit, int, error) {
+	sqlQuery := `
+		SELECT s.id, s.transcript_id, t.file_id, f.name, f.folder_id,
+			COALESCE(sp.name, sp.label, ''),
+			s.text,
+			ts_headline(s.search_config, s.text, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2'),
+			s.start_ms, s.end_ms,
+			ts_rank_cd(s.search_vector, q.query),
+			COUNT(*) OVER()
+		FROM ` + segmentQueries(1, 2) + ` q
+		INNER JOIN transcript_segments s ON s.search_config = q.config AND s.search_vector @@ q.query
+		INNER JOIN transcripts t ON t.id = s.transcript_id
+		INNER JOIN files f ON f.id = t.file_id
+		LEFT JOIN transcript_speakers sp ON sp.id = s.speaker_id
+		WHERE t.user_id = $1
+			AND t.source_transcript_id IS NULL
+			AND f.deleted_at IS NULL
+	`
+	args := []interface{}{userID, query}
+
+	if scope.FileID != "" {
+		args = append(args, scope.FileID)
+		sqlQuery += fmt.Sprintf(" AND f.id = $%d", len(args))
+	}
+
+	if scope.FolderID != "" {
+		// Include files anywhere below the folder, not just direct children
+		args = append(args, scope.FolderID)
+		sqlQuery += " AND " + folderSubtreeCondition("f.folder_id", len(args))
+	}
+
+	args = append(args, limit, offset)
+	sqlQuery += fmt.Sprintf(" ORDER BY 11 DESC, t.file_id, s.start_ms LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, 0, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, 0, fmt.Errorf("database query error: failed to search transcripts")
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	total := 0
+	for rows.Next() {
+		var hit models.SearchHit
+		err := rows.Scan(
+			&hit.SegmentID,
+			&hit.TranscriptID,
+			&hit.FileID,
+			&hit.FileName,
+			&hit.FolderID,
+			&hit.Speaker,
+			&hit.Text,
+			&hit.Snippet,
+			&hit.StartMs,
+			&hit.EndMs,
+			&hit.Rank,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("data parsing error: failed to read search result")
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return hits, total, nil
+}
+
+// segmentQueries is a derived table of (config, query): the search text bound to $queryArg parsed
+// once for each text search configuration the user's transcripts use. Joining segments to it on
+// search_config and search_vector @@ query lets the GIN index on search_vector serve the match,
+// which parsing the query per segment row would not. The user ID must be bound to $userArg.
+func segmentQueries(userArg, queryArg int) string {
+	return fmt.Sprintf(`(
+		SELECT config, websearch_to_tsquery(config, $%d) AS query
+		FROM (SELECT DISTINCT transcript_ts_config(language) AS config FROM transcripts WHERE user_id = $%d) configs
+	)`, queryArg, userArg)
+}
+
+// folderSubtreeCondition matches rows whose folder column lies in the subtree rooted at
+// the folder bound to $folderArg. The user ID must be bound to $1.
+func folderSubtreeCondition(column string, folderArg int) string {
+	return fmt.Sprintf(`%s IN (
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = $%d AND user_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM folders c INNER JOIN subtree st ON c.parent_id = st.id
+			WHERE c.user_id = $1 AND c.deleted_at IS NULL
+		)
+		SELECT id FROM subtree
+	)`, column, folderArg)
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type SmartFolderRepository struct {
+	db *database.DB
+}
+
+func NewSmartFolderRepository(db *database.DB) *SmartFolderRepository {
+	return &SmartFolderRepository{db: db}
+}
+
+// SmartFolderUpdate changes a smart folder. Nil fields are left alone; an empty ParentID moves it to
+// the root and empty Color or Icon clear them.
+type SmartFolderUpdate struct {
+	Name     *string
+	ParentID *string
+	Color    *string
+	Icon     *string
+	Filters  *models.SmartFolderFilters
+}
+
+const smartFolderColumns = `id, user_id, name, parent_id, color, icon, filters, created_at, updated_at`
+
+func scanSmartFolder(row interface{ Scan(...interface{}) error }) (*models.SmartFolder, error) {
+	var folder models.SmartFolder
+	var filters []byte
+	err := row.Scan(
+		&folder.ID,
+		&folder.UserID,
+		&folder.Name,
+		&folder.ParentID,
+		&folder.Color,
+		&folder.Icon,
+		&filters,
+		&folder.CreatedAt,
+		&folder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &folder.Filters); err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// liveSmartFolder hides smart folders whose parent folder is in the trash; they come back when the
+// folder is restored and are deleted with it when the trash is purged. The user must be bound to $1.
+const liveSmartFolder = `(parent_id IS NULL OR parent_id IN (SELECT id FROM folders WHERE user_id = $1 AND deleted_at IS NULL))`
+
+// ListSmartFolders returns all of a user's smart folders by name
+func (r *SmartFolderRepository) ListSmartFolders(userID string) ([]models.SmartFolder, error) {
+	return listSmartFolders(r.db, userID)
+}
+
+func listSmartFolders(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, userID string) ([]models.SmartFolder, error) {
+	rows, err := q.Query(`
+		SELECT `+smartFolderColumns+`
+		FROM smart_folders
+		WHERE user_id = $1 AND `+liveSmartFolder+`
+		ORDER BY lower(name), id
+	`, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve smart folders")
+	}
+	defer rows.Close()
+
+	folders := []models.SmartFolder{}
+	for rows.Next() {
+		folder, err := scanSmartFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read smart folder")
+		}
+		folders = append(folders, *folder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating smart folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+// GetSmartFolder returns a smart folder, or nil when it does not exist
+func (r *SmartFolderRepository) GetSmartFolder(smartFolderID, userID string) (*models.SmartFolder, error) {
+	folder, err := scanSmartFolder(r.db.QueryRow(`
+		SELECT `+smartFolderColumns+`
+		FROM smart_folders
+		WHERE user_id = $1 AND id = $2 AND `+liveSmartFolder+`
+	`, userID, smartFolderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve smart folder")
+	}
+	return folder, nil
+}
+
+// CreateSmartFolder saves a smart folder under parentID (the root when nil)
+func (r *SmartFolderRepository) CreateSmartFolder(userID, name string, parentID, color, icon *string, filters models.SmartFolderFilters) (*models.SmartFolder, error) {
+	if err := r.checkFolders(userID, parentID, filters); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(filters)
+	if err != nil {
+		return nil, fmt.Errorf("data parsing error: failed to encode smart folder filters")
+	}
+
+	folder, err := scanSmartFolder(r.db.QueryRow(`
+		INSERT INTO smart_folders (user_id, name, parent_id, color, icon, filters)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+smartFolderColumns+`
+	`, userID, name, parentID, color, icon, raw))
+	if err != nil {
+		return nil, smartFolderError(err, "create")
+	}
+	return folder, nil
+}
+
+// UpdateSmartFolder renames, moves or changes the filters of a smart folder
+func (r *SmartFolderRepository) UpdateSmartFolder(smartFolderID, userID string, update SmartFolderUpdate) (*models.SmartFolder, error) {
+	folder, err := r.GetSmartFolder(smartFolderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("smart folder not found")
+	}
+
+	if update.Name != nil {
+		folder.Name = *update.Name
+	}
+	if update.ParentID != nil {
+		folder.ParentID = emptyToNil(*update.ParentID)
+	}
+	if update.Color != nil {
+		folder.Color = emptyToNil(*update.Color)
+	}
+	if update.Icon != nil {
+		folder.Icon = emptyToNil(*update.Icon)
+	}
+	if update.Filters != nil {
+		folder.Filters = *update.Filters
+	}
+
+	if err := r.checkFolders(userID, folder.ParentID, folder.Filters); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(folder.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("data parsing error: failed to encode smart folder filters")
+	}
+
+	updated, err := scanSmartFolder(r.db.QueryRow(`
+		UPDATE smart_folders
+		SET name = $1, parent_id = $2, color = $3, icon = $4, filters = $5, updated_at = NOW()
+		WHERE id = $6 AND user_id = $7
+		RETURNING `+smartFolderColumns+`
+	`, folder.Name, folder.ParentID, folder.Color, folder.Icon, raw, smartFolderID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("smart folder not found")
+		}
+		return nil, smartFolderError(err, "update")
+	}
+	return updated, nil
+}
+
+// DeleteSmartFolder deletes a smart folder. The files it lists are not affected.
+func (r *SmartFolderRepository) DeleteSmartFolder(smartFolderID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM smart_folders WHERE id = $1 AND user_id = $2`, smartFolderID, userID)
+	if err != nil {
+		return smartFolderError(err, "delete")
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("smart folder not found")
+	}
+	return nil
+}
+
+// checkFolders makes sure the parent folder and the folder the filters search below are the user's
+// live folders
+func (r *SmartFolderRepository) checkFolders(userID string, parentID *string, filters models.SmartFolderFilters) error {
+	for _, folderID := range []*string{parentID, filters.FolderID} {
+		if folderID == nil {
+			continue
+		}
+		folder, err := getFolderTx(r.db, *folderID, userID)
+		if err != nil {
+			return err
+		}
+		if folder == nil {
+			return fmt.Errorf("folder not found")
+		}
+	}
+	return nil
+}
+
+func smartFolderError(err error, action string) error {
+	if strings.Contains(err.Error(), "foreign key") {
+		return fmt.Errorf("folder not found")
+	}
+	if strings.Contains(err.Error(), "connection") {
+		return fmt.Errorf("database connection error: unable to connect to database")
+	}
+	return fmt.Errorf("database error: failed to %s smart folder", action)
+}
+
+// smartFolderConditions turns smart folder filters into conditions on files, binding values with
+// bind, which returns the placeholder number of the bound value. The user must be bound to $1.
+// Relative periods are resolved against now.
+func smartFolderConditions(filters models.SmartFolderFilters, now time.Time, bind func(interface{}) int) string {
+	next := func(value interface{}) string {
+		return fmt.Sprintf("$%d", bind(value))
+	}
+
+	condition := ""
+	if filters.FolderID != nil {
+		condition += " AND " + folderSubtreeCondition("folder_id", bind(*filters.FolderID))
+	}
+	if len(filters.Languages) > 0 {
+		// "es" also matches regional variants such as "es-MX"
+		arg := next(pq.Array(lowerAll(filters.Languages)))
+		condition += " AND (lower(language) = ANY(" + arg + ") OR lower(split_part(language, '-', 1)) = ANY(" + arg + "))"
+	}
+	if len(filters.Tags) > 0 {
+		condition += " AND ARRAY(SELECT lower(tag) FROM unnest(tags) AS tag) @> " + next(pq.Array(lowerAll(filters.Tags))) + "::text[]"
+	}
+	if len(filters.Services) > 0 {
+		condition += " AND lower(service) = ANY(" + next(pq.Array(lowerAll(filters.Services))) + ")"
+	}
+	if filters.MinDurationMs != nil {
+		condition += " AND duration_ms >= " + next(*filters.MinDurationMs)
+	}
+	if filters.MaxDurationMs != nil {
+		condition += " AND duration_ms <= " + next(*filters.MaxDurationMs)
+	}
+
+	after, before := filters.CreatedAfter, filters.CreatedBefore
+	if start, end, ok := SmartFolderPeriodRange(filters.CreatedWithin, now); ok {
+		if after == nil || start.After(*after) {
+			after = &start
+		}
+		if before == nil || end.Before(*before) {
+			before = &end
+		}
+	}
+	if after != nil {
+		condition += " AND created_at >= " + next(*after)
+	}
+	if before != nil {
+		condition += " AND created_at < " + next(*before)
+	}
+
+	if filters.Text != "" {
+		condition += ` AND id IN (
+			SELECT t.file_id
+			FROM ` + segmentQueries(1, bind(filters.Text)) + ` q
+			INNER JOIN transcript_segments s ON s.search_config = q.config AND s.search_vector @@ q.query
+			INNER JOIN transcripts t ON t.id = s.transcript_id
+			WHERE t.user_id = $1
+		)`
+	}
+
+	return condition
+}
+
+// SmartFolderPeriodRange returns the [start, end) range of a relative period containing now, in
+// now's location. Weeks start on Monday.
+func SmartFolderPeriodRange(period models.SmartFolderPeriod, now time.Time) (time.Time, time.Time, bool) {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case models.SmartFolderPeriodToday:
+		return today, today.AddDate(0, 0, 1), true
+	case models.SmartFolderPeriodThisWeek:
+		start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), true
+	case models.SmartFolderPeriodThisMonth:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0), true
+	case models.SmartFolderPeriodThisQuarter:
+		start := time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 3, 0), true
+	case models.SmartFolderPeriodThisYear:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(1, 0, 0), true
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
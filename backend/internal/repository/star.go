@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// maxQuickAccessItems caps each list of the quick access listing
+const maxQuickAccessItems = 200
+
+type StarRepository struct {
+	db *database.DB
+}
+
+func NewStarRepository(db *database.DB) *StarRepository {
+	return &StarRepository{db: db}
+}
+
+// StarFolder stars a live folder for the user. Starring it again is a no-op.
+func (r *StarRepository) StarFolder(folderID, userID string) error {
+	return r.star(`
+		WITH target AS (
+			SELECT id FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		),
+		starred AS (
+			INSERT INTO folder_stars (user_id, folder_id)
+			SELECT $2, id FROM target
+			ON CONFLICT DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM target)
+	`, folderID, userID, "folder")
+}
+
+// StarFile stars a live file for the user. Starring it again is a no-op.
+func (r *StarRepository) StarFile(fileID, userID string) error {
+	return r.star(`
+		WITH target AS (
+			SELECT id FROM files WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		),
+		starred AS (
+			INSERT INTO file_stars (user_id, file_id)
+			SELECT $2, id FROM target
+			ON CONFLICT DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM target)
+	`, fileID, userID, "file")
+}
+
+func (r *StarRepository) star(query, itemID, userID, kind string) error {
+	var found bool
+	if err := r.db.QueryRow(query, itemID, userID).Scan(&found); err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return fmt.Errorf("database error: failed to star %s", kind)
+	}
+	if !found {
+		return fmt.Errorf("%s not found", kind)
+	}
+	return nil
+}
+
+// UnstarFolder removes the user's star from a folder. Unstarring an unstarred folder is a no-op.
+func (r *StarRepository) UnstarFolder(folderID, userID string) error {
+	if _, err := r.db.Exec(`DELETE FROM folder_stars WHERE user_id = $1 AND folder_id = $2`, userID, folderID); err != nil {
+		return fmt.Errorf("database error: failed to unstar folder")
+	}
+	return nil
+}
+
+// UnstarFile removes the user's star from a file. Unstarring an unstarred file is a no-op.
+func (r *StarRepository) UnstarFile(fileID, userID string) error {
+	if _, err := r.db.Exec(`DELETE FROM file_stars WHERE user_id = $1 AND file_id = $2`, userID, fileID); err != nil {
+		return fmt.Errorf("database error: failed to unstar file")
+	}
+	return nil
+}
+
+// GetQuickAccess returns the user's pinned folders by name and their starred folders and files,
+// most recently starred first. Items in the trash are left out.
+func (r *StarRepository) GetQuickAccess(userID string) (*models.QuickAccess, error) {
+	quickAccess := &models.QuickAccess{}
+
+	var err error
+	quickAccess.PinnedFolders, err = r.queryFolders(`
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE user_id = $1 AND pinned AND deleted_at IS NULL
+		ORDER BY lower(name), id
+		LIMIT $2
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quickAccess.StarredFolders, err = r.queryFolders(`
+		SELECT `+folderColumns+`
+		FROM folders
+		WHERE user_id = $1 AND deleted_at IS NULL
+			AND id IN (SELECT folder_id FROM folder_stars WHERE user_id = $1)
+		ORDER BY (SELECT s.created_at FROM folder_stars s WHERE s.user_id = $1 AND s.folder_id = folders.id) DESC, id
+		LIMIT $2
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+fileColumns+`
+		FROM files f
+		INNER JOIN file_stars s ON s.file_id = f.id AND s.user_id = $1
+		WHERE f.user_id = $1 AND f.deleted_at IS NULL
+		ORDER BY s.created_at DESC, f.id
+		LIMIT $2
+	`, userID, maxQuickAccessItems)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve starred files")
+	}
+	defer rows.Close()
+
+	quickAccess.StarredFiles = []models.File{}
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read file information")
+		}
+		quickAccess.StarredFiles = append(quickAccess.StarredFiles, *file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating starred files: %w", err)
+	}
+
+	return quickAccess, nil
+}
+
+func (r *StarRepository) queryFolders(query, userID string) ([]models.Folder, error) {
+	rows, err := r.db.Query(query, userID, maxQuickAccessItems)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve folders")
+	}
+	defer rows.Close()
+
+	folders := []models.Folder{}
+	for rows.Next() {
+		folder, err := scanFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read folder information")
+		}
+		folders = append(folders, *folder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating folders: %w", err)
+	}
+
+	return folders, nil
+}
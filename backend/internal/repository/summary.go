@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type SummaryRepository struct {
+	db *database.DB
+}
+
+func NewSummaryRepository(db *database.DB) *SummaryRepository {
+	return &SummaryRepository{db: db}
+}
+
+// SaveSummary stores a transcript's summary, replacing any previous one
+func (r *SummaryRepository) SaveSummary(summary *models.TranscriptSummary) (*models.TranscriptSummary, error) {
+	keyPoints, err := json.Marshal(summary.KeyPoints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key points: %w", err)
+	}
+	actionItems, err := json.Marshal(summary.ActionItems)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action items: %w", err)
+	}
+
+	query := `
+		INSERT INTO transcript_summaries (transcript_id, summary, key_points, action_items, generator, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (transcript_id) DO UPDATE
+		SET summary = EXCLUDED.summary,
+			key_points = EXCLUDED.key_points,
+			action_items = EXCLUDED.action_items,
+			generator = EXCLUDED.generator,
+			updated_at = NOW()
+		RETURNING id, transcript_id, summary, key_points, action_items, generator, created_at, updated_at
+	`
+
+	saved, err := scanSummary(r.db.QueryRow(query, summary.TranscriptID, summary.Summary, keyPoints, actionItems, summary.Generator))
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to save summary")
+	}
+
+	return saved, nil
+}
+
+// GetSummary retrieves the stored summary for a transcript
+func (r *SummaryRepository) GetSummary(transcriptID string) (*models.TranscriptSummary, error) {
+	query := `
+		SELECT id, transcript_id, summary, key_points, action_items, generator, created_at, updated_at
+		FROM transcript_summaries
+		WHERE transcript_id = $1
+	`
+
+	summary, err := scanSummary(r.db.QueryRow(query, transcriptID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve summary")
+	}
+
+	return summary, nil
+}
+
+func scanSummary(row interface{ Scan(...interface{}) error }) (*models.TranscriptSummary, error) {
+	var summary models.TranscriptSummary
+	var keyPoints, actionItems []byte
+	err := row.Scan(
+		&summary.ID,
+		&summary.TranscriptID,
+		&summary.Summary,
+		&keyPoints,
+		&actionItems,
+		&summary.Generator,
+		&summary.CreatedAt,
+		&summary.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(keyPoints, &summary.KeyPoints); err != nil {
+		return nil, fmt.Errorf("data parsing error: failed to read key points")
+	}
+	if err := json.Unmarshal(actionItems, &summary.ActionItems); err != nil {
+		return nil, fmt.Errorf("data parsing error: failed to read action items")
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,754 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type TranscriptRepository struct {
+	db *database.DB
+}
+
+func NewTranscriptRepository(db *database.DB) *TranscriptRepository {
+	return &TranscriptRepository{db: db}
+}
+
+const transcriptColumns = `t.id, t.file_id, t.user_id, t.language, t.source_transcript_id, t.redacted_at, t.created_at, t.updated_at`
+
+// GetTranscriptByFileID retrieves a file's original transcript with its speaker roster and segments
+func (r *TranscriptRepository) GetTranscriptByFileID(fileID, userID string) (*models.Transcript, error) {
+	query := `
+		SELECT ` + transcriptColumns + `
+		FROM transcripts t
+		INNER JOIN files f ON f.id = t.file_id
+		WHERE t.file_id = $1 AND t.user_id = $2 AND t.source_transcript_id IS NULL AND f.deleted_at IS NULL
+		ORDER BY t.created_at
+		LIMIT 1
+	`
+
+	return r.getTranscript(query, fileID, userID)
+}
+
+// GetTranscriptVariant retrieves a file's translated transcript in the given language
+func (r *TranscriptRepository) GetTranscriptVariant(fileID, userID, language string) (*models.Transcript, error) {
+	query := `
+		SELECT ` + transcriptColumns + `
+		FROM transcripts t
+		INNER JOIN files f ON f.id = t.file_id
+		WHERE t.file_id = $1 AND t.user_id = $2 AND t.source_transcript_id IS NOT NULL
+			AND lower(t.language) = lower($3) AND f.deleted_at IS NULL
+		ORDER BY t.created_at DESC
+		LIMIT 1
+	`
+
+	return r.getTranscript(query, fileID, userID, language)
+}
+
+// ListTranscriptVariants lists a file's translated transcripts without their segments
+func (r *TranscriptRepository) ListTranscriptVariants(fileID, userID string) ([]models.Transcript, error) {
+	query := `
+		SELECT ` + transcriptColumns + `
+		FROM transcripts t
+		INNER JOIN files f ON f.id = t.file_id
+		WHERE t.file_id = $1 AND t.user_id = $2 AND t.source_transcript_id IS NOT NULL AND f.deleted_at IS NULL
+		ORDER BY t.language
+	`
+
+	rows, err := r.db.Query(query, fileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve translations")
+	}
+	defer rows.Close()
+
+	variants := []models.Transcript{}
+	for rows.Next() {
+		transcript, err := scanTranscript(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read transcript information")
+		}
+		variants = append(variants, *transcript)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating translations: %w", err)
+	}
+
+	return variants, nil
+}
+
+// getTranscript runs a single-transcript query and loads its roster and segments
+func (r *TranscriptRepository) getTranscript(query string, args ...interface{}) (*models.Transcript, error) {
+	transcript, err := scanTranscript(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve transcript")
+	}
+
+	// Translations share the original's roster so renames apply to every language
+	rosterID := transcript.ID
+	if transcript.SourceTranscriptID != nil {
+		rosterID = *transcript.SourceTranscriptID
+	}
+
+	speakers, err := r.GetSpeakers(rosterID)
+	if err != nil {
+		return nil, err
+	}
+	transcript.Speakers = speakers
+
+	segments, err := r.GetSegments(transcript.ID)
+	if err != nil {
+		return nil, err
+	}
+	transcript.Segments = segments
+
+	// Chapter titles are in the original's language, so translations don't carry them
+	if transcript.SourceTranscriptID == nil {
+		chapters, err := r.GetChapters(transcript.ID)
+		if err != nil {
+			return nil, err
+		}
+		transcript.Chapters = chapters
+	}
+
+	transcript.ResolveSpeakerNames()
+
+	return transcript, nil
+}
+
+// ListFolderTranscripts loads the original transcript of every file in a folder and its subfolders,
+// along with the files' names keyed by file ID
+func (r *TranscriptRepository) ListFolderTranscripts(folderID, userID string) ([]models.Transcript, map[string]string, error) {
+	query := `
+		SELECT ` + transcriptColumns + `, f.name
+		FROM transcripts t
+		INNER JOIN files f ON f.id = t.file_id
+		WHERE t.user_id = $1 AND t.source_transcript_id IS NULL AND f.deleted_at IS NULL
+			AND ` + folderSubtreeCondition("f.folder_id", 2) + `
+		ORDER BY f.name, t.created_at
+	`
+
+	rows, err := r.db.Query(query, userID, folderID)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, nil, fmt.Errorf("database query error: failed to retrieve transcripts")
+	}
+	defer rows.Close()
+
+	transcripts := []models.Transcript{}
+	fileNames := make(map[string]string)
+	for rows.Next() {
+		var transcript models.Transcript
+		var fileName string
+		err := rows.Scan(
+			&transcript.ID,
+			&transcript.FileID,
+			&transcript.UserID,
+			&transcript.Language,
+			&transcript.SourceTranscriptID,
+			&transcript.RedactedAt,
+			&transcript.CreatedAt,
+			&transcript.UpdatedAt,
+			&fileName,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("data parsing error: failed to read transcript information")
+		}
+		// A file keeps only its first original transcript, as in GetTranscriptByFileID
+		if _, seen := fileNames[transcript.FileID]; seen {
+			continue
+		}
+		fileNames[transcript.FileID] = fileName
+		transcripts = append(transcripts, transcript)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating transcripts: %w", err)
+	}
+	rows.Close()
+
+	for i := range transcripts {
+		if transcripts[i].Speakers, err = r.GetSpeakers(transcripts[i].ID); err != nil {
+			return nil, nil, err
+		}
+		if transcripts[i].Segments, err = r.GetSegments(transcripts[i].ID); err != nil {
+			return nil, nil, err
+		}
+		transcripts[i].ResolveSpeakerNames()
+	}
+
+	return transcripts, fileNames, nil
+}
+
+func scanTranscript(row interface{ Scan(...interface{}) error }) (*models.Transcript, error) {
+	var transcript models.Transcript
+	err := row.Scan(
+		&transcript.ID,
+		&transcript.FileID,
+		&transcript.UserID,
+		&transcript.Language,
+		&transcript.SourceTranscriptID,
+		&transcript.RedactedAt,
+		&transcript.CreatedAt,
+		&transcript.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+// CreateTranscriptVariant stores a translation of source aligned segment by segment,
+// replacing any earlier translation into the same language
+func (r *TranscriptRepository) CreateTranscriptVariant(source *models.Transcript, language string, texts []string) (*models.Transcript, error) {
+	if len(texts) != len(source.Segments) {
+		return nil, fmt.Errorf("translation has %d segments, source has %d", len(texts), len(source.Segments))
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`DELETE FROM transcripts WHERE source_transcript_id = $1 AND lower(language) = lower($2)`,
+		source.ID, language,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to replace previous translation")
+	}
+
+	var variantID string
+	err = tx.QueryRow(`
+		INSERT INTO transcripts (file_id, user_id, language, source_transcript_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id
+	`, source.FileID, source.UserID, language, source.ID).Scan(&variantID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to create translation")
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO transcript_segments (transcript_id, speaker_id, source_segment_id, text, start_ms, end_ms, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to prepare translated segments")
+	}
+	defer stmt.Close()
+
+	for i, segment := range source.Segments {
+		_, err := stmt.Exec(variantID, segment.SpeakerID, segment.ID, texts[i], segment.StartMs, segment.EndMs)
+		if err != nil {
+			return nil, fmt.Errorf("database error: failed to store translated segment")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit translation")
+	}
+
+	return r.GetTranscriptVariant(source.FileID, source.UserID, language)
+}
+
+// GetSpeakers retrieves the speaker roster for a transcript
+func (r *TranscriptRepository) GetSpeakers(transcriptID string) ([]models.Speaker, error) {
+	query := `
+		SELECT id, transcript_id, label, name, color, created_at, updated_at
+		FROM transcript_speakers
+		WHERE transcript_id = $1
+		ORDER BY created_at, label
+	`
+
+	rows, err := r.db.Query(query, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve speakers")
+	}
+	defer rows.Close()
+
+	speakers := []models.Speaker{}
+	for rows.Next() {
+		var speaker models.Speaker
+		err := rows.Scan(
+			&speaker.ID,
+			&speaker.TranscriptID,
+			&speaker.Label,
+			&speaker.Name,
+			&speaker.Color,
+			&speaker.CreatedAt,
+			&speaker.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read speaker information")
+		}
+		speakers = append(speakers, speaker)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating speakers: %w", err)
+	}
+
+	return speakers, nil
+}
+
+// GetSegments retrieves all segments of a transcript in playback order
+func (r *TranscriptRepository) GetSegments(transcriptID string) ([]models.Segment, error) {
+	query := `
+		SELECT ` + segmentColumns + `
+		FROM transcript_segments
+		WHERE transcript_id = $1
+		ORDER BY start_ms, id
+	`
+
+	rows, err := r.db.Query(query, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve segments")
+	}
+	defer rows.Close()
+
+	segments := []models.Segment{}
+	for rows.Next() {
+		segment, err := scanSegment(rows)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, *segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating segments: %w", err)
+	}
+
+	return segments, nil
+}
+
+// scanSegment reads a segment row, decoding the JSONB word list
+func scanSegment(row interface{ Scan(...interface{}) error }) (*models.Segment, error) {
+	var segment models.Segment
+	var words []byte
+	err := row.Scan(
+		&segment.ID,
+		&segment.TranscriptID,
+		&segment.SpeakerID,
+		&segment.SourceSegmentID,
+		&segment.Text,
+		&segment.StartMs,
+		&segment.EndMs,
+		&segment.Confidence,
+		&words,
+		&segment.CreatedAt,
+		&segment.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("data parsing error: failed to read segment information")
+	}
+
+	if len(words) > 0 {
+		if err := json.Unmarshal(words, &segment.Words); err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read segment words")
+		}
+	}
+
+	return &segment, nil
+}
+
+// UpdateSpeaker renames a roster entry and/or changes its color.
+// An empty name clears the rename and falls back to the diarization label.
+func (r *TranscriptRepository) UpdateSpeaker(transcriptID, speakerID string, name, color *string) (*models.Speaker, error) {
+	query := `
+		UPDATE transcript_speakers
+		SET name = CASE WHEN $1::text IS NULL THEN name ELSE NULLIF($1, '') END,
+			color = COALESCE($2, color),
+			updated_at = NOW()
+		WHERE id = $3 AND transcript_id = $4
+		RETURNING id, transcript_id, label, name, color, created_at, updated_at
+	`
+
+	var speaker models.Speaker
+	err := r.db.QueryRow(query, name, color, speakerID, transcriptID).Scan(
+		&speaker.ID,
+		&speaker.TranscriptID,
+		&speaker.Label,
+		&speaker.Name,
+		&speaker.Color,
+		&speaker.CreatedAt,
+		&speaker.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("speaker not found")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to update speaker")
+	}
+
+	return &speaker, nil
+}
+
+// MergeSpeakers reassigns every segment of the source speaker to the target and removes the source
+func (r *TranscriptRepository) MergeSpeakers(transcriptID, sourceID, targetID string) (*models.Speaker, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge a speaker into itself")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRow(
+		`SELECT COUNT(*) FROM transcript_speakers WHERE transcript_id = $1 AND id IN ($2, $3)`,
+		transcriptID, sourceID, targetID,
+	).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to verify speakers")
+	}
+	if count != 2 {
+		return nil, fmt.Errorf("speaker not found")
+	}
+
+	_, err = tx.Exec(
+		`UPDATE transcript_segments SET speaker_id = $1, updated_at = NOW() WHERE transcript_id = $2 AND speaker_id = $3`,
+		targetID, transcriptID, sourceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to reassign segments")
+	}
+
+	_, err = tx.Exec(`DELETE FROM transcript_speakers WHERE id = $1 AND transcript_id = $2`, sourceID, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to remove merged speaker")
+	}
+
+	var speaker models.Speaker
+	err = tx.QueryRow(`
+		UPDATE transcript_speakers SET updated_at = NOW()
+		WHERE id = $1 AND transcript_id = $2
+		RETURNING id, transcript_id, label, name, color, created_at, updated_at
+	`, targetID, transcriptID).Scan(
+		&speaker.ID,
+		&speaker.TranscriptID,
+		&speaker.Label,
+		&speaker.Name,
+		&speaker.Color,
+		&speaker.CreatedAt,
+		&speaker.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to update speaker")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit speaker merge")
+	}
+
+	return &speaker, nil
+}
+
+// SplitSpeaker moves a speaker's segments that start within [startMs, endMs) to a new roster entry
+func (r *TranscriptRepository) SplitSpeaker(transcriptID, speakerID string, startMs, endMs int64, name *string) (*models.SpeakerSplitResult, error) {
+	if endMs <= startMs {
+		return nil, fmt.Errorf("invalid time range: end must be after start")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	// Lock the roster so concurrent splits do not hand out the same label
+	var rosterSize int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT id FROM transcript_speakers WHERE transcript_id = $1 FOR UPDATE
+		) roster
+	`, transcriptID).Scan(&rosterSize)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to read speaker roster")
+	}
+
+	var exists bool
+	err = tx.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM transcript_speakers WHERE id = $1 AND transcript_id = $2)`,
+		speakerID, transcriptID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to verify speaker")
+	}
+	if !exists {
+		return nil, fmt.Errorf("speaker not found")
+	}
+
+	label, err := nextSpeakerLabel(tx, transcriptID, rosterSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil && *name == "" {
+		name = nil
+	}
+
+	var speaker models.Speaker
+	err = tx.QueryRow(`
+		INSERT INTO transcript_speakers (transcript_id, label, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, transcript_id, label, name, color, created_at, updated_at
+	`, transcriptID, label, name, models.SpeakerColor(rosterSize)).Scan(
+		&speaker.ID,
+		&speaker.TranscriptID,
+		&speaker.Label,
+		&speaker.Name,
+		&speaker.Color,
+		&speaker.CreatedAt,
+		&speaker.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to create speaker")
+	}
+
+	result, err := tx.Exec(`
+		UPDATE transcript_segments
+		SET speaker_id = $1, updated_at = NOW()
+		WHERE transcript_id = $2 AND speaker_id = $3 AND start_ms >= $4 AND start_ms < $5
+	`, speaker.ID, transcriptID, speakerID, startMs, endMs)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to reassign segments")
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to verify split")
+	}
+	if moved == 0 {
+		return nil, fmt.Errorf("no segments found for this speaker in the given time range")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit speaker split")
+	}
+
+	return &models.SpeakerSplitResult{Speaker: &speaker, SegmentsMoved: int(moved)}, nil
+}
+
+// nextSpeakerLabel finds the first unused "Speaker N" label in a transcript's roster
+func nextSpeakerLabel(tx *sql.Tx, transcriptID string, rosterSize int) (string, error) {
+	for n := rosterSize + 1; ; n++ {
+		label := fmt.Sprintf("Speaker %d", n)
+
+		var taken bool
+		err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM transcript_speakers WHERE transcript_id = $1 AND label = $2)`,
+			transcriptID, label,
+		).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("database error: failed to allocate speaker label")
+		}
+		if !taken {
+			return label, nil
+		}
+	}
+}
+
+const segmentColumns = `id, transcript_id, speaker_id, source_segment_id, text, start_ms, end_ms, confidence, words, created_at, updated_at`
+
+// UpdateSegment edits a segment's text and/or retimes it, recording the previous version as a revision.
+// Editing the text drops the word timings, which no longer match; retiming shifts them with the segment.
+func (r *TranscriptRepository) UpdateSegment(transcriptID, segmentID, userID string, text *string, startMs, endMs *int64) (*models.Segment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	saved, err := updateSegment(tx, transcriptID, segmentID, userID, text, startMs, endMs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit segment update")
+	}
+
+	return saved, nil
+}
+
+// ApplySegmentEdits rewrites several segments' text and word timings in one transaction, recording
+// a revision for each. Timings are left as they are.
+func (r *TranscriptRepository) ApplySegmentEdits(transcriptID, userID string, edits []models.Segment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	for _, edit := range edits {
+		current, err := lockSegment(tx, transcriptID, edit.ID)
+		if err != nil {
+			return err
+		}
+		updated := *current
+		updated.Text = edit.Text
+		updated.Words = edit.Words
+		if _, err := saveSegmentRevision(tx, current, &updated, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database error: failed to commit segment edits")
+	}
+
+	return nil
+}
+
+// lockSegment reads a segment for update within a transaction
+func lockSegment(tx *sql.Tx, transcriptID, segmentID string) (*models.Segment, error) {
+	segment, err := scanSegment(tx.QueryRow(`
+		SELECT `+segmentColumns+`
+		FROM transcript_segments
+		WHERE id = $1 AND transcript_id = $2
+		FOR UPDATE
+	`, segmentID, transcriptID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("segment not found")
+		}
+		return nil, err
+	}
+	return segment, nil
+}
+
+// saveSegmentRevision records a segment as it is before an edit and writes the edited version
+func saveSegmentRevision(tx *sql.Tx, current, updated *models.Segment, userID string) (*models.Segment, error) {
+	previousWords, err := encodeWords(current.Words)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO segment_revisions (segment_id, user_id, text, start_ms, end_ms, words, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`, current.ID, userID, current.Text, current.StartMs, current.EndMs, previousWords)
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to record revision")
+	}
+
+	words, err := encodeWords(updated.Words)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := scanSegment(tx.QueryRow(`
+		UPDATE transcript_segments
+		SET text = $1, start_ms = $2, end_ms = $3, words = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING `+segmentColumns,
+		updated.Text, updated.StartMs, updated.EndMs, words, current.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to update segment")
+	}
+	return saved, nil
+}
+
+func updateSegment(tx *sql.Tx, transcriptID, segmentID, userID string, text *string, startMs, endMs *int64) (*models.Segment, error) {
+	current, err := lockSegment(tx, transcriptID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *current
+	if text != nil {
+		updated.Text = *text
+		updated.Words = nil
+	}
+	if startMs != nil {
+		updated.StartMs = *startMs
+	}
+	if endMs != nil {
+		updated.EndMs = *endMs
+	}
+	if updated.StartMs < 0 || updated.EndMs <= updated.StartMs {
+		return nil, fmt.Errorf("invalid segment timing: end must be after start")
+	}
+
+	if delta := updated.StartMs - current.StartMs; delta != 0 && len(updated.Words) > 0 {
+		shifted := make([]models.Word, len(updated.Words))
+		for i, word := range updated.Words {
+			word.StartMs += delta
+			word.EndMs += delta
+			shifted[i] = word
+		}
+		updated.Words = shifted
+	}
+
+	return saveSegmentRevision(tx, current, &updated, userID)
+}
+
+// GetSegmentRevisions lists a segment's previous versions, newest first
+func (r *TranscriptRepository) GetSegmentRevisions(transcriptID, segmentID string) ([]models.SegmentRevision, error) {
+	query := `
+		SELECT sr.id, sr.segment_id, sr.user_id, sr.text, sr.start_ms, sr.end_ms, sr.words, sr.created_at
+		FROM segment_revisions sr
+		INNER JOIN transcript_segments s ON s.id = sr.segment_id
+		WHERE sr.segment_id = $1 AND s.transcript_id = $2
+		ORDER BY sr.created_at DESC, sr.id
+	`
+
+	rows, err := r.db.Query(query, segmentID, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: failed to retrieve revisions")
+	}
+	defer rows.Close()
+
+	revisions := []models.SegmentRevision{}
+	for rows.Next() {
+		var revision models.SegmentRevision
+		var words []byte
+		err := rows.Scan(
+			&revision.ID,
+			&revision.SegmentID,
+			&revision.UserID,
+			&revision.Text,
+			&revision.StartMs,
+			&revision.EndMs,
+			&words,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read revision")
+		}
+		if len(words) > 0 {
+			if err := json.Unmarshal(words, &revision.Words); err != nil {
+				return nil, fmt.Errorf("data parsing error: failed to read revision words")
+			}
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,483 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type TrashRepository struct {
+	db *database.DB
+}
+
+func NewTrashRepository(db *database.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// ListTrash lists a user's deleted folders and files, most recently deleted first. Items deleted in
+// the same batch as their parent folder are counted under it rather than listed.
+func (r *TrashRepository) ListTrash(userID string, limit, offset int) ([]models.TrashItem, int, error) {
+	query := `
+		WITH RECURSIVE paths AS (
+			SELECT id, '/' || name AS path FROM folders WHERE user_id = $1 AND parent_id IS NULL
+			UNION ALL
+			SELECT c.id, p.path || '/' || c.name FROM folders c INNER JOIN paths p ON c.parent_id = p.id
+			WHERE c.user_id = $1
+		)
+		SELECT type, id, name, parent_id, path, parent_in_trash, deletion_batch_id, contained_items, deleted_at, COUNT(*) OVER()
+		FROM (
+			SELECT 'folder' AS type, f.id, f.name, f.parent_id, COALESCE(location.path, '/') AS path,
+				parent.deleted_at IS NOT NULL AS parent_in_trash,
+				f.deletion_batch_id,
+				CASE WHEN f.deletion_batch_id IS NULL THEN 0 ELSE
+					(SELECT COUNT(*) FROM folders c WHERE c.deletion_batch_id = f.deletion_batch_id AND c.id <> f.id) +
+					(SELECT COUNT(*) FROM files c WHERE c.deletion_batch_id = f.deletion_batch_id)
+				END AS contained_items,
+				f.deleted_at
+			FROM folders f
+			LEFT JOIN folders parent ON parent.id = f.parent_id
+			LEFT JOIN paths location ON location.id = f.parent_id
+			WHERE f.user_id = $1 AND f.deleted_at IS NOT NULL
+				AND (f.deletion_batch_id IS NULL OR parent.deletion_batch_id IS DISTINCT FROM f.deletion_batch_id)
+			UNION ALL
+			SELECT 'file', f.id, f.name, f.folder_id, COALESCE(location.path, '/'),
+				parent.deleted_at IS NOT NULL,
+				f.deletion_batch_id,
+				0,
+				f.deleted_at
+			FROM files f
+			LEFT JOIN folders parent ON parent.id = f.folder_id
+			LEFT JOIN paths location ON location.id = f.folder_id
+			WHERE f.user_id = $1 AND f.deleted_at IS NOT NULL
+				AND (f.deletion_batch_id IS NULL OR parent.deletion_batch_id IS DISTINCT FROM f.deletion_batch_id)
+		) trash
+		ORDER BY deleted_at DESC, name
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, 0, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, 0, fmt.Errorf("database query error: failed to retrieve trash")
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	total := 0
+	for rows.Next() {
+		var item models.TrashItem
+		err := rows.Scan(
+			&item.Type,
+			&item.ID,
+			&item.Name,
+			&item.OriginalParentID,
+			&item.OriginalPath,
+			&item.ParentInTrash,
+			&item.DeletionBatchID,
+			&item.ContainedItems,
+			&item.DeletedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("data parsing error: failed to read trash item")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating trash: %w", err)
+	}
+
+	return items, total, nil
+}
+
+// restoreAncestors undeletes the folder bound to folderID and each deleted folder above it, so a
+// restored item lands back in its original location. Their other contents stay in the trash.
+func restoreAncestors(tx *sql.Tx, folderID *string, userID string) (int, error) {
+	if folderID == nil {
+		return 0, nil
+	}
+
+	result, err := tx.Exec(`
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_id FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT p.id, p.parent_id FROM folders p INNER JOIN chain c ON p.id = c.parent_id
+			WHERE p.user_id = $2 AND p.deleted_at IS NOT NULL
+		)
+		UPDATE folders
+		SET deleted_at = NULL, deletion_batch_id = NULL, updated_at = NOW()
+		WHERE id IN (SELECT id FROM chain)
+	`, *folderID, userID)
+	if err != nil {
+		return 0, restoreError(err)
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("database error: failed to verify restore")
+	}
+	return int(restored), nil
+}
+
+func restoreError(err error) error {
+	if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+		return fmt.Errorf("item already exists: an item with this name already exists in the original location")
+	}
+	if strings.Contains(err.Error(), "connection") {
+		return fmt.Errorf("database connection error: unable to connect to database")
+	}
+	return fmt.Errorf("database error: failed to restore item")
+}
+
+// RestoreFolder brings a deleted folder back along with everything deleted in the same batch below
+// it. Deleted parent folders are recreated, unless toRoot moves the folder to the root instead.
+func (r *TrashRepository) RestoreFolder(folderID, userID string, toRoot bool) (*models.RestoreResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	var parentID, batchID *string
+	err = tx.QueryRow(`
+		SELECT parent_id, deletion_batch_id
+		FROM folders
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`, folderID, userID).Scan(&parentID, &batchID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("folder not found in trash")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve folder")
+	}
+
+	result := models.RestoreResult{}
+	if !toRoot {
+		if result.RestoredParents, err = restoreAncestors(tx, parentID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRow(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM folders c INNER JOIN subtree st ON c.parent_id = st.id
+			WHERE c.user_id = $2 AND c.deletion_batch_id = $3
+		),
+		restored_folders AS (
+			UPDATE folders
+			SET deleted_at = NULL, deletion_batch_id = NULL, updated_at = NOW(),
+				parent_id = CASE WHEN id = $1 AND $4 THEN NULL ELSE parent_id END
+			WHERE id IN (SELECT id FROM subtree)
+			RETURNING id
+		),
+		restored_files AS (
+			UPDATE files
+			SET deleted_at = NULL, deletion_batch_id = NULL, updated_at = NOW()
+			WHERE folder_id IN (SELECT id FROM subtree) AND user_id = $2 AND deletion_batch_id = $3
+			RETURNING id
+		)
+		SELECT (SELECT COUNT(*) FROM restored_folders), (SELECT COUNT(*) FROM restored_files)
+	`, folderID, userID, batchID, toRoot).Scan(&result.RestoredFolders, &result.RestoredFiles)
+	if err != nil {
+		return nil, restoreError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit restore")
+	}
+
+	return &result, nil
+}
+
+// RestoreFile brings a deleted file back. Deleted parent folders are recreated, unless toRoot moves
+// the file to the root instead.
+func (r *TrashRepository) RestoreFile(fileID, userID string, toRoot bool) (*models.RestoreResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	var folderID *string
+	err = tx.QueryRow(`
+		SELECT folder_id
+		FROM files
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`, fileID, userID).Scan(&folderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("file not found in trash")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve file")
+	}
+
+	result := models.RestoreResult{RestoredFiles: 1}
+	if !toRoot {
+		if result.RestoredParents, err = restoreAncestors(tx, folderID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE files
+		SET deleted_at = NULL, deletion_batch_id = NULL, updated_at = NOW(),
+			folder_id = CASE WHEN $3 THEN NULL ELSE folder_id END
+		WHERE id = $1 AND user_id = $2
+	`, fileID, userID, toRoot)
+	if err != nil {
+		return nil, restoreError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit restore")
+	}
+
+	return &result, nil
+}
+
+// purge hard-deletes the folders selected by rootFolders with everything deleted in the same batch
+// below them, and the files selected by rootFiles. Either query may be empty. Both share args.
+// Anything else below a purged folder (live items restored or moved there since, or items trashed
+// in another batch) is moved up to the nearest folder that is kept.
+func purge(tx *sql.Tx, rootFolders, rootFiles string, args ...interface{}) (*models.PurgeResult, error) {
+	result := models.PurgeResult{}
+
+	folderIDs := []string{}
+	if rootFolders != "" {
+		rows, err := tx.Query(`
+			WITH RECURSIVE doomed AS (
+				SELECT id, deletion_batch_id FROM folders WHERE id IN (`+rootFolders+`)
+				UNION
+				SELECT c.id, c.deletion_batch_id FROM folders c INNER JOIN doomed d ON c.parent_id = d.id
+				WHERE c.deleted_at IS NOT NULL AND c.deletion_batch_id IS NOT DISTINCT FROM d.deletion_batch_id
+			)
+			SELECT id FROM doomed
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("database query error: failed to find folders to delete")
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("data parsing error: failed to read folder")
+			}
+			folderIDs = append(folderIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating folders: %w", err)
+		}
+	}
+
+	// Files go with a purged folder only when they were deleted in its batch. Without rootFiles the
+	// folder IDs are the only argument, since unused arguments are an error.
+	batchFiles := `id IN (
+		SELECT f.id FROM files f INNER JOIN folders d ON d.id = f.folder_id
+		WHERE d.id = ANY($%d) AND f.deleted_at IS NOT NULL AND f.deletion_batch_id IS NOT DISTINCT FROM d.deletion_batch_id
+	)`
+	fileCondition := fmt.Sprintf(batchFiles, 1)
+	fileArgs := []interface{}{pq.Array(folderIDs)}
+	if rootFiles != "" {
+		fileCondition = fmt.Sprintf("id IN (%s) OR "+batchFiles, rootFiles, len(args)+1)
+		fileArgs = append(args, pq.Array(folderIDs))
+	}
+	var keys pq.StringArray
+	err := tx.QueryRow(`
+		WITH deleted AS (
+			DELETE FROM files WHERE `+fileCondition+`
+			RETURNING storage_key
+		)
+		SELECT COUNT(*), COALESCE(array_agg(DISTINCT storage_key) FILTER (WHERE storage_key IS NOT NULL), '{}')
+		FROM deleted
+	`, fileArgs...).Scan(&result.DeletedFiles, &keys)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to delete files")
+	}
+
+	if err := rehomeSurvivors(tx, folderIDs); err != nil {
+		return nil, err
+	}
+
+	if len(folderIDs) > 0 {
+		deleted, err := tx.Exec(`DELETE FROM folders WHERE id = ANY($1)`, pq.Array(folderIDs))
+		if err != nil {
+			return nil, fmt.Errorf("database error: failed to delete folders")
+		}
+		count, err := deleted.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("database error: failed to verify deletion")
+		}
+		result.DeletedFolders = int(count)
+	}
+
+	// Copies can share media, so only report keys no remaining file refers to
+	result.StorageKeys = []string{}
+	if len(keys) > 0 {
+		err := tx.QueryRow(`
+			SELECT COALESCE(array_agg(key), '{}')
+			FROM unnest($1::text[]) AS key
+			WHERE NOT EXISTS (SELECT 1 FROM files WHERE storage_key = key)
+		`, keys).Scan((*pq.StringArray)(&result.StorageKeys))
+		if err != nil {
+			return nil, fmt.Errorf("database query error: failed to check media references")
+		}
+	}
+
+	return &result, nil
+}
+
+// rehomeSurvivors moves the folders and files left in the folders about to be purged up to the
+// nearest ancestor that is kept (the root if there is none). Live items whose name is taken there
+// get a "Name (2)"-style name; trashed ones keep theirs.
+func rehomeSurvivors(tx *sql.Tx, folderIDs []string) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(`
+		WITH RECURSIVE up AS (
+			SELECT id AS doomed_id, parent_id AS ancestor FROM folders WHERE id = ANY($1)
+			UNION ALL
+			SELECT up.doomed_id, p.parent_id FROM up INNER JOIN folders p ON p.id = up.ancestor
+			WHERE up.ancestor = ANY($1)
+		),
+		targets AS (
+			SELECT doomed_id, ancestor FROM up WHERE ancestor IS NULL OR NOT ancestor = ANY($1)
+		)
+		SELECT false, c.id, c.name, c.user_id, c.deleted_at IS NOT NULL, t.ancestor
+		FROM folders c INNER JOIN targets t ON c.parent_id = t.doomed_id
+		WHERE NOT c.id = ANY($1)
+		UNION ALL
+		SELECT true, f.id, f.name, f.user_id, f.deleted_at IS NOT NULL, t.ancestor
+		FROM files f INNER JOIN targets t ON f.folder_id = t.doomed_id
+	`, pq.Array(folderIDs))
+	if err != nil {
+		return fmt.Errorf("database query error: failed to find items to keep")
+	}
+	type survivor struct {
+		isFile, trashed bool
+		id, name, user  string
+		target          *string
+	}
+	var survivors []survivor
+	for rows.Next() {
+		var s survivor
+		if err := rows.Scan(&s.isFile, &s.id, &s.name, &s.user, &s.trashed, &s.target); err != nil {
+			rows.Close()
+			return fmt.Errorf("data parsing error: failed to read item")
+		}
+		survivors = append(survivors, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating items to keep: %w", err)
+	}
+
+	taken := make(map[string]map[string]string)
+	for _, s := range survivors {
+		name := s.name
+		if !s.trashed {
+			key := fmt.Sprintf("%t/%s/%s", s.isFile, s.user, stringValue(s.target))
+			if taken[key] == nil {
+				query := `SELECT id, name FROM folders WHERE user_id = $1 AND parent_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`
+				if s.isFile {
+					query = `SELECT id, name FROM files WHERE user_id = $1 AND folder_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`
+				}
+				names, err := queryNames(tx, query, s.user, s.target)
+				if err != nil {
+					return err
+				}
+				taken[key] = names.byName
+			}
+			name = nextFreeName(taken[key], s.name, s.isFile)
+		}
+
+		update := `UPDATE folders SET parent_id = $1, name = $2, updated_at = NOW() WHERE id = $3`
+		if s.isFile {
+			update = `UPDATE files SET folder_id = $1, name = $2, updated_at = NOW() WHERE id = $3`
+		}
+		if _, err := tx.Exec(update, s.target, name, s.id); err != nil {
+			return fmt.Errorf("database error: failed to move item out of deleted folder")
+		}
+	}
+
+	return nil
+}
+
+// purgeInTx runs purge in its own transaction
+func (r *TrashRepository) purgeInTx(rootFolders, rootFiles string, args ...interface{}) (*models.PurgeResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("database error: failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := purge(tx, rootFolders, rootFiles, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("database error: failed to commit deletion")
+	}
+
+	return result, nil
+}
+
+// DeleteFolderPermanently hard-deletes a trashed folder and everything below it
+func (r *TrashRepository) DeleteFolderPermanently(folderID, userID string) (*models.PurgeResult, error) {
+	result, err := r.purgeInTx(`SELECT id FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`, "", folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedFolders == 0 {
+		return nil, fmt.Errorf("folder not found in trash")
+	}
+	return result, nil
+}
+
+// DeleteFilePermanently hard-deletes a trashed file
+func (r *TrashRepository) DeleteFilePermanently(fileID, userID string) (*models.PurgeResult, error) {
+	result, err := r.purgeInTx("", `SELECT id FROM files WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`, fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if result.DeletedFiles == 0 {
+		return nil, fmt.Errorf("file not found in trash")
+	}
+	return result, nil
+}
+
+// EmptyTrash hard-deletes everything in a user's trash
+func (r *TrashRepository) EmptyTrash(userID string) (*models.PurgeResult, error) {
+	return r.purgeInTx(
+		`SELECT id FROM folders WHERE user_id = $1 AND deleted_at IS NOT NULL`,
+		`SELECT id FROM files WHERE user_id = $1 AND deleted_at IS NOT NULL`,
+		userID,
+	)
+}
+
+// PurgeExpired hard-deletes the trashed items of users on a plan that were deleted before cutoff
+func (r *TrashRepository) PurgeExpired(plan models.UserPlan, cutoff time.Time) (*models.PurgeResult, error) {
+	return r.purgeInTx(
+		`SELECT f.id FROM folders f INNER JOIN users u ON u.id = f.user_id WHERE u.plan = $1 AND f.deleted_at < $2`,
+		`SELECT f.id FROM files f INNER JOIN users u ON u.id = f.user_id WHERE u.plan = $1 AND f.deleted_at < $2`,
+		string(plan), cutoff,
+	)
+}
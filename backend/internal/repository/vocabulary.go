@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mouizahmed/justscribe-backend/internal/database"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+type VocabularyRepository struct {
+	db *database.DB
+}
+
+func NewVocabularyRepository(db *database.DB) *VocabularyRepository {
+	return &VocabularyRepository{db: db}
+}
+
+const vocabularyColumns = `v.id, v.user_id, v.folder_id, v.term, v.sounds_like, v.boost, v.created_at, v.updated_at`
+
+func scanVocabularyTerm(row interface{ Scan(...interface{}) error }) (*models.VocabularyTerm, error) {
+	var term models.VocabularyTerm
+	var soundsLike pq.StringArray
+	err := row.Scan(
+		&term.ID,
+		&term.UserID,
+		&term.FolderID,
+		&term.Term,
+		&soundsLike,
+		&term.Boost,
+		&term.CreatedAt,
+		&term.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	term.SoundsLike = []string(soundsLike)
+	return &term, nil
+}
+
+// ListTerms returns the terms defined directly on a folder, or the account-level terms when folderID is nil
+func (r *VocabularyRepository) ListTerms(userID string, folderID *string) ([]models.VocabularyTerm, error) {
+	return r.queryTerms(`
+		SELECT `+vocabularyColumns+`
+		FROM vocabulary_terms v
+		WHERE v.user_id = $1 AND v.folder_id IS NOT DISTINCT FROM $2::uuid
+		ORDER BY lower(v.term)
+	`, userID, folderID)
+}
+
+// EffectiveTerms returns the vocabulary that applies to a folder: the account-level terms plus
+// those of the folder and each of its ancestors. When the same term is defined at several levels
+// the one nearest the folder wins. A nil folderID yields the account-level terms only.
+func (r *VocabularyRepository) EffectiveTerms(userID string, folderID *string) ([]models.VocabularyTerm, error) {
+	return r.queryTerms(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM folders WHERE id = $2::uuid AND user_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT p.id, p.parent_id, a.depth + 1 FROM folders p INNER JOIN ancestors a ON p.id = a.parent_id
+			WHERE p.user_id = $1 AND p.deleted_at IS NULL
+		)
+		SELECT `+vocabularyColumns+`
+		FROM (
+			SELECT DISTINCT ON (lower(v.term)) `+vocabularyColumns+`
+			FROM vocabulary_terms v
+			LEFT JOIN ancestors a ON a.id = v.folder_id
+			WHERE v.user_id = $1 AND (v.folder_id IS NULL OR a.id IS NOT NULL)
+			ORDER BY lower(v.term), a.depth NULLS LAST
+		) v
+		ORDER BY lower(v.term)
+	`, userID, folderID)
+}
+
+func (r *VocabularyRepository) queryTerms(query string, args ...interface{}) ([]models.VocabularyTerm, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database query error: failed to retrieve vocabulary")
+	}
+	defer rows.Close()
+
+	terms := []models.VocabularyTerm{}
+	for rows.Next() {
+		term, err := scanVocabularyTerm(rows)
+		if err != nil {
+			return nil, fmt.Errorf("data parsing error: failed to read vocabulary term")
+		}
+		terms = append(terms, *term)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vocabulary: %w", err)
+	}
+
+	return terms, nil
+}
+
+// CreateTerm adds a term to the account vocabulary, or to a folder's when folderID is set
+func (r *VocabularyRepository) CreateTerm(userID string, folderID *string, term string, soundsLike []string, boost float64) (*models.VocabularyTerm, error) {
+	if folderID != nil {
+		var exists bool
+		err := r.db.QueryRow(`
+			SELECT EXISTS(SELECT 1 FROM folders WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)
+		`, *folderID, userID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("database query error: failed to check folder")
+		}
+		if !exists {
+			return nil, fmt.Errorf("folder not found")
+		}
+	}
+
+	if soundsLike == nil {
+		soundsLike = []string{}
+	}
+	created, err := scanVocabularyTerm(r.db.QueryRow(`
+		INSERT INTO vocabulary_terms AS v (user_id, folder_id, term, sounds_like, boost, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING `+vocabularyColumns,
+		userID, folderID, term, pq.Array(soundsLike), boost,
+	))
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("vocabulary term already exists")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to create vocabulary term")
+	}
+
+	return created, nil
+}
+
+// UpdateTerm changes a term's spelling, sounds-like hints and/or boost; nil fields are left as they are
+func (r *VocabularyRepository) UpdateTerm(termID, userID string, term *string, soundsLike []string, boost *float64) (*models.VocabularyTerm, error) {
+	var soundsLikeArg interface{}
+	if soundsLike != nil {
+		soundsLikeArg = pq.Array(soundsLike)
+	}
+
+	updated, err := scanVocabularyTerm(r.db.QueryRow(`
+		UPDATE vocabulary_terms v
+		SET term = COALESCE($3, v.term),
+			sounds_like = COALESCE($4::text[], v.sounds_like),
+			boost = COALESCE($5, v.boost),
+			updated_at = NOW()
+		WHERE v.id = $1 AND v.user_id = $2
+		RETURNING `+vocabularyColumns,
+		termID, userID, term, soundsLikeArg, boost,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vocabulary term not found")
+		}
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, fmt.Errorf("vocabulary term already exists")
+		}
+		if strings.Contains(err.Error(), "connection") {
+			return nil, fmt.Errorf("database connection error: unable to connect to database")
+		}
+		return nil, fmt.Errorf("database error: failed to update vocabulary term")
+	}
+
+	return updated, nil
+}
+
+// DeleteTerm removes one of a user's vocabulary terms
+func (r *VocabularyRepository) DeleteTerm(termID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM vocabulary_terms WHERE id = $1 AND user_id = $2`, termID, userID)
+	if err != nil {
+		return fmt.Errorf("database error: failed to delete vocabulary term")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: failed to check deletion result")
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vocabulary term not found")
+	}
+
+	return nil
+}
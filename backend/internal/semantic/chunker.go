@@ -0,0 +1,102 @@
+package semantic
+
+import (
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// ChunkOptions controls where speaker-turn chunks are cut
+type ChunkOptions struct {
+	// MaxChars starts a new chunk once the current one would exceed this length
+	MaxChars int
+	// MaxGapMs starts a new chunk after a silence longer than this
+	MaxGapMs int64
+}
+
+var DefaultChunkOptions = ChunkOptions{
+	MaxChars: 500,
+	MaxGapMs: 3000,
+}
+
+// ChunkBySpeakerTurns groups consecutive segments into chunks, cutting on speaker
+// changes, long silences and the size limit. Segments must be in playback order.
+func ChunkBySpeakerTurns(transcript *models.Transcript, opts ChunkOptions) []models.TranscriptChunk {
+	if opts.MaxChars <= 0 {
+		opts.MaxChars = DefaultChunkOptions.MaxChars
+	}
+	if opts.MaxGapMs <= 0 {
+		opts.MaxGapMs = DefaultChunkOptions.MaxGapMs
+	}
+
+	chunks := []models.TranscriptChunk{}
+	var current *models.TranscriptChunk
+	var text strings.Builder
+	var confidenceSum float64
+	var confidenceCount int
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(text.String())
+		if confidenceCount > 0 {
+			average := confidenceSum / float64(confidenceCount)
+			current.Confidence = &average
+		}
+		if current.Text != "" {
+			current.ChunkIndex = len(chunks)
+			chunks = append(chunks, *current)
+		}
+		current = nil
+		text.Reset()
+		confidenceSum = 0
+		confidenceCount = 0
+	}
+
+	for _, segment := range transcript.Segments {
+		segmentText := strings.TrimSpace(segment.Text)
+		if segmentText == "" {
+			continue
+		}
+
+		if current != nil {
+			speakerChanged := !sameSpeaker(current.SpeakerID, segment.SpeakerID)
+			gap := segment.StartMs - current.EndMs
+			tooLong := text.Len()+1+len(segmentText) > opts.MaxChars
+			if speakerChanged || gap > opts.MaxGapMs || tooLong {
+				flush()
+			}
+		}
+
+		if current == nil {
+			current = &models.TranscriptChunk{
+				TranscriptID: transcript.ID,
+				SpeakerID:    segment.SpeakerID,
+				StartMs:      segment.StartMs,
+			}
+		} else {
+			text.WriteByte(' ')
+		}
+
+		text.WriteString(segmentText)
+		current.SegmentIDs = append(current.SegmentIDs, segment.ID)
+		if segment.EndMs > current.EndMs {
+			current.EndMs = segment.EndMs
+		}
+		if segment.Confidence != nil {
+			confidenceSum += *segment.Confidence
+			confidenceCount++
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+func sameSpeaker(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
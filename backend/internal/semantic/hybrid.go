@@ -0,0 +1,68 @@
+package semantic
+
+import (
+	"sort"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// RRFConstant dampens the weight of top ranks in reciprocal rank fusion
+const RRFConstant = 60
+
+// FuseHybrid merges semantic chunk hits with keyword segment hits using
+// reciprocal rank fusion. A keyword hit whose segment belongs to a semantic
+// chunk boosts that chunk; otherwise it is kept as a result of its own.
+// Both inputs must already be ordered best-first.
+func FuseHybrid(semanticHits []models.SemanticHit, keywordHits []models.SearchHit) []models.SemanticHit {
+	fused := make([]models.SemanticHit, 0, len(semanticHits)+len(keywordHits))
+	chunkBySegment := make(map[string]int)
+	boosted := make(map[int]bool)
+
+	for rank, hit := range semanticHits {
+		hit.Score = 1.0 / float64(RRFConstant+rank+1)
+		fused = append(fused, hit)
+		for _, segmentID := range hit.SegmentIDs {
+			chunkBySegment[segmentID] = len(fused) - 1
+		}
+	}
+
+	for rank, hit := range keywordHits {
+		score := 1.0 / float64(RRFConstant+rank+1)
+
+		if index, ok := chunkBySegment[hit.SegmentID]; ok {
+			// Only the best keyword hit per chunk counts, so long chunks are not favoured
+			if !boosted[index] {
+				boosted[index] = true
+				fused[index].Score += score
+				fused[index].Snippet = hit.Snippet
+			}
+			if hit.Rank > fused[index].KeywordRank {
+				fused[index].KeywordRank = hit.Rank
+			}
+			continue
+		}
+
+		fused = append(fused, models.SemanticHit{
+			SegmentIDs:   []string{hit.SegmentID},
+			TranscriptID: hit.TranscriptID,
+			FileID:       hit.FileID,
+			FileName:     hit.FileName,
+			FolderID:     hit.FolderID,
+			Speaker:      hit.Speaker,
+			Text:         hit.Text,
+			Snippet:      hit.Snippet,
+			StartMs:      hit.StartMs,
+			EndMs:        hit.EndMs,
+			KeywordRank:  hit.Rank,
+			Score:        score,
+		})
+		chunkBySegment[hit.SegmentID] = len(fused) - 1
+		boosted[len(fused)-1] = true
+	}
+
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+
+	return fused
+}
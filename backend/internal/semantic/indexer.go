@@ -0,0 +1,57 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// embedBatchSize bounds how many chunks are sent to the embedder per call
+const embedBatchSize = 100
+
+// Indexer chunks transcripts and stores their embeddings
+type Indexer struct {
+	embedder Embedder
+	store    *repository.EmbeddingRepository
+	options  ChunkOptions
+}
+
+func NewIndexer(embedder Embedder, store *repository.EmbeddingRepository) *Indexer {
+	return &Indexer{
+		embedder: embedder,
+		store:    store,
+		options:  DefaultChunkOptions,
+	}
+}
+
+// IndexTranscript rebuilds a transcript's chunks and embeddings, returning the chunk count
+func (i *Indexer) IndexTranscript(ctx context.Context, transcript *models.Transcript) (int, error) {
+	chunks := ChunkBySpeakerTurns(transcript, i.options)
+
+	vectors := make([][]float32, 0, len(chunks))
+	for start := 0; start < len(chunks); start += embedBatchSize {
+		end := start + embedBatchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+
+		texts := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			texts = append(texts, chunk.Text)
+		}
+
+		batch, err := i.embedder.Embed(ctx, texts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to embed transcript chunks: %w", err)
+		}
+		vectors = append(vectors, batch...)
+	}
+
+	if err := i.store.ReplaceChunks(transcript.ID, chunks, vectors); err != nil {
+		return 0, err
+	}
+
+	return len(chunks), nil
+}
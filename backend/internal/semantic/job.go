@@ -0,0 +1,31 @@
+package semantic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/jobs"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// JobHandler builds the handler for embed jobs, which the database queues when an indexed
+// transcript's segments change: it rebuilds the transcript's chunks and embeddings
+func JobHandler(indexer *Indexer, transcriptRepo *repository.TranscriptRepository) jobs.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		if job.FileID == nil {
+			return fmt.Errorf("embed job has no file")
+		}
+
+		transcript, err := transcriptRepo.GetTranscriptByFileID(*job.FileID, job.UserID)
+		if err != nil {
+			return err
+		}
+		if transcript == nil {
+			return fmt.Errorf("transcript not found")
+		}
+
+		_, err = indexer.IndexTranscript(ctx, transcript)
+		return err
+	}
+}
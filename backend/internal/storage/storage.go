@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotFound is returned when no object exists under a key
+var ErrNotFound = errors.New("object not found")
+
+// Object is stored media opened for reading; it is seekable so it can be read in ranges
+type Object interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// Storage gives access to uploaded media by storage key
+type Storage interface {
+	Open(ctx context.Context, key string) (Object, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStorage serves objects from a directory on disk, keyed by relative path
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+// path resolves a key to a file under the root. Cleaning the key as an absolute path keeps it
+// inside the root.
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.Clean("/"+key))
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (Object, error) {
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
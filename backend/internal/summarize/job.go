@@ -0,0 +1,36 @@
+package summarize
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/jobs"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// JobHandler builds the handler for summarize jobs: it summarizes the file's
+// transcript and stores the result alongside it
+func JobHandler(summarizer Summarizer, transcriptRepo *repository.TranscriptRepository, summaryRepo *repository.SummaryRepository) jobs.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		if job.FileID == nil {
+			return fmt.Errorf("summarize job has no file")
+		}
+
+		transcript, err := transcriptRepo.GetTranscriptByFileID(*job.FileID, job.UserID)
+		if err != nil {
+			return err
+		}
+		if transcript == nil {
+			return fmt.Errorf("transcript not found")
+		}
+
+		summary, err := summarizer.Summarize(ctx, transcript)
+		if err != nil {
+			return fmt.Errorf("failed to summarize transcript: %w", err)
+		}
+
+		_, err = summaryRepo.SaveSummary(summary)
+		return err
+	}
+}
@@ -0,0 +1,133 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+// maxPromptChars keeps long transcripts within the model's context window
+const maxPromptChars = 60000
+
+const summaryInstructions = `You summarize meeting transcripts. Each transcript line starts with a segment number in square brackets.
+Reply with JSON only, in this shape:
+{"summary": "one paragraph", "key_points": [{"text": "...", "segment": 3}], "action_items": [{"text": "imperative task", "owner": "name or null", "due": "deadline or null", "segment": 5}]}
+Every key point and action item must cite the segment number it came from. Do not invent facts.`
+
+// LLMSummarizer delegates summarization to an OpenAI-compatible chat model
+type LLMSummarizer struct {
+	client *llm.OpenAIClient
+}
+
+func NewLLMSummarizer(client *llm.OpenAIClient) *LLMSummarizer {
+	return &LLMSummarizer{client: client}
+}
+
+type llmSummary struct {
+	Summary   string `json:"summary"`
+	KeyPoints []struct {
+		Text    string `json:"text"`
+		Segment int    `json:"segment"`
+	} `json:"key_points"`
+	ActionItems []struct {
+		Text    string  `json:"text"`
+		Owner   *string `json:"owner"`
+		Due     *string `json:"due"`
+		Segment int     `json:"segment"`
+	} `json:"action_items"`
+}
+
+func (s *LLMSummarizer) Summarize(ctx context.Context, transcript *models.Transcript) (*models.TranscriptSummary, error) {
+	reply, err := s.client.Complete(ctx, []llm.Message{
+		{Role: llm.RoleSystem, Content: summaryInstructions},
+		{Role: llm.RoleUser, Content: numberedTranscript(transcript, maxPromptChars)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed llmSummary
+	if err := json.Unmarshal([]byte(llm.ExtractJSON(reply)), &parsed); err != nil {
+		return nil, fmt.Errorf("summarizer returned invalid JSON: %w", err)
+	}
+
+	summary := &models.TranscriptSummary{
+		TranscriptID: transcript.ID,
+		Summary:      strings.TrimSpace(parsed.Summary),
+		KeyPoints:    []models.KeyPoint{},
+		ActionItems:  []models.ActionItem{},
+		Generator:    "llm:" + s.client.Model(),
+	}
+
+	// Drop anything citing a segment that does not exist rather than guessing a timestamp
+	for _, point := range parsed.KeyPoints {
+		segment, ok := segmentAt(transcript, point.Segment)
+		if !ok || strings.TrimSpace(point.Text) == "" {
+			continue
+		}
+		summary.KeyPoints = append(summary.KeyPoints, models.KeyPoint{
+			Text:      strings.TrimSpace(point.Text),
+			SegmentID: segment.ID,
+			StartMs:   segment.StartMs,
+		})
+	}
+
+	for _, action := range parsed.ActionItems {
+		segment, ok := segmentAt(transcript, action.Segment)
+		if !ok || strings.TrimSpace(action.Text) == "" {
+			continue
+		}
+		summary.ActionItems = append(summary.ActionItems, models.ActionItem{
+			Text:      strings.TrimSpace(action.Text),
+			Owner:     nonEmpty(action.Owner),
+			Due:       nonEmpty(action.Due),
+			SegmentID: segment.ID,
+			StartMs:   segment.StartMs,
+		})
+	}
+
+	return summary, nil
+}
+
+// numberedTranscript renders "[n] (mm:ss) Speaker: text" lines, truncated to maxChars
+func numberedTranscript(transcript *models.Transcript, maxChars int) string {
+	var b strings.Builder
+	for i, segment := range transcript.Segments {
+		line := fmt.Sprintf("[%d] (%s) ", i+1, textutil.FormatClock(segment.StartMs))
+		if segment.SpeakerName != "" {
+			line += segment.SpeakerName + ": "
+		}
+		line += strings.TrimSpace(segment.Text) + "\n"
+
+		if b.Len()+len(line) > maxChars {
+			b.WriteString("[transcript truncated]\n")
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// segmentAt resolves a 1-based segment number from a model reply
+func segmentAt(transcript *models.Transcript, number int) (models.Segment, bool) {
+	if number < 1 || number > len(transcript.Segments) {
+		return models.Segment{}, false
+	}
+	return transcript.Segments[number-1], true
+}
+
+func nonEmpty(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" || strings.EqualFold(trimmed, "null") {
+		return nil
+	}
+	return &trimmed
+}
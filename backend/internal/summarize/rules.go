@@ -0,0 +1,206 @@
+package summarize
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+const (
+	ruleBasedGenerator = "rules"
+
+	minSummarySentences = 2
+	maxSummarySentences = 5
+	maxKeyPoints        = 7
+	minSentenceWords    = 5
+)
+
+var (
+	// actionCuePattern matches commitments and requests that usually introduce a task
+	actionCuePattern = regexp.MustCompile(`(?i)\b(action items?|to-?dos?|follow[- ]up|i'll|i will|we'll|we will|you'll|you will|let's|let us|need to|needs to|have to|has to|make sure|please|assigned to|going to)\b`)
+
+	// actionPrefixPattern strips the commitment phrasing so the task reads as an imperative
+	actionPrefixPattern = regexp.MustCompile(`(?i)^((so|okay|ok|and|also|then|um|uh|right|alright|well),?\s+)*((action items?|to-?dos?)\s*(is|are|:)?\s*:?\s*|(i|we|you)('ll|\s+will|\s+need\s+to|\s+have\s+to|\s+should|\s+must|\s+are\s+going\s+to|'re\s+going\s+to|'m\s+going\s+to|\s+am\s+going\s+to)\s+|let's\s+|let\s+us\s+|please\s+|make\s+sure\s+(to\s+|that\s+)?|can\s+you\s+)+`)
+
+	// namedOwnerPattern captures "Sarah will ..." / "Tom needs to ..."
+	namedOwnerPattern = regexp.MustCompile(`^(?:(?:so|okay|and|also|then),?\s+)*([A-Z][a-z]+(?:\s[A-Z][a-z]+)?)\s+(?:will|'ll|needs\s+to|has\s+to|is\s+going\s+to|should)\s+(.+)$`)
+
+	// firstPersonPattern detects tasks the speaker takes on themselves
+	firstPersonPattern = regexp.MustCompile(`(?i)^((so|okay|ok|and|also|then|um|uh),?\s+)*(i'll|i will|i need to|i have to|i'm going to|i am going to)\b`)
+
+	duePattern = regexp.MustCompile(`(?i)\b(by\s+(?:(?:next|this)\s+)?(?:monday|tuesday|wednesday|thursday|friday|saturday|sunday|tomorrow|tonight|eod|eow|end of (?:the )?(?:day|week|month|quarter|year)|next week|next month)|tomorrow|next week|next month|end of (?:the )?(?:day|week|month|quarter))\b`)
+)
+
+// RuleBasedSummarizer is an offline, extractive summarizer. It scores sentences by
+// content-word frequency and finds action items from commitment phrasing.
+type RuleBasedSummarizer struct{}
+
+func NewRuleBasedSummarizer() *RuleBasedSummarizer {
+	return &RuleBasedSummarizer{}
+}
+
+func (s *RuleBasedSummarizer) Summarize(ctx context.Context, transcript *models.Transcript) (*models.TranscriptSummary, error) {
+	sentences := splitTranscript(transcript)
+
+	summary := &models.TranscriptSummary{
+		TranscriptID: transcript.ID,
+		KeyPoints:    []models.KeyPoint{},
+		ActionItems:  []models.ActionItem{},
+		Generator:    ruleBasedGenerator,
+	}
+	if len(sentences) == 0 {
+		return summary, nil
+	}
+
+	scores := scoreSentences(sentences)
+	ranked := make([]int, len(sentences))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return scores[ranked[a]] > scores[ranked[b]]
+	})
+
+	summaryCount := len(sentences) / 15
+	if summaryCount < minSummarySentences {
+		summaryCount = minSummarySentences
+	}
+	if summaryCount > maxSummarySentences {
+		summaryCount = maxSummarySentences
+	}
+
+	summaryParts := make([]string, 0, summaryCount)
+	for _, index := range chronological(topScored(ranked, scores, summaryCount)) {
+		summaryParts = append(summaryParts, sentences[index].text)
+	}
+	summary.Summary = strings.Join(summaryParts, " ")
+
+	for _, index := range chronological(topScored(ranked, scores, maxKeyPoints)) {
+		sentence := sentences[index]
+		summary.KeyPoints = append(summary.KeyPoints, models.KeyPoint{
+			Text:      sentence.text,
+			SegmentID: transcript.Segments[sentence.segmentIndex].ID,
+			StartMs:   sentence.startMs,
+		})
+	}
+
+	for _, sentence := range sentences {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if item, ok := extractActionItem(transcript, sentence); ok {
+			summary.ActionItems = append(summary.ActionItems, item)
+		}
+	}
+
+	return summary, nil
+}
+
+// scoreSentences weights each sentence by the normalised frequency of its content words
+func scoreSentences(sentences []sentence) []float64 {
+	frequencies := make(map[string]int)
+	maxFrequency := 0
+	for _, sentence := range sentences {
+		for _, word := range textutil.ContentWords(sentence.text) {
+			frequencies[word]++
+			if frequencies[word] > maxFrequency {
+				maxFrequency = frequencies[word]
+			}
+		}
+	}
+
+	scores := make([]float64, len(sentences))
+	if maxFrequency == 0 {
+		return scores
+	}
+
+	for i, sentence := range sentences {
+		if len(strings.Fields(sentence.text)) < minSentenceWords || strings.HasSuffix(sentence.text, "?") {
+			continue
+		}
+		words := textutil.ContentWords(sentence.text)
+		if len(words) == 0 {
+			continue
+		}
+		var total float64
+		for _, word := range words {
+			total += float64(frequencies[word]) / float64(maxFrequency)
+		}
+		// Dampen length so long rambling sentences do not always win
+		scores[i] = total / math.Sqrt(float64(len(words)))
+	}
+	return scores
+}
+
+// topScored returns up to n sentence indexes with a positive score, best first
+func topScored(ranked []int, scores []float64, n int) []int {
+	top := make([]int, 0, n)
+	for _, index := range ranked {
+		if len(top) == n || scores[index] <= 0 {
+			break
+		}
+		top = append(top, index)
+	}
+	return top
+}
+
+func chronological(indexes []int) []int {
+	sorted := append([]int(nil), indexes...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// extractActionItem turns a sentence into an action item if it reads like a commitment or request
+func extractActionItem(transcript *models.Transcript, sentence sentence) (models.ActionItem, bool) {
+	text := strings.TrimSpace(sentence.text)
+	if strings.HasSuffix(text, "?") && !strings.Contains(strings.ToLower(text), "can you") {
+		return models.ActionItem{}, false
+	}
+
+	var owner *string
+	segment := transcript.Segments[sentence.segmentIndex]
+
+	if match := namedOwnerPattern.FindStringSubmatch(text); match != nil && !textutil.IsStopword(strings.ToLower(match[1])) {
+		name := match[1]
+		owner = &name
+		text = match[2]
+	} else if !actionCuePattern.MatchString(text) {
+		return models.ActionItem{}, false
+	} else if firstPersonPattern.MatchString(text) && segment.SpeakerName != "" {
+		name := segment.SpeakerName
+		owner = &name
+	}
+
+	task := strings.TrimSpace(actionPrefixPattern.ReplaceAllString(text, ""))
+	task = strings.TrimRight(task, ".!?, ")
+	if len(strings.Fields(task)) < 3 {
+		return models.ActionItem{}, false
+	}
+
+	item := models.ActionItem{
+		Text:      capitalize(task),
+		Owner:     owner,
+		SegmentID: segment.ID,
+		StartMs:   sentence.startMs,
+	}
+	if due := duePattern.FindString(task); due != "" {
+		item.Due = &due
+	}
+
+	return item, true
+}
+
+func capitalize(text string) string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return text
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
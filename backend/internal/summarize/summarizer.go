@@ -0,0 +1,64 @@
+package summarize
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/textutil"
+)
+
+// Summarizer produces a summary, key points and action items for a transcript.
+// Every key point and action item must reference the segment it came from.
+type Summarizer interface {
+	Summarize(ctx context.Context, transcript *models.Transcript) (*models.TranscriptSummary, error)
+}
+
+// sentence is one sentence of a segment with its estimated start time
+type sentence struct {
+	text         string
+	segmentIndex int
+	startMs      int64
+}
+
+// splitTranscript breaks every segment into sentences. Start times inside a
+// segment come from word timings when available, otherwise they are
+// interpolated from the sentence's character offset.
+func splitTranscript(transcript *models.Transcript) []sentence {
+	var sentences []sentence
+	for i, segment := range transcript.Segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		offset := 0
+		wordsSeen := 0
+		for _, part := range textutil.SplitSentences(text) {
+			position := strings.Index(text[offset:], part)
+			if position >= 0 {
+				offset += position
+			}
+
+			sentences = append(sentences, sentence{
+				text:         part,
+				segmentIndex: i,
+				startMs:      sentenceStart(segment, text, offset, wordsSeen),
+			})
+
+			offset += len(part)
+			wordsSeen += len(strings.Fields(part))
+		}
+	}
+	return sentences
+}
+
+func sentenceStart(segment models.Segment, text string, offset, wordsSeen int) int64 {
+	if wordsSeen < len(segment.Words) {
+		return segment.Words[wordsSeen].StartMs
+	}
+	if len(text) == 0 || segment.EndMs <= segment.StartMs {
+		return segment.StartMs
+	}
+	return segment.StartMs + (segment.EndMs-segment.StartMs)*int64(offset)/int64(len(text))
+}
@@ -0,0 +1,109 @@
+package textutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Tokenize lowercases text and splits it into letter/digit runs, keeping apostrophes inside words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// ContentWords returns the tokens of text that are not stopwords and are longer than one character
+func ContentWords(text string) []string {
+	tokens := Tokenize(text)
+	words := tokens[:0]
+	for _, token := range tokens {
+		token = strings.Trim(token, "'")
+		if len([]rune(token)) > 1 && !IsStopword(token) {
+			words = append(words, token)
+		}
+	}
+	return words
+}
+
+// SplitSentences breaks text on sentence-ending punctuation, keeping the punctuation
+func SplitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+	runes := []rune(text)
+
+	for i, r := range runes {
+		current.WriteRune(r)
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		// Do not split decimals such as "3.5"
+		if r == '.' && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+			continue
+		}
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(current.String()); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		current.Reset()
+	}
+
+	if sentence := strings.TrimSpace(current.String()); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// FormatClock renders milliseconds as MM:SS, or H:MM:SS past the hour
+func FormatClock(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	hours := ms / 3600000
+	minutes := (ms % 3600000) / 60000
+	seconds := (ms % 60000) / 1000
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
+// IsStopword reports whether a lowercase token is a common English function word
+func IsStopword(token string) bool {
+	_, ok := stopwords[token]
+	return ok
+}
+
+var stopwords = func() map[string]struct{} {
+	words := strings.Fields(`
+		a about above after again against all also am an and any are aren't as at
+		be because been before being below between both but by
+		can can't cannot could couldn't
+		did didn't do does doesn't doing don't down during
+		each either else even ever every
+		few for from further
+		get gets getting go goes going gonna got gotta
+		had hadn't has hasn't have haven't having he he'd he'll he's her here here's hers herself him himself his how how's
+		i i'd i'll i'm i've if in into is isn't it it's its itself
+		just
+		kind know
+		let's like
+		may me might more most must mustn't my myself
+		no nor not now
+		of off oh ok okay on once one only or other ought our ours ourselves out over own
+		really right
+		said same say says see shall shan't she she'd she'll she's should shouldn't so some something such sure
+		than that that's the their theirs them themselves then there there's these they they'd they'll they're they've thing things think this those though through to too
+		um uh under until up upon us
+		very
+		want was wasn't way we we'd we'll we're we've well were weren't what what's when when's where where's whether which while who who's whom why why's will with won't would wouldn't
+		yeah yes yet you you'd you'll you're you've your yours yourself yourselves
+	`)
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set
+}()
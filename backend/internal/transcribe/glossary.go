@@ -0,0 +1,197 @@
+package transcribe
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// Glossary rewrites known misspellings of vocabulary terms, and differently-cased copies of the
+// terms themselves, to the glossary spelling
+type Glossary struct {
+	pattern *regexp.Regexp
+	// spellings maps each folded variant to the term it is corrected to
+	spellings map[string]string
+}
+
+// NewGlossary compiles the hints' terms and sounds-like variants. When two terms claim the same
+// variant the higher-boosted one wins.
+func NewGlossary(hints []Hint) *Glossary {
+	ordered := append([]Hint(nil), hints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Boost > ordered[j].Boost
+	})
+
+	spellings := make(map[string]string)
+	for _, hint := range ordered {
+		phrase := strings.Join(strings.Fields(hint.Phrase), " ")
+		if phrase == "" {
+			continue
+		}
+		for _, variant := range append([]string{phrase}, hint.SoundsLike...) {
+			key := fold(variant)
+			if _, taken := spellings[key]; key != "" && !taken {
+				spellings[key] = phrase
+			}
+		}
+	}
+	if len(spellings) == 0 {
+		return &Glossary{}
+	}
+
+	variants := make([]string, 0, len(spellings))
+	for variant := range spellings {
+		variants = append(variants, variant)
+	}
+	// Longest first, so "dev ops team" is preferred over "dev ops" where both match
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i]) != len(variants[j]) {
+			return len(variants[i]) > len(variants[j])
+		}
+		return variants[i] < variants[j]
+	})
+	alternatives := make([]string, len(variants))
+	for i, variant := range variants {
+		words := strings.Split(variant, " ")
+		for j, word := range words {
+			words[j] = regexp.QuoteMeta(word)
+		}
+		alternatives[i] = strings.Join(words, `\s+`)
+	}
+
+	return &Glossary{
+		pattern:   regexp.MustCompile(`(?i)(` + strings.Join(alternatives, "|") + `)`),
+		spellings: spellings,
+	}
+}
+
+// correction is a span of segment text and the glossary spelling that replaces it
+type correction struct {
+	start, end int
+	spelling   string
+}
+
+// Normalize corrects segments in place and returns the number of corrections made
+func (g *Glossary) Normalize(segments []models.Segment) int {
+	total := 0
+	for i := range segments {
+		total += g.Correct(&segments[i])
+	}
+	return total
+}
+
+// Correct rewrites a segment's text and word timings to the glossary spelling and returns the
+// number of corrections made. A multi-word match becomes one word spanning the words it covers.
+func (g *Glossary) Correct(segment *models.Segment) int {
+	if g.pattern == nil {
+		return 0
+	}
+
+	var corrections []correction
+	for _, loc := range g.pattern.FindAllStringIndex(segment.Text, -1) {
+		if !wordBoundary(segment.Text, loc[0], loc[1]) {
+			continue
+		}
+		matched := segment.Text[loc[0]:loc[1]]
+		spelling := g.spellings[fold(matched)]
+		if spelling == "" || matched == spelling {
+			continue
+		}
+		corrections = append(corrections, correction{start: loc[0], end: loc[1], spelling: spelling})
+	}
+	if len(corrections) == 0 {
+		return 0
+	}
+
+	segment.Words = correctWords(segment.Text, segment.Words, corrections)
+
+	var b strings.Builder
+	last := 0
+	for _, c := range corrections {
+		b.WriteString(segment.Text[last:c.start])
+		b.WriteString(c.spelling)
+		last = c.end
+	}
+	b.WriteString(segment.Text[last:])
+	segment.Text = b.String()
+
+	return len(corrections)
+}
+
+// correctWords applies corrections to the word timings, locating each word in the segment text in
+// order. Words covered by one correction are merged, keeping any punctuation around the match.
+func correctWords(text string, words []models.Word, corrections []correction) []models.Word {
+	if len(words) == 0 {
+		return words
+	}
+
+	corrected := make([]models.Word, 0, len(words))
+	cursor := 0
+	lastCorrection := -1
+	lastStart := 0
+	for _, word := range words {
+		start := strings.Index(text[cursor:], word.Text)
+		if start < 0 || word.Text == "" {
+			// The words no longer line up with the text; keep the word rather than guess
+			corrected = append(corrected, word)
+			lastCorrection = -1
+			continue
+		}
+		start += cursor
+		end := start + len(word.Text)
+		cursor = end
+
+		index := -1
+		for i, c := range corrections {
+			if start < c.end && c.start < end {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			corrected = append(corrected, word)
+			lastCorrection = -1
+			continue
+		}
+
+		c := corrections[index]
+		if index == lastCorrection {
+			merged := &corrected[len(corrected)-1]
+			merged.EndMs = word.EndMs
+			merged.Text = text[lastStart:c.start] + c.spelling + text[c.end:max(end, c.end)]
+			if word.Confidence < merged.Confidence {
+				merged.Confidence = word.Confidence
+			}
+			continue
+		}
+		word.Text = text[min(start, c.start):c.start] + c.spelling + text[c.end:max(end, c.end)]
+		corrected = append(corrected, word)
+		lastCorrection = index
+		lastStart = min(start, c.start)
+	}
+	return corrected
+}
+
+// fold lowercases a variant and collapses its whitespace, the form spellings are keyed by
+func fold(variant string) string {
+	return strings.Join(strings.Fields(strings.ToLower(variant)), " ")
+}
+
+// wordBoundary reports whether a match stands alone rather than sitting inside a longer word
+func wordBoundary(text string, start, end int) bool {
+	before := []rune(text[:start])
+	if len(before) > 0 && isWordRune(before[len(before)-1]) {
+		return false
+	}
+	for _, r := range text[end:] {
+		return !isWordRune(r)
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
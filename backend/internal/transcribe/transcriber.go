@@ -0,0 +1,85 @@
+package transcribe
+
+import (
+	"context"
+	"io"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+)
+
+// Transcriber turns audio into timed segments. Implementations should pass the request's hints
+// on to the speech engine where it supports custom vocabulary or phrase boosting.
+type Transcriber interface {
+	Transcribe(ctx context.Context, req Request) (*Result, error)
+}
+
+type Request struct {
+	UserID string
+	// FolderID is the folder the file is being transcribed into, which decides the vocabulary
+	FolderID    *string
+	Audio       io.Reader
+	ContentType string
+	// Language is empty to let the engine detect it
+	Language string
+	Hints    []Hint
+}
+
+type Result struct {
+	Language string
+	Segments []models.Segment
+	// Corrections counts the words rewritten to their glossary spelling after transcription
+	Corrections int
+}
+
+// Hint is a vocabulary term as handed to a speech engine
+type Hint struct {
+	Phrase     string
+	SoundsLike []string
+	Boost      float64
+}
+
+// HintsFor converts vocabulary terms to transcription hints
+func HintsFor(terms []models.VocabularyTerm) []Hint {
+	hints := make([]Hint, len(terms))
+	for i, term := range terms {
+		hints[i] = Hint{
+			Phrase:     term.Term,
+			SoundsLike: term.SoundsLike,
+			Boost:      term.Boost,
+		}
+	}
+	return hints
+}
+
+// VocabularySource looks up the vocabulary that applies to a folder
+type VocabularySource interface {
+	EffectiveTerms(userID string, folderID *string) ([]models.VocabularyTerm, error)
+}
+
+// vocabularyTranscriber adds the user's vocabulary to every request and enforces the glossary
+// spelling on what comes back
+type vocabularyTranscriber struct {
+	inner  Transcriber
+	source VocabularySource
+}
+
+// WithVocabulary wraps a transcriber so it receives the account and folder vocabulary as hints,
+// and its output is normalized to the glossary
+func WithVocabulary(inner Transcriber, source VocabularySource) Transcriber {
+	return &vocabularyTranscriber{inner: inner, source: source}
+}
+
+func (t *vocabularyTranscriber) Transcribe(ctx context.Context, req Request) (*Result, error) {
+	terms, err := t.source.EffectiveTerms(req.UserID, req.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	req.Hints = append(req.Hints, HintsFor(terms)...)
+
+	result, err := t.inner.Transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Corrections += NewGlossary(req.Hints).Normalize(result.Segments)
+	return result, nil
+}
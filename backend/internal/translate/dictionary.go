@@ -0,0 +1,111 @@
+package translate
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// builtinDictionaries is a small English glossary so local development produces visibly translated output
+var builtinDictionaries = map[string]map[string]string{
+	"en:es": {
+		"hello": "hola", "hi": "hola", "thanks": "gracias", "thank": "gracias", "you": "tú", "yes": "sí",
+		"no": "no", "the": "el", "a": "un", "and": "y", "or": "o", "we": "nosotros", "i": "yo",
+		"is": "es", "are": "son", "meeting": "reunión", "today": "hoy", "tomorrow": "mañana",
+		"week": "semana", "budget": "presupuesto", "project": "proyecto", "team": "equipo",
+		"good": "bueno", "morning": "mañana", "next": "siguiente", "please": "por favor",
+		"review": "revisión", "question": "pregunta", "time": "tiempo", "work": "trabajo",
+	},
+	"en:fr": {
+		"hello": "bonjour", "hi": "salut", "thanks": "merci", "thank": "merci", "you": "vous", "yes": "oui",
+		"no": "non", "the": "le", "a": "un", "and": "et", "or": "ou", "we": "nous", "i": "je",
+		"is": "est", "are": "sont", "meeting": "réunion", "today": "aujourd'hui", "tomorrow": "demain",
+		"week": "semaine", "budget": "budget", "project": "projet", "team": "équipe",
+		"good": "bon", "morning": "matin", "next": "prochain", "please": "s'il vous plaît",
+		"review": "revue", "question": "question", "time": "temps", "work": "travail",
+	},
+}
+
+// DictionaryTranslator is a deterministic, offline Translator for tests and local development.
+// It substitutes words one at a time from a per-language-pair dictionary, keeping punctuation,
+// capitalization and any word it doesn't know.
+type DictionaryTranslator struct {
+	dictionaries map[string]map[string]string
+}
+
+// NewDictionaryTranslator returns a translator seeded with the built-in glossary
+func NewDictionaryTranslator() *DictionaryTranslator {
+	t := &DictionaryTranslator{dictionaries: make(map[string]map[string]string)}
+	for pair, entries := range builtinDictionaries {
+		source, target, _ := strings.Cut(pair, ":")
+		t.AddEntries(source, target, entries)
+	}
+	return t
+}
+
+// AddEntries extends the dictionary for a language pair
+func (t *DictionaryTranslator) AddEntries(sourceLanguage, targetLanguage string, entries map[string]string) {
+	pair := BaseLanguage(sourceLanguage) + ":" + BaseLanguage(targetLanguage)
+	if t.dictionaries[pair] == nil {
+		t.dictionaries[pair] = make(map[string]string, len(entries))
+	}
+	for word, translation := range entries {
+		t.dictionaries[pair][strings.ToLower(word)] = translation
+	}
+}
+
+func (t *DictionaryTranslator) Translate(ctx context.Context, texts []string, sourceLanguage, targetLanguage string) ([]string, error) {
+	if sourceLanguage == "" {
+		sourceLanguage = "en"
+	}
+	dictionary := t.dictionaries[BaseLanguage(sourceLanguage)+":"+BaseLanguage(targetLanguage)]
+
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		translated[i] = translateWords(text, dictionary)
+	}
+	return translated, nil
+}
+
+// translateWords replaces each run of letters found in the dictionary, leaving everything else untouched
+func translateWords(text string, dictionary map[string]string) string {
+	if len(dictionary) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '\'') {
+			j++
+		}
+		word := string(runes[i:j])
+		if translation, ok := dictionary[strings.ToLower(word)]; ok {
+			word = matchCase(word, translation)
+		}
+		b.WriteString(word)
+		i = j
+	}
+	return b.String()
+}
+
+// matchCase capitalizes the translation when the original word was capitalized
+func matchCase(original, translation string) string {
+	first := []rune(original)[0]
+	if !unicode.IsUpper(first) || translation == "" {
+		return translation
+	}
+	runes := []rune(translation)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
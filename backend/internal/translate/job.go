@@ -0,0 +1,71 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mouizahmed/justscribe-backend/internal/jobs"
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+)
+
+// batchSize bounds how many segments are sent to the translator at once
+const batchSize = 50
+
+// JobHandler builds the handler for translate jobs: it translates the file's transcript
+// segment by segment and stores the result as a language variant of it
+func JobHandler(translator Translator, transcriptRepo *repository.TranscriptRepository) jobs.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		if job.FileID == nil {
+			return fmt.Errorf("translate job has no file")
+		}
+
+		var payload models.TranslatePayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid translate payload: %w", err)
+		}
+		target, err := NormalizeLanguage(payload.TargetLanguage)
+		if err != nil {
+			return err
+		}
+
+		transcript, err := transcriptRepo.GetTranscriptByFileID(*job.FileID, job.UserID)
+		if err != nil {
+			return err
+		}
+		if transcript == nil {
+			return fmt.Errorf("transcript not found")
+		}
+
+		source := ""
+		if transcript.Language != nil {
+			source = *transcript.Language
+		}
+
+		texts := make([]string, len(transcript.Segments))
+		for i, segment := range transcript.Segments {
+			texts[i] = segment.Text
+		}
+
+		translated := make([]string, 0, len(texts))
+		for start := 0; start < len(texts); start += batchSize {
+			end := start + batchSize
+			if end > len(texts) {
+				end = len(texts)
+			}
+
+			batch, err := translator.Translate(ctx, texts[start:end], source, target)
+			if err != nil {
+				return fmt.Errorf("failed to translate transcript: %w", err)
+			}
+			if len(batch) != end-start {
+				return fmt.Errorf("translator returned %d segments, expected %d", len(batch), end-start)
+			}
+			translated = append(translated, batch...)
+		}
+
+		_, err = transcriptRepo.CreateTranscriptVariant(transcript, target, translated)
+		return err
+	}
+}
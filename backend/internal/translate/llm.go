@@ -0,0 +1,59 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mouizahmed/justscribe-backend/internal/llm"
+)
+
+const translateInstructions = `You translate transcript segments for subtitles.
+You receive a JSON array of strings. Reply with a JSON array of the same length containing the translation of each string, in the same order.
+Translate each string independently, keep it roughly the same length, and do not merge, split, add or drop entries. Reply with JSON only.`
+
+// LLMTranslator delegates translation to a chat model
+type LLMTranslator struct {
+	completer llm.Completer
+}
+
+func NewLLMTranslator(completer llm.Completer) *LLMTranslator {
+	return &LLMTranslator{completer: completer}
+}
+
+func (t *LLMTranslator) Translate(ctx context.Context, texts []string, sourceLanguage, targetLanguage string) ([]string, error) {
+	if len(texts) == 0 {
+		return []string{}, nil
+	}
+
+	encoded, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+
+	source := sourceLanguage
+	if source == "" {
+		source = "the detected language"
+	}
+	reply, err := t.completer.Complete(ctx, []llm.Message{
+		{Role: llm.RoleSystem, Content: translateInstructions},
+		{Role: llm.RoleUser, Content: fmt.Sprintf("Translate from %s to %s:\n%s", source, targetLanguage, encoded)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(llm.ExtractJSON(reply)), &translated); err != nil {
+		return nil, fmt.Errorf("translator returned invalid JSON: %w", err)
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("translator returned %d segments, expected %d", len(translated), len(texts))
+	}
+
+	for i := range translated {
+		translated[i] = strings.TrimSpace(translated[i])
+	}
+	return translated, nil
+}
@@ -0,0 +1,36 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Translator translates a batch of texts, returning one translation per input in the same order.
+// An empty source language means the translator should detect it.
+type Translator interface {
+	Translate(ctx context.Context, texts []string, sourceLanguage, targetLanguage string) ([]string, error)
+}
+
+// languagePattern accepts BCP 47 style codes such as "es", "pt-BR" or "zh-Hant"
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// NormalizeLanguage validates a language code and lowercases its primary subtag
+func NormalizeLanguage(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if !languagePattern.MatchString(code) {
+		return "", fmt.Errorf("invalid language code: %q", code)
+	}
+	primary, region, found := strings.Cut(code, "-")
+	if !found {
+		return strings.ToLower(primary), nil
+	}
+	return strings.ToLower(primary) + "-" + region, nil
+}
+
+// BaseLanguage returns the primary subtag of a language code, e.g. "pt" for "pt-BR"
+func BaseLanguage(code string) string {
+	primary, _, _ := strings.Cut(strings.ToLower(code), "-")
+	return primary
+}
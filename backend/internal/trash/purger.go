@@ -0,0 +1,72 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mouizahmed/justscribe-backend/internal/models"
+	"github.com/mouizahmed/justscribe-backend/internal/repository"
+	"github.com/mouizahmed/justscribe-backend/internal/storage"
+)
+
+// plans are purged in turn, each with its own retention window
+var plans = []models.UserPlan{models.UserPlanFree, models.UserPlanProfessional, models.UserPlanBusiness}
+
+// Purger permanently deletes trashed folders and files, and their stored media, once they have
+// been in the trash longer than the owner's plan retains them
+type Purger struct {
+	trashRepo *repository.TrashRepository
+	store     storage.Storage
+	interval  time.Duration
+}
+
+func NewPurger(trashRepo *repository.TrashRepository, store storage.Storage) *Purger {
+	return &Purger{
+		trashRepo: trashRepo,
+		store:     store,
+		interval:  time.Hour,
+	}
+}
+
+// Run purges expired items every interval until the context is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired runs one purge pass over every plan
+func (p *Purger) PurgeExpired(ctx context.Context) {
+	now := time.Now()
+	for _, plan := range plans {
+		result, err := p.trashRepo.PurgeExpired(plan, now.Add(-plan.TrashRetention()))
+		if err != nil {
+			log.Printf("Error purging trash for %s plan: %v", plan, err)
+			continue
+		}
+		if result.DeletedFolders > 0 || result.DeletedFiles > 0 {
+			log.Printf("Purged %d folders and %d files from the %s plan trash", result.DeletedFolders, result.DeletedFiles, plan)
+		}
+		DeleteMedia(ctx, p.store, result.StorageKeys)
+	}
+}
+
+// DeleteMedia removes purged files' media from storage. The rows are already gone, so failures are
+// logged rather than returned.
+func DeleteMedia(ctx context.Context, store storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Error deleting media %s: %v", key, err)
+		}
+	}
+}
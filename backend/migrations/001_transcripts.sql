@@ -0,0 +1,40 @@
+-- Transcripts, their segments and the per-transcript speaker roster.
+-- Segments reference roster entries so a rename applies everywhere at once.
+
+CREATE TABLE IF NOT EXISTS transcripts (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	language TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcripts_file_id ON transcripts(file_id);
+
+CREATE TABLE IF NOT EXISTS transcript_speakers (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	label TEXT NOT NULL,
+	name TEXT,
+	color TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (transcript_id, label)
+);
+
+CREATE TABLE IF NOT EXISTS transcript_segments (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	speaker_id UUID REFERENCES transcript_speakers(id) ON DELETE SET NULL,
+	text TEXT NOT NULL,
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	confidence DOUBLE PRECISION,
+	words JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcript_segments_transcript ON transcript_segments(transcript_id, start_ms);
+CREATE INDEX IF NOT EXISTS idx_transcript_segments_speaker ON transcript_segments(speaker_id);
@@ -0,0 +1,74 @@
+-- Language-aware full-text index over transcript segments.
+-- Each segment carries the text search configuration derived from its
+-- transcript's language, and the tsvector is generated from it.
+
+CREATE OR REPLACE FUNCTION transcript_ts_config(lang TEXT) RETURNS regconfig AS $$
+	SELECT CASE lower(split_part(COALESCE(lang, ''), '-', 1))
+		WHEN 'ar' THEN 'arabic'::regconfig
+		WHEN 'da' THEN 'danish'::regconfig
+		WHEN 'de' THEN 'german'::regconfig
+		WHEN 'el' THEN 'greek'::regconfig
+		WHEN 'en' THEN 'english'::regconfig
+		WHEN 'es' THEN 'spanish'::regconfig
+		WHEN 'fi' THEN 'finnish'::regconfig
+		WHEN 'fr' THEN 'french'::regconfig
+		WHEN 'hu' THEN 'hungarian'::regconfig
+		WHEN 'id' THEN 'indonesian'::regconfig
+		WHEN 'it' THEN 'italian'::regconfig
+		WHEN 'nl' THEN 'dutch'::regconfig
+		WHEN 'no' THEN 'norwegian'::regconfig
+		WHEN 'pt' THEN 'portuguese'::regconfig
+		WHEN 'ro' THEN 'romanian'::regconfig
+		WHEN 'ru' THEN 'russian'::regconfig
+		WHEN 'sv' THEN 'swedish'::regconfig
+		WHEN 'tr' THEN 'turkish'::regconfig
+		ELSE 'simple'::regconfig
+	END
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE transcript_segments
+	ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT 'simple';
+
+ALTER TABLE transcript_segments
+	ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector(search_config, text)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_transcript_segments_search ON transcript_segments USING GIN (search_vector);
+
+-- New segments pick up their transcript's language
+CREATE OR REPLACE FUNCTION transcript_segments_set_search_config() RETURNS trigger AS $$
+BEGIN
+	SELECT transcript_ts_config(t.language) INTO NEW.search_config
+	FROM transcripts t
+	WHERE t.id = NEW.transcript_id;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transcript_segments_search_config ON transcript_segments;
+CREATE TRIGGER trg_transcript_segments_search_config
+	BEFORE INSERT ON transcript_segments
+	FOR EACH ROW EXECUTE FUNCTION transcript_segments_set_search_config();
+
+-- Changing a transcript's language re-indexes its segments
+CREATE OR REPLACE FUNCTION transcripts_propagate_search_config() RETURNS trigger AS $$
+BEGIN
+	UPDATE transcript_segments
+	SET search_config = transcript_ts_config(NEW.language)
+	WHERE transcript_id = NEW.id;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transcripts_search_config ON transcripts;
+CREATE TRIGGER trg_transcripts_search_config
+	AFTER UPDATE OF language ON transcripts
+	FOR EACH ROW
+	WHEN (OLD.language IS DISTINCT FROM NEW.language)
+	EXECUTE FUNCTION transcripts_propagate_search_config();
+
+-- Backfill existing segments
+UPDATE transcript_segments s
+SET search_config = transcript_ts_config(t.language)
+FROM transcripts t
+WHERE t.id = s.transcript_id;
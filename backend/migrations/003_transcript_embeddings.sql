@@ -0,0 +1,23 @@
+-- Speaker-turn chunks of each transcript with their embeddings (pgvector).
+-- The vector width must match the configured embedder's dimensions.
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS transcript_chunks (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	chunk_index INTEGER NOT NULL,
+	speaker_id UUID REFERENCES transcript_speakers(id) ON DELETE SET NULL,
+	segment_ids UUID[] NOT NULL,
+	text TEXT NOT NULL,
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	confidence DOUBLE PRECISION,
+	embedding vector(1536) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (transcript_id, chunk_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcript_chunks_transcript ON transcript_chunks(transcript_id);
+CREATE INDEX IF NOT EXISTS idx_transcript_chunks_embedding ON transcript_chunks
+	USING hnsw (embedding vector_cosine_ops);
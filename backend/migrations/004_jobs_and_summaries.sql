@@ -0,0 +1,31 @@
+-- Background jobs, claimed by workers with FOR UPDATE SKIP LOCKED.
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id TEXT NOT NULL REFERENCES users(id),
+	file_id UUID REFERENCES files(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'queued',
+	payload JSONB,
+	error TEXT,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	started_at TIMESTAMPTZ,
+	finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs(type, created_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_jobs_file ON jobs(file_id, type, created_at DESC);
+
+-- Meeting summary, key points and action items, one row per transcript.
+
+CREATE TABLE IF NOT EXISTS transcript_summaries (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL UNIQUE REFERENCES transcripts(id) ON DELETE CASCADE,
+	summary TEXT NOT NULL,
+	key_points JSONB NOT NULL DEFAULT '[]',
+	action_items JSONB NOT NULL DEFAULT '[]',
+	generator TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
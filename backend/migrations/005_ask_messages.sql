@@ -0,0 +1,13 @@
+-- Per-file conversation history for retrieval-augmented Q&A.
+
+CREATE TABLE IF NOT EXISTS ask_messages (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	role TEXT NOT NULL,
+	content TEXT NOT NULL,
+	citations JSONB NOT NULL DEFAULT '[]',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ask_messages_file ON ask_messages(file_id, user_id, created_at);
@@ -0,0 +1,15 @@
+-- Translations are stored as language variants of the original transcript.
+-- Each translated segment points at the segment it was translated from, so
+-- the two can be exported side by side as bilingual subtitles.
+
+ALTER TABLE transcripts
+	ADD COLUMN IF NOT EXISTS source_transcript_id UUID REFERENCES transcripts(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transcripts_variant_language
+	ON transcripts(source_transcript_id, lower(language))
+	WHERE source_transcript_id IS NOT NULL;
+
+ALTER TABLE transcript_segments
+	ADD COLUMN IF NOT EXISTS source_segment_id UUID REFERENCES transcript_segments(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transcript_segments_source ON transcript_segments(source_segment_id);
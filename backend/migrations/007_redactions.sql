@@ -0,0 +1,37 @@
+-- PII redaction. Redacted segments keep their original text and word timings
+-- encrypted at rest so the owner can reveal or restore them; every apply,
+-- reveal and restore is recorded in the audit log.
+
+CREATE TABLE IF NOT EXISTS redaction_terms (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id TEXT NOT NULL REFERENCES users(id),
+	term TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_redaction_terms_user_term ON redaction_terms(user_id, lower(term));
+
+CREATE TABLE IF NOT EXISTS segment_redactions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	segment_id UUID NOT NULL UNIQUE REFERENCES transcript_segments(id) ON DELETE CASCADE,
+	entities JSONB NOT NULL DEFAULT '[]',
+	original_ciphertext BYTEA NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_redactions_transcript ON segment_redactions(transcript_id);
+
+CREATE TABLE IF NOT EXISTS redaction_audit_log (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	action TEXT NOT NULL,
+	segment_count INTEGER NOT NULL DEFAULT 0,
+	entity_counts JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_redaction_audit_transcript ON redaction_audit_log(transcript_id, created_at DESC);
+
+ALTER TABLE transcripts ADD COLUMN IF NOT EXISTS redacted_at TIMESTAMPTZ;
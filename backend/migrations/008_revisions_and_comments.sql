@@ -0,0 +1,73 @@
+-- Segment revisions keep the previous text and timing every time a segment is
+-- edited or retimed.
+
+CREATE TABLE IF NOT EXISTS segment_revisions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	segment_id UUID NOT NULL REFERENCES transcript_segments(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	text TEXT NOT NULL,
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	words JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_revisions_segment ON segment_revisions(segment_id, created_at DESC);
+
+-- Threaded comments anchored to a time range. Root comments are anchored to the
+-- segment the range starts in; replies inherit their thread's anchor.
+
+CREATE TABLE IF NOT EXISTS transcript_comments (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	parent_id UUID REFERENCES transcript_comments(id) ON DELETE CASCADE,
+	segment_id UUID REFERENCES transcript_segments(id) ON DELETE SET NULL,
+	start_ms BIGINT,
+	end_ms BIGINT,
+	body TEXT NOT NULL,
+	resolved_at TIMESTAMPTZ,
+	resolved_by TEXT REFERENCES users(id),
+	edited_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcript_comments_transcript ON transcript_comments(transcript_id, start_ms);
+CREATE INDEX IF NOT EXISTS idx_transcript_comments_parent ON transcript_comments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_transcript_comments_segment ON transcript_comments(segment_id);
+
+CREATE TABLE IF NOT EXISTS comment_mentions (
+	comment_id UUID NOT NULL REFERENCES transcript_comments(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user ON comment_mentions(user_id, created_at DESC);
+
+-- Keep comment anchors on their segment when it is retimed: an anchor edge that
+-- sat on a segment boundary follows that boundary, anything else keeps its
+-- offset from the segment start.
+CREATE OR REPLACE FUNCTION shift_comment_anchors() RETURNS trigger AS $$
+BEGIN
+	IF NEW.start_ms IS DISTINCT FROM OLD.start_ms OR NEW.end_ms IS DISTINCT FROM OLD.end_ms THEN
+		UPDATE transcript_comments
+		SET start_ms = CASE WHEN start_ms = OLD.start_ms THEN NEW.start_ms
+				ELSE GREATEST(start_ms + (NEW.start_ms - OLD.start_ms), 0) END,
+			end_ms = CASE WHEN end_ms = OLD.end_ms THEN NEW.end_ms
+				ELSE GREATEST(end_ms + (NEW.start_ms - OLD.start_ms), 0) END
+		WHERE segment_id = NEW.id;
+
+		UPDATE transcript_comments
+		SET end_ms = start_ms
+		WHERE segment_id = NEW.id AND end_ms < start_ms;
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS transcript_segments_shift_comments ON transcript_segments;
+CREATE TRIGGER transcript_segments_shift_comments
+	AFTER UPDATE OF start_ms, end_ms ON transcript_segments
+	FOR EACH ROW EXECUTE FUNCTION shift_comment_anchors();
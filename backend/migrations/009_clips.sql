@@ -0,0 +1,19 @@
+-- Where each file's media lives in object storage, and highlight clips cut
+-- from a file's timeline.
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS storage_key TEXT;
+
+CREATE TABLE IF NOT EXISTS clips (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	title TEXT NOT NULL,
+	note TEXT,
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CHECK (start_ms >= 0 AND end_ms > start_ms)
+);
+
+CREATE INDEX IF NOT EXISTS idx_clips_file ON clips(file_id, start_ms);
@@ -0,0 +1,19 @@
+-- Reviewer decisions on low-confidence words and segments, and sign-off once
+-- a file's review queue has been worked through.
+
+CREATE TABLE IF NOT EXISTS review_decisions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	segment_id UUID NOT NULL REFERENCES transcript_segments(id) ON DELETE CASCADE,
+	-- NULL when the decision covers the whole segment
+	word_index INT,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	action TEXT NOT NULL CHECK (action IN ('accept', 'correct')),
+	original_text TEXT NOT NULL,
+	corrected_text TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_decisions_item ON review_decisions(segment_id, (COALESCE(word_index, -1)));
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS reviewed_by TEXT REFERENCES users(id);
@@ -0,0 +1,15 @@
+-- Chapters found by topic segmentation. Each transcript keeps one set of
+-- chapters, replaced whenever chaptering is run again.
+
+CREATE TABLE IF NOT EXISTS transcript_chapters (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	transcript_id UUID NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+	chapter_index INT NOT NULL,
+	title TEXT NOT NULL,
+	start_ms BIGINT NOT NULL,
+	end_ms BIGINT NOT NULL,
+	start_segment_id UUID REFERENCES transcript_segments(id) ON DELETE SET NULL,
+	generator TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (transcript_id, chapter_index)
+);
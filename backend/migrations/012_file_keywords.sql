@@ -0,0 +1,14 @@
+-- Key phrases extracted from each file's transcript. Keywords a user confirms
+-- are stored in files.tags instead, so they survive re-extraction.
+
+CREATE TABLE IF NOT EXISTS file_keywords (
+	file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+	user_id TEXT NOT NULL REFERENCES users(id),
+	keyword TEXT NOT NULL,
+	score DOUBLE PRECISION NOT NULL,
+	occurrences INT NOT NULL,
+	extracted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (file_id, keyword)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_keywords_user ON file_keywords(user_id, keyword);
@@ -0,0 +1,16 @@
+-- Custom vocabulary. Terms live at account level (folder_id NULL) or on a
+-- folder, where they apply to everything below it. Sounds-like hints double as
+-- the known misspellings normalized to the term after transcription.
+
+CREATE TABLE IF NOT EXISTS vocabulary_terms (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id TEXT NOT NULL REFERENCES users(id),
+	folder_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+	term TEXT NOT NULL,
+	sounds_like TEXT[] NOT NULL DEFAULT '{}',
+	boost REAL NOT NULL DEFAULT 1 CHECK (boost >= 0 AND boost <= 10),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vocabulary_terms_scope ON vocabulary_terms(user_id, (COALESCE(folder_id::text, '')), lower(term));
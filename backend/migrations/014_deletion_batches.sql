@@ -0,0 +1,8 @@
+-- Deleting a folder soft-deletes its whole subtree. Every folder and file deleted
+-- together shares a deletion batch ID so the batch can be restored as one.
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS deletion_batch_id UUID;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS deletion_batch_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_folders_deletion_batch ON folders(deletion_batch_id) WHERE deletion_batch_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_files_deletion_batch ON files(deletion_batch_id) WHERE deletion_batch_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Trash listing and the purger look items up by deletion time.
+
+CREATE INDEX IF NOT EXISTS idx_folders_trash ON folders(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_files_trash ON files(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Folder listings are paginated with keyset cursors and can be sorted by
+-- duration. Durations are backfilled from the end of each file's transcript.
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS duration_ms BIGINT;
+
+UPDATE files f
+SET duration_ms = d.duration_ms
+FROM (
+	SELECT t.file_id, MAX(s.end_ms) AS duration_ms
+	FROM transcripts t
+	INNER JOIN transcript_segments s ON s.transcript_id = t.id
+	WHERE t.source_transcript_id IS NULL
+	GROUP BY t.file_id
+) d
+WHERE d.file_id = f.id AND f.duration_ms IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_folders_listing ON folders(user_id, parent_id, lower(name), id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_files_listing ON files(user_id, folder_id, lower(name), id) WHERE deleted_at IS NULL;
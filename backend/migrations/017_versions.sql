@@ -0,0 +1,24 @@
+-- Row versions for optimistic concurrency (ETag / If-Match); every change to a row bumps it
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_row_version() RETURNS trigger AS $$
+BEGIN
+	NEW.version := OLD.version + 1;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_folders_version ON folders;
+CREATE TRIGGER trg_folders_version
+	BEFORE UPDATE ON folders
+	FOR EACH ROW
+	WHEN (OLD.* IS DISTINCT FROM NEW.*)
+	EXECUTE FUNCTION bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_files_version ON files;
+CREATE TRIGGER trg_files_version
+	BEFORE UPDATE ON files
+	FOR EACH ROW
+	WHEN (OLD.* IS DISTINCT FROM NEW.*)
+	EXECUTE FUNCTION bump_row_version();
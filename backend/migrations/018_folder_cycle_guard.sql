@@ -0,0 +1,31 @@
+-- Reject parent changes that would make a folder its own ancestor. The trigger takes the same
+-- per-user advisory lock as the application's folder changes, so concurrent moves are checked one
+-- at a time and each sees the other's committed result.
+CREATE OR REPLACE FUNCTION folders_prevent_cycle() RETURNS trigger AS $$
+BEGIN
+	PERFORM pg_advisory_xact_lock(hashtext('folders:' || NEW.user_id));
+
+	IF EXISTS (
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM folders WHERE id = NEW.parent_id
+			UNION
+			SELECT f.id, f.parent_id
+			FROM folders f
+			INNER JOIN ancestors a ON f.id = a.parent_id
+		)
+		SELECT 1 FROM ancestors WHERE id = NEW.id
+	) THEN
+		RAISE EXCEPTION 'folder cycle: folder % cannot be moved into its own subtree', NEW.id
+			USING ERRCODE = 'check_violation';
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_folders_prevent_cycle ON folders;
+CREATE TRIGGER trg_folders_prevent_cycle
+	BEFORE UPDATE OF parent_id ON folders
+	FOR EACH ROW
+	WHEN (NEW.parent_id IS NOT NULL AND NEW.parent_id IS DISTINCT FROM OLD.parent_id)
+	EXECUTE FUNCTION folders_prevent_cycle();
@@ -0,0 +1,23 @@
+-- Folder appearance (color, icon, description), pinning and per-user stars on
+-- folders and files for the sidebar's quick access list.
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS color TEXT CHECK (color ~ '^#[0-9a-f]{6}$');
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS icon TEXT;
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_folders_pinned ON folders(user_id) WHERE pinned AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS folder_stars (
+	user_id TEXT NOT NULL REFERENCES users(id),
+	folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, folder_id)
+);
+
+CREATE TABLE IF NOT EXISTS file_stars (
+	user_id TEXT NOT NULL REFERENCES users(id),
+	file_id UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, file_id)
+);
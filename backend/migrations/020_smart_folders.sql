@@ -0,0 +1,16 @@
+-- Smart folders: saved searches shown in the folder tree under parent_id (the
+-- root when NULL). Filters are evaluated when the folder is listed.
+
+CREATE TABLE IF NOT EXISTS smart_folders (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id TEXT NOT NULL REFERENCES users(id),
+	parent_id UUID REFERENCES folders(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	color TEXT CHECK (color ~ '^#[0-9a-f]{6}$'),
+	icon TEXT,
+	filters JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_smart_folders_user ON smart_folders(user_id, parent_id);
@@ -0,0 +1,44 @@
+-- Drop a transcript's embedding chunks as soon as its segments change, so
+-- semantic search and ask never cite text that no longer exists. Transcripts
+-- that were indexed get an embed job queued to rebuild them.
+
+CREATE INDEX IF NOT EXISTS idx_jobs_file_type_status ON jobs(file_id, type, status);
+
+CREATE OR REPLACE FUNCTION transcript_segments_invalidate_chunks() RETURNS trigger AS $$
+DECLARE
+	target UUID;
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		target := OLD.transcript_id;
+	ELSE
+		target := NEW.transcript_id;
+	END IF;
+
+	DELETE FROM transcript_chunks WHERE transcript_id = target;
+
+	-- Rebuild when chunks were dropped, or when a rebuild already running
+	-- may have read the segments before this change
+	IF FOUND OR EXISTS (
+		SELECT 1
+		FROM jobs j
+		INNER JOIN transcripts t ON t.file_id = j.file_id
+		WHERE t.id = target AND j.type = 'embed' AND j.status = 'running'
+	) THEN
+		INSERT INTO jobs (user_id, file_id, type, status, created_at)
+		SELECT t.user_id, t.file_id, 'embed', 'queued', NOW()
+		FROM transcripts t
+		WHERE t.id = target
+			AND NOT EXISTS (
+				SELECT 1 FROM jobs j
+				WHERE j.file_id = t.file_id AND j.type = 'embed' AND j.status = 'queued'
+			);
+	END IF;
+
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transcript_segments_invalidate_chunks ON transcript_segments;
+CREATE TRIGGER trg_transcript_segments_invalidate_chunks
+	AFTER INSERT OR DELETE OR UPDATE OF transcript_id, speaker_id, text, start_ms, end_ms, confidence ON transcript_segments
+	FOR EACH ROW EXECUTE FUNCTION transcript_segments_invalidate_chunks();
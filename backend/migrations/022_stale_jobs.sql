@@ -0,0 +1,4 @@
+-- Running jobs are scanned for ones abandoned by a crashed or restarted
+-- worker, which are claimed again or failed after too many attempts.
+
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs(type, started_at) WHERE status = 'running';
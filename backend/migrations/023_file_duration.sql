@@ -0,0 +1,75 @@
+-- Keep files.duration_ms (the end of the file's transcript) in step with its
+-- segments: uploads, edits, merges, splits and deletes all go through these
+-- triggers. Translations (source_transcript_id set) do not count.
+
+CREATE OR REPLACE FUNCTION refresh_file_durations(file_ids UUID[]) RETURNS void AS $$
+	UPDATE files f
+	SET duration_ms = d.duration_ms
+	FROM (
+		SELECT id AS file_id, (
+			SELECT MAX(s.end_ms)
+			FROM transcripts t
+			INNER JOIN transcript_segments s ON s.transcript_id = t.id
+			WHERE t.file_id = ids.id AND t.source_transcript_id IS NULL
+		) AS duration_ms
+		FROM unnest(file_ids) AS ids(id)
+	) d
+	WHERE f.id = d.file_id AND f.duration_ms IS DISTINCT FROM d.duration_ms;
+$$ LANGUAGE sql;
+
+-- One refresh per statement, so inserting a transcript's segments in bulk
+-- recomputes each file once
+CREATE OR REPLACE FUNCTION transcript_segments_refresh_duration() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		PERFORM refresh_file_durations(ARRAY(
+			SELECT DISTINCT t.file_id FROM transcripts t WHERE t.id IN (SELECT transcript_id FROM new_segments)
+		));
+	ELSIF TG_OP = 'DELETE' THEN
+		PERFORM refresh_file_durations(ARRAY(
+			SELECT DISTINCT t.file_id FROM transcripts t WHERE t.id IN (SELECT transcript_id FROM old_segments)
+		));
+	ELSE
+		PERFORM refresh_file_durations(ARRAY(
+			SELECT DISTINCT t.file_id FROM transcripts t
+			WHERE t.id IN (SELECT transcript_id FROM new_segments UNION SELECT transcript_id FROM old_segments)
+		));
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transcript_segments_duration_insert ON transcript_segments;
+CREATE TRIGGER trg_transcript_segments_duration_insert
+	AFTER INSERT ON transcript_segments
+	REFERENCING NEW TABLE AS new_segments
+	FOR EACH STATEMENT EXECUTE FUNCTION transcript_segments_refresh_duration();
+
+DROP TRIGGER IF EXISTS trg_transcript_segments_duration_update ON transcript_segments;
+CREATE TRIGGER trg_transcript_segments_duration_update
+	AFTER UPDATE ON transcript_segments
+	REFERENCING OLD TABLE AS old_segments NEW TABLE AS new_segments
+	FOR EACH STATEMENT EXECUTE FUNCTION transcript_segments_refresh_duration();
+
+DROP TRIGGER IF EXISTS trg_transcript_segments_duration_delete ON transcript_segments;
+CREATE TRIGGER trg_transcript_segments_duration_delete
+	AFTER DELETE ON transcript_segments
+	REFERENCING OLD TABLE AS old_segments
+	FOR EACH STATEMENT EXECUTE FUNCTION transcript_segments_refresh_duration();
+
+-- A deleted transcript's segments are gone by the time their triggers can
+-- look up its file, so the transcript refreshes the file itself
+CREATE OR REPLACE FUNCTION transcripts_refresh_duration() RETURNS trigger AS $$
+BEGIN
+	PERFORM refresh_file_durations(ARRAY[OLD.file_id]);
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transcripts_duration ON transcripts;
+CREATE TRIGGER trg_transcripts_duration
+	AFTER DELETE ON transcripts
+	FOR EACH ROW EXECUTE FUNCTION transcripts_refresh_duration();
+
+-- Correct durations that went stale before the triggers existed
+SELECT refresh_file_durations(ARRAY(SELECT DISTINCT file_id FROM transcripts));